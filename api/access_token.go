@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adinovcina/golang-setup/store"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+)
+
+// PersonalAccessTokenPrefix tells personal access tokens apart from session tokens in Authorization header.
+const PersonalAccessTokenPrefix = "pat_"
+
+// maxAccessTokenNameLength is the length of the name column.
+const maxAccessTokenNameLength = 100
+
+// NewPersonalAccessToken generates new random personal access token.
+func NewPersonalAccessToken() string {
+	return PersonalAccessTokenPrefix + NewDoubleUUIDCode()
+}
+
+// IsPersonalAccessToken checks if bearer token is personal access token.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// PersonalAccessTokenRequest used when user creates personal access token. Scopes are permissions
+// granted to the token, limited to the ones of the role user is authorized with.
+type PersonalAccessTokenRequest struct {
+	ExpiresAt *time.Time `json:"expiresAt"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// Validate PersonalAccessTokenRequest.
+func (patr *PersonalAccessTokenRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(patr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		name := strings.TrimSpace(patr.Name)
+		if name == "" {
+			response.Error(status.ErrorMissingName)
+		} else if len(name) > maxAccessTokenNameLength {
+			response.Error(status.ErrorNameTooLong)
+		}
+
+		if patr.ExpiresAt == nil || !patr.ExpiresAt.After(time.Now()) {
+			response.Error(status.ErrorInvalidExpiration)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// PersonalAccessTokenDataResponse is returned once personal access token is created. Token is
+// returned only once, only its hash is stored.
+type PersonalAccessTokenDataResponse struct {
+	*store.PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// PersonalAccessTokensDataResponse contains personal access tokens of logged user.
+type PersonalAccessTokensDataResponse struct {
+	Tokens []*store.PersonalAccessToken `json:"tokens"`
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 	"strings"
+	"unicode/utf8"
 
 	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
 	"github.com/twinj/uuid"
@@ -20,6 +21,10 @@ func (car *CreateAccountRequest) Validate(r *http.Request) (bool, *BaseResponse)
 		response := new(BaseResponse)
 
 		// Validate body params
+		if strings.TrimSpace(car.Name) == "" {
+			response.Error(status.ErrorMissingName)
+		}
+
 		if strings.TrimSpace(car.Email) == "" {
 			response.Error(status.ErrorMissingEmail)
 		}
@@ -32,6 +37,39 @@ func (car *CreateAccountRequest) Validate(r *http.Request) (bool, *BaseResponse)
 	})
 }
 
+// RegisterRequest used when user signs up on their own.
+type RegisterRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Validate RegisterRequest.
+func (rr *RegisterRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(rr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if strings.TrimSpace(rr.Name) == "" {
+			response.Error(status.ErrorMissingName)
+		}
+
+		if strings.TrimSpace(rr.Email) == "" {
+			response.Error(status.ErrorMissingEmail)
+		}
+
+		if !validateEmail(rr.Email) {
+			response.Error(status.ErrorEmailNotInCorrectFormat)
+		}
+
+		if strings.TrimSpace(rr.Password) == "" {
+			response.Error(status.ErrorMissingPassword)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
 // ForgotPasswordRequest used when user wants to reset password.
 type ForgotPasswordRequest struct {
 	Email string `json:"email"`
@@ -55,6 +93,48 @@ func (fpr *ForgotPasswordRequest) Validate(r *http.Request) (bool, *BaseResponse
 	})
 }
 
+// VerifyEmailRequest contains token from the email verification link.
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// Validate VerifyEmailRequest.
+func (ver *VerifyEmailRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(ver, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if strings.TrimSpace(ver.Token) == "" {
+			response.Error(status.ErrorMissingToken)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// ResendVerificationEmailRequest used when user did not receive the email verification link.
+type ResendVerificationEmailRequest struct {
+	Email string `json:"email"`
+}
+
+// Validate ResendVerificationEmailRequest.
+func (rver *ResendVerificationEmailRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(rver, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if strings.TrimSpace(rver.Email) == "" {
+			response.Error(status.ErrorMissingEmail)
+		}
+
+		if !validateEmail(rver.Email) {
+			response.Error(status.ErrorEmailNotInCorrectFormat)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
 // SetPasswordRequest used when user want to set a new password.
 type SetPasswordRequest struct {
 	Password string `json:"password"`
@@ -79,8 +159,22 @@ func (spr *SetPasswordRequest) Validate(r *http.Request) (bool, *BaseResponse) {
 	})
 }
 
+// UserActivateDataResponse is returned after the active status of the user is set.
+type UserActivateDataResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Active bool      `json:"active"`
+	// Changed is false when the user already had the requested status
+	Changed bool `json:"changed"`
+}
+
+// maxReasonLength is the length of the reason column.
+const maxReasonLength = 500
+
 // UserActivateRequest contains id of user that needs to be deactivated / activated.
 type UserActivateRequest struct {
+	Active *bool `json:"active"`
+	// Reason is logged along with the change and sent to the user
+	Reason string    `json:"reason"`
 	UserID uuid.UUID `json:"userID"`
 }
 
@@ -94,14 +188,43 @@ func (uar *UserActivateRequest) Validate(r *http.Request) (bool, *BaseResponse)
 			response.Error(status.ErrorMissingUserID)
 		}
 
+		if uar.Active == nil {
+			response.Error(status.ErrorMissingActive)
+		}
+
+		if utf8.RuneCountInString(uar.Reason) > maxReasonLength {
+			response.Error(status.ErrorReasonTooLong)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// SwitchRoleRequest contains id of the role user wants to be authorized with.
+type SwitchRoleRequest struct {
+	RoleID int64 `json:"roleID"`
+}
+
+// Validate SwitchRoleRequest.
+func (srr *SwitchRoleRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(srr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if srr.RoleID <= 0 {
+			response.Error(status.ErrorMissingRoleID)
+		}
+
 		return response.HasErrors(), response
 	})
 }
 
-// ChangePasswordRequest contains new and old password for user to change.
+// ChangePasswordRequest contains new and old password for user to change. All other user
+// sessions are revoked, current session is revoked as well if KeepCurrentSession is set to false.
 type ChangePasswordRequest struct {
-	CurrentPassword string `json:"currentPassword"`
-	NewPassword     string `json:"newPassword"`
+	KeepCurrentSession *bool  `json:"keepCurrentSession,omitempty"`
+	CurrentPassword    string `json:"currentPassword"`
+	NewPassword        string `json:"newPassword"`
 }
 
 // Validate ChangePasswordRequest.
@@ -1,21 +1,23 @@
 package account
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/api/audit"
 	m "github.com/adinovcina/golang-setup/api/middleware"
 	"github.com/adinovcina/golang-setup/config"
 	"github.com/adinovcina/golang-setup/store"
 	"github.com/adinovcina/golang-setup/tools/encryption"
+	"github.com/adinovcina/golang-setup/tools/lockout"
 	"github.com/adinovcina/golang-setup/tools/logger"
 	mailjet "github.com/adinovcina/golang-setup/tools/mailjet"
 	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
-	"github.com/adinovcina/golang-setup/tools/utils"
+	"github.com/adinovcina/golang-setup/tools/password"
+	"github.com/adinovcina/golang-setup/tools/signing"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -25,44 +27,130 @@ func AttachAccountRoutes(r chi.Router,
 	repo store.Repository,
 	inMemRepo store.InMemRepository,
 	mailjetClient *mailjet.Client,
+	passwordPolicy *password.Policy,
+	passwordHasher encryption.Hasher,
+	lockoutPolicy *lockout.Policy,
+	keyring *signing.Keyring,
 ) {
-	svc := newService(conf, repo, inMemRepo, mailjetClient)
+	svc := newService(conf, repo, inMemRepo, mailjetClient, passwordPolicy, passwordHasher, lockoutPolicy, keyring)
+	permissions := store.GetPermissions()
+	rateLimit := m.RateLimit(inMemRepo, conf.RateLimit, false)
+	// Recovery routes send emails, so they are limited per email address as well
+	recoveryRateLimit := m.RateLimit(inMemRepo, conf.RateLimit, true)
 
 	// Unprotected REST routes for "account" resource
 	r.Route("/account", func(r chi.Router) {
 		// Authenticate user using email and password
-		r.Post("/authenticate", svc.handleAuthenticateUser)
+		r.With(rateLimit).Post("/authenticate", svc.handleAuthenticateUser)
 		// Used by users to authorize their selected user role
-		r.Post("/authorize", svc.handleAuthorizeUser)
+		r.With(rateLimit).Post("/authorize", svc.handleAuthorizeUser)
 		// Used by user to extend his session once it's expired
-		r.Post("/refresh-token", svc.handleRefreshToken)
+		r.With(rateLimit).Post("/refresh-token", svc.handleRefreshToken)
+		// Used by user to sign up, unless accounts are only created by admin
+		if conf.Account.RegistrationEnabled {
+			r.With(rateLimit).Post("/register", svc.handleRegister)
+		}
 		// Used by user to reset their forgotten password
-		r.Post("/forgot-password", svc.handleForgotPassword)
+		r.With(recoveryRateLimit).Post("/forgot-password", svc.handleForgotPassword)
 		// Used by user to set his new password once he receive reset link on email
-		r.Post("/set-password", svc.handleSetPassword)
+		r.With(rateLimit).Post("/set-password", svc.handleSetPassword)
+		// Used by user to verify their email address once they receive verification link on email
+		r.With(rateLimit).Post("/verify-email", svc.handleVerifyEmail)
+		// Used by user to receive a new verification link
+		r.With(recoveryRateLimit).Post("/verify-email/resend", svc.handleResendVerificationEmail)
+		// Used to fetch the current terms of service
+		r.Get("/terms", svc.handleGetTerms)
 
 		r.Group(func(r chi.Router) {
-			// Private API group
-			r.Use(m.AuthorizeRequest(&conf.Redis, inMemRepo))
+			// Private API group. Routes managing the account itself require user's own session, so they
+			// can not be used with personal access token or while admin impersonates the user
+			r.Use(m.AuthorizeRequest(keyring, inMemRepo, repo))
 
 			// Used by logged in user to fetch his user roles
 			r.Get("/roles", svc.handleGetRoles)
+			// Used by logged in user to continue the session with another of his roles
+			r.With(m.RequireOwnSession).Post("/switch-role", svc.handleSwitchRole)
 			// Used by user to change their password
-			r.Post("/change-password", svc.handleChangePassword)
+			r.With(m.RequireOwnSession).Post("/change-password", svc.handleChangePassword)
 			// Used by user to update their profile
-			r.Patch("/users/profile", svc.handleUpdateUserProfile)
+			r.With(m.RequireOwnSession).Patch("/users/profile", svc.handleUpdateUserProfile)
 			// Used to logout user from platform
-			r.Post("/logout", svc.handleLogout)
+			r.With(m.RequireOwnSession).Post("/logout", svc.handleLogout)
 			// Used to fetch user profile
 			r.Get("/me", svc.handleGetProfile)
+			// Used to manage active sessions of logged user
+			r.With(m.RequireOwnSession).Route("/sessions", func(r chi.Router) {
+				// Used to list devices user is logged in from
+				r.Get("/", svc.handleGetSessions)
+				// Used to log out everywhere except from the current session
+				r.Delete("/", svc.handleRevokeSessions)
+				// Used to log out of a single session
+				r.Delete("/{sessionID}", svc.handleRevokeSession)
+			})
+			// Used to check if logged user accepted the current terms of service
+			r.Get("/terms/acceptance", svc.handleGetTermsAcceptance)
+			// Used by logged user to accept the current terms of service
+			r.With(m.RequireOwnSession).Post("/terms/accept", svc.handleAcceptTerms)
+			// Used to manage personal access tokens which scripts use instead of the password
+			r.With(m.RequireOwnSession).Route("/tokens", func(r chi.Router) {
+				// Used to list personal access tokens of logged user
+				r.Get("/", svc.handleGetAccessTokens)
+				// Used to create personal access token, token is returned only once
+				r.Post("/", svc.handleCreateAccessToken)
+				// Used to revoke personal access token
+				r.Delete("/{id}", svc.handleDeleteAccessToken)
+			})
+			// Used by admin to end impersonation and return to the session it was started from
+			r.Post("/impersonate/end", svc.handleEndImpersonation)
 
+			// Routes below require the current terms of service to be accepted
 			r.Group(func(r chi.Router) {
-				// Restrict only to admin role
-				r.Use(m.CheckAllowedRoles(store.GetRoles().Admin))
+				r.Use(m.RequireTermsAccepted(repo))
+
+				// Used to manage second factor of logged user
+				r.With(m.RequireOwnSession).Route("/mfa", func(r chi.Router) {
+					// Used to generate a new secret and provisioning URI for authenticator app
+					r.Post("/enroll", svc.handleEnrollMFA)
+					// Used to enable MFA with the code from authenticator app
+					r.Post("/confirm", svc.handleConfirmMFA)
+					// Used to generate a new set of recovery codes
+					r.Post("/recovery-codes", svc.handleRegenerateRecoveryCodes)
+					// Used to disable MFA
+					r.Post("/disable", svc.handleDisableMFA)
+				})
+
+				// Restricted to users whose role grants the required permission
 				// Used by admin user to activate or deactivate another user
-				r.Post("/activate", svc.handleActivateUser)
+				r.With(m.RequirePermission(permissions.UsersActivate)).Post("/activate", svc.handleActivateUser)
 				// Used by admin to retrieve list of all users in the system
-				r.With(m.PaginationCursor(repo)).Get("/users", svc.handleGetUsers)
+				r.With(m.RequirePermission(permissions.UsersRead), m.PaginationCursor(repo)).Get("/users", svc.handleGetUsers)
+				// Used by admin to create a new account and invite the user over email, or invite again
+				// the user who never set the password
+				r.With(m.RequirePermission(permissions.UsersCreate)).Post("/users", svc.handleCreateAccount)
+				// Used by admin to fetch details and roles of the user
+				r.With(m.RequirePermission(permissions.UsersRead)).Get("/users/{id}", svc.handleGetUser)
+				// Used by admin to edit name, email, phone and language of the user
+				r.With(m.RequirePermission(permissions.UsersUpdate)).Patch("/users/{id}", svc.handleUpdateUser)
+				// Used by admin to replace roles assigned to the user
+				r.With(m.RequirePermission(permissions.RolesManage)).Put("/users/{id}/roles", svc.handleSetUserRoles)
+				// Used by admin to let the user suspended after too many failed logins log in again
+				r.With(m.RequirePermission(permissions.UsersUpdate)).Post("/users/{id}/unlock", svc.handleUnlockUser)
+				// Used by admin to delete the user, deleted user can be restored
+				r.With(m.RequirePermission(permissions.UsersDelete)).Delete("/users/{id}", svc.handleDeleteUser)
+				// Used by admin to restore the deleted user
+				r.With(m.RequirePermission(permissions.UsersDelete)).Post("/users/{id}/restore", svc.handleRestoreUser)
+				// Used by admin to list active sessions of the user
+				r.With(m.RequirePermission(permissions.UsersRead)).Get("/users/{id}/sessions", svc.handleGetUserSessions)
+				// Used by admin to log the user out everywhere
+				r.With(m.RequirePermission(permissions.UsersSessions)).Delete("/users/{id}/sessions", svc.handleRevokeUserSessions)
+				// Used by admin to act as another user for a short time, e.g. to reproduce the reported issue
+				r.With(m.RequirePermission(permissions.UsersImpersonate), m.RequireOwnSession).
+					Post("/impersonate", svc.handleImpersonate)
+				// Used by admin to log the user out of a single session
+				r.With(m.RequirePermission(permissions.UsersSessions)).
+					Delete("/users/{id}/sessions/{sessionID}", svc.handleRevokeUserSession)
+				// Used by admin to publish a new version of terms of service
+				r.With(m.RequirePermission(permissions.TermsManage)).Post("/terms", svc.handleCreateTerms)
 			})
 		})
 	})
@@ -85,31 +173,43 @@ func (s *service) handleAuthenticateUser(w http.ResponseWriter, r *http.Request)
 
 	// Retrieve user from the database by email and check if exists
 	user, err := s.repo.GetUserByEmail(request.Email)
-
-	// If user is not found then tell user that email or password is incorrect
-	if err != nil && err.Error() == store.UserNotFound {
-		response.Error(status.ErrorIncorrectEmailOrPassword)
-		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+	if err != nil && err.Error() != store.UserNotFound {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
-	} else if err != nil {
-		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+	}
 
+	// Checked before the password, so the password can not be guessed while login is delayed
+	if !s.verifyLoginBackoff(w, r, response, user, request.Password) {
 		return
 	}
 
-	// Handle case when user is not active
-	if !user.Active {
-		response.Error(status.ErrorUserNotActive)
-		api.ErrorResponse(response, http.StatusUnauthorized, w, r, err)
+	// If user is not found then tell user that email or password is incorrect
+	if user == nil {
+		if s.conf.Account.StrictResponses {
+			s.verifyDummyPassword(request.Password)
+		}
+
+		s.recordLoginFailure(r, nil)
+
+		audit.Record(r, s.repo, &store.AuditEvent{
+			Action:  store.GetAuditActions().LoginFailed,
+			Details: map[string]string{"reason": "unknown_email"},
+		})
+
+		response.Error(status.ErrorIncorrectEmailOrPassword)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
 
 		return
 	}
 
-	if user.VerifyIfUserIsSuspended(s.conf.Account.MaxLoginFailures) {
-		response.Errors = append(response.Errors,
-			api.Error{Code: status.ErrorUserSuspended, Message: fmt.Sprintf("%s %s", status.ErrorStatusText(status.ErrorUserSuspended), user.LoginBlockedUntil)})
-		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+	// Handle case when user is not active. In strict mode it is handled after the password is checked,
+	// so the response is the same as for the active user
+	if !user.Active && !s.conf.Account.StrictResponses {
+		s.recordAudit(r, store.GetAuditActions().LoginFailed, user.ID, map[string]string{"reason": "user_not_active"})
+
+		response.Error(status.ErrorUserNotActive)
+		api.ErrorResponse(response, http.StatusUnauthorized, w, r, err)
 
 		return
 	}
@@ -117,16 +217,43 @@ func (s *service) handleAuthenticateUser(w http.ResponseWriter, r *http.Request)
 	// All good so far, now check if password match. Password is hashed in database
 	// NOTE: We will not show user that he missed his password since that would be easy for
 	// hackers to guess that email is correct.
-	err = encryption.IsValid(user.Password, request.Password)
+	err = s.passwordHasher.Verify(user.Password, request.Password)
 	if err != nil {
+		// We want to count failed login every time a user misses his password
+		s.recordLoginFailure(r, user)
+
+		s.recordAudit(r, store.GetAuditActions().LoginFailed, user.ID, map[string]string{"reason": "invalid_password"})
+
 		response.Error(status.ErrorIncorrectEmailOrPassword)
 		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
 
-		// We want to increment the failed login counter every time a user misses his password.
-		_, err = s.repo.UpdateLoginAttempt(user.ID, s.conf.Account.BanDurationTime.Minutes(), s.conf.Account.MaxLoginFailures)
-		if err != nil {
-			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
-		}
+		return
+	}
+
+	// Reached only in strict mode, where only user who knows the password is told, by email, that the
+	// account is not active
+	if !user.Active {
+		s.recordAudit(r, store.GetAuditActions().LoginFailed, user.ID, map[string]string{"reason": "user_not_active"})
+
+		go s.mailjetClient.SendEmailAccountNotActive(s.conf.Email.AccountNotActiveTemplateID, user.Name,
+			s.conf.Email.SenderEmail, user.Email)
+
+		s.rejectLogin(w, r, response)
+
+		return
+	}
+
+	// Hashes generated with outdated algorithm or parameters are upgraded while the password is at hand
+	if s.passwordHasher.NeedsRehash(user.Password) {
+		s.rehashPassword(user, request.Password)
+	}
+
+	// Checked after the password, so the policy does not reveal anything about the account
+	if s.isEmailVerificationRequired(user) {
+		s.recordAudit(r, store.GetAuditActions().LoginFailed, user.ID, map[string]string{"reason": "email_not_verified"})
+
+		response.Error(status.ErrorEmailNotVerified)
+		api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
 
 		return
 	}
@@ -139,8 +266,18 @@ func (s *service) handleAuthenticateUser(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Let the client know if code from authenticator app is needed to authorize
+	mfa, err := s.repo.GetUserMFA(user.ID)
+	if err != nil && err.Error() != store.MFANotFound {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
 	response.Data = api.AuthenticateUserDataResponse{
-		Token: temporaryToken,
+		Token:         temporaryToken,
+		MFARequired:   mfa != nil && mfa.Enabled,
+		EmailVerified: user.EmailVerified,
 	}
 
 	api.SuccessResponse(response, http.StatusOK, w)
@@ -177,6 +314,12 @@ func (s *service) handleAuthorizeUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Codes are not checked while login is delayed, so they can not be guessed in a burst before the
+	// failed logins are counted
+	if !s.verifyLoginBackoff(w, r, response, user, "") {
+		return
+	}
+
 	// If temporary token has expired return unauthorized
 	if user.Expired {
 		response.Error(status.ErrorTokenExpiredOrNotValid)
@@ -193,30 +336,48 @@ func (s *service) handleAuthorizeUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Reset the login counter to zero when the user has successfully logged in
-	if user.FailedLoginCount > 0 {
-		err = s.repo.ResetFailedLoginCounter(user.ID)
-		if err != nil {
-			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+	// Users with enabled MFA must provide the code from authenticator app or a recovery code
+	if !s.authorizeSecondFactor(w, r, response, user, request) {
+		return
+	}
 
-			return
-		}
+	// Temporary token can be used only once, so it can not be replayed to log in again
+	mfaToken, err := s.repo.GetTokenByTokenAndType(request.Token, store.GetTokenTypes().MFA)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
 	}
 
-	token, err := s.createToken(r.Context(), user)
+	if err := s.repo.DeleteTokenByID(mfaToken.ID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	// Reset the login counters when the user has successfully logged in
+	if err := s.resetLoginFailures(r, user); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	token, sessionID, err := s.createToken(r.Context(), user, "")
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
 	}
 
-	refreshToken, err := s.createRefreshToken(user.ID)
+	refreshToken, err := s.createRefreshToken(user, sessionID, "")
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
 	}
 
+	s.recordSelfAudit(r, store.GetAuditActions().LoginSucceeded, user.ID)
+
 	response.Data = api.LoginDataResponse{
 		Email: user.Email,
 		Name:  user.Name,
@@ -249,6 +410,80 @@ func (s *service) handleGetRoles(w http.ResponseWriter, r *http.Request) {
 	api.SuccessResponse(response, http.StatusOK, w)
 }
 
+// handleSwitchRole re-issues the session of logged user with another role assigned to him. Session ID
+// is kept, while refresh tokens issued for the previous role are replaced.
+func (s *service) handleSwitchRole(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.SwitchRoleRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	user, err := s.repo.GetUserByID(requestData.UserID)
+	if err != nil {
+		response.Error(status.ErrorGetUser)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	selected, err := s.selectRole(user, request.RoleID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if !selected {
+		response.Error(status.ErrorRoleNotAssigned)
+		api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
+
+		return
+	}
+
+	// Refresh tokens of the session would restore the previous role
+	if err := s.repo.DeleteSessionTokens(user.ID, requestData.SessionID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	token, sessionID, err := s.createToken(r.Context(), user, requestData.SessionID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	refreshToken, err := s.createRefreshToken(user, sessionID, "")
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.LoginDataResponse{
+		Email: user.Email,
+		Name:  user.Name,
+		Token: api.Token{
+			Token:        token,
+			RefreshToken: refreshToken,
+		},
+		Role:     user.Role,
+		UserID:   user.ID,
+		Language: user.Language,
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
 // handleForgotPassword used by user to send an email with password reset link.
 func (s *service) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
 	requestData := api.RequestData(r)
@@ -265,9 +500,25 @@ func (s *service) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, err := s.repo.GetUserByEmail(request.Email)
-	if err != nil {
+	if err != nil && err.Error() != store.UserNotFound {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	} else if err != nil {
 		logger.Error().Err(err).Msgf("ForgotPassword unable to find an account: %v.", request.Email)
 
+		// In strict mode the response is the same as if the email was sent
+		if s.conf.Account.StrictResponses {
+			audit.Record(r, s.repo, &store.AuditEvent{
+				Action:  store.GetAuditActions().PasswordResetRequest,
+				Details: map[string]string{"reason": "unknown_email"},
+			})
+
+			api.SuccessResponse(response, http.StatusNoContent, w)
+
+			return
+		}
+
 		response.Error(status.ErrorEmailDoesNotExists)
 		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
 
@@ -278,17 +529,27 @@ func (s *service) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
 		logger.Error().Err(err).Msgf("ForgotPassword user is not active email: %v and user id: %v.",
 			request.Email, user.ID)
 
+		// In strict mode the response is the same as if the email was sent, and the user is told by email
+		// that the account is not active instead
+		if s.conf.Account.StrictResponses {
+			s.recordAudit(r, store.GetAuditActions().PasswordResetRequest, user.ID,
+				map[string]string{"reason": "user_not_active"})
+
+			go s.mailjetClient.SendEmailAccountNotActive(s.conf.Email.AccountNotActiveTemplateID, user.Name,
+				s.conf.Email.SenderEmail, user.Email)
+
+			api.SuccessResponse(response, http.StatusNoContent, w)
+
+			return
+		}
+
 		response.Error(status.ErrorUserNotActive)
 		api.ErrorResponse(response, http.StatusUnauthorized, w, r, err)
 
 		return
 	}
 
-	// Generate password token. For create an account this link sent to email should be active for 30 days
-	token := strings.ReplaceAll(utils.GenerateUniqueID()+utils.GenerateUniqueID()+utils.GenerateUniqueID(), "-", "")
-	tokenExpiresAt := time.Now().Add(s.conf.MFA.AccessTokenExpiration).Unix()
-
-	passwordToken, err := s.repo.AddPasswordResetToken(user.ID, token, tokenExpiresAt)
+	passwordToken, err := s.createPasswordToken(user.ID, s.conf.MFA.AccessTokenExpiration, false)
 	if err != nil {
 		logger.Error().Err(err).Msgf(`ForgotPassword unable to create password token code for email: %v and user id: %v.`,
 			request.Email, user.ID)
@@ -301,9 +562,203 @@ func (s *service) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
 	go s.mailjetClient.SendEmailResetPassword(s.conf.Email.ForgotPasswordTemplateID, user.Email,
 		s.conf.Email.SenderEmail, user.Email, passwordToken.Token)
 
+	s.recordAudit(r, store.GetAuditActions().PasswordResetRequest, user.ID, nil)
+
 	api.SuccessResponse(response, http.StatusNoContent, w)
 }
 
+// handleCreateAccount is used by admin to create an inactive account and invite the user to set his password.
+// If the invited user never set the password, e.g. because invitation expired, a new invitation is sent.
+func (s *service) handleCreateAccount(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.CreateAccountRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	// User stays inactive until he sets his password over the invitation link
+	user, err := s.repo.CreateUser(&store.User{
+		Name:   strings.TrimSpace(request.Name),
+		Email:  request.Email,
+		Role:   store.GetRoles().User.Name,
+		Active: false,
+	})
+
+	// Invitation is sent again to the user who never set the password, e.g. once the previous one expired
+	reinvited := err != nil && err.Error() == store.UserDuplicated
+	if reinvited {
+		var ok bool
+		if user, ok = s.getUserToReinvite(w, r, response, request.Email, err); !ok {
+			return
+		}
+
+		// Only the latest invitation link can be used
+		if err := s.repo.DeletePasswordTokens(user.ID); err != nil {
+			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+			return
+		}
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	// Invitation link should be active longer than the regular password reset link. Setting the password
+	// over the link sent to the email address verifies it, so no separate verification link is sent.
+	passwordToken, err := s.createPasswordToken(user.ID, s.conf.Account.InvitationExpiration, true)
+	if err != nil {
+		logger.Error().Err(err).Msgf("CreateAccount unable to create password token for email: %v and user id: %v.",
+			user.Email, user.ID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	// Send email in a new thread
+	go s.mailjetClient.SendEmailInvitation(s.conf.Email.InvitationTemplateID, user.Name,
+		s.conf.Email.SenderEmail, user.Email, passwordToken.Token)
+
+	if reinvited {
+		s.recordAudit(r, store.GetAuditActions().UserReinvited, user.ID, nil)
+	} else {
+		s.recordAudit(r, store.GetAuditActions().UserCreated, user.ID, nil)
+	}
+
+	response.Data = api.UserProfileDataResponse{
+		ID:       user.ID,
+		Name:     user.Name,
+		Email:    user.Email,
+		Language: user.Language,
+		Role:     user.Role,
+	}
+
+	if reinvited {
+		api.SuccessResponse(response, http.StatusOK, w)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusCreated, w)
+}
+
+// getUserToReinvite returns the user with the email address who was invited, but never set the password.
+// Error response is written if email address belongs to any other user.
+func (s *service) getUserToReinvite(w http.ResponseWriter, r *http.Request, response *api.BaseResponse, email string, duplicateErr error) (*store.User, bool) {
+	user, err := s.repo.GetUserByEmail(email)
+	if err != nil && err.Error() != store.UserNotFound {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	// Deleted users are not found, while users who set the password were already activated once
+	if err != nil || user.Active || user.Password != "" {
+		response.Error(status.ErrorEmailAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, duplicateErr)
+
+		return nil, false
+	}
+
+	// User deactivated by admin must not be activated again over the invitation
+	changedByAdmin, err := s.repo.HasUserActivationLog(user.ID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	if changedByAdmin {
+		response.Error(status.ErrorEmailAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, duplicateErr)
+
+		return nil, false
+	}
+
+	// Role is not loaded with the user looked up by email
+	user, err = s.repo.GetUserByID(user.ID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	return user, true
+}
+
+// handleRegister is used by user to create an account on their own. Account is active right away,
+// while login of users who did not verify their email address is governed by verification policy.
+func (s *service) handleRegister(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.RegisterRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if !api.ValidatePassword(s.passwordPolicy, request.Password, request.Email, response) {
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	hashedPassword, err := s.passwordHasher.Hash(request.Password)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	user, err := s.repo.CreateUser(&store.User{
+		Name:     strings.TrimSpace(request.Name),
+		Email:    request.Email,
+		Password: hashedPassword,
+		Role:     store.GetRoles().User.Name,
+		Active:   true,
+	})
+	if err != nil && err.Error() == store.UserDuplicated {
+		response.Error(status.ErrorEmailAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordSelfAudit(r, store.GetAuditActions().UserCreated, user.ID)
+
+	// Account is already created, so user can ask for a new verification link if this one fails
+	if err := s.sendVerificationEmail(user, user.Email); err != nil {
+		logger.Error().Err(err).Msgf("Register unable to create verification token for email: %v and user id: %v.",
+			user.Email, user.ID)
+	}
+
+	response.Data = api.UserProfileDataResponse{
+		ID:       user.ID,
+		Name:     user.Name,
+		Email:    user.Email,
+		Language: user.Language,
+		Role:     user.Role,
+	}
+
+	api.SuccessResponse(response, http.StatusCreated, w)
+}
+
 // handleSetPassword will set password if token is valid.
 func (s *service) handleSetPassword(w http.ResponseWriter, r *http.Request) {
 	requestData := api.RequestData(r)
@@ -328,27 +783,72 @@ func (s *service) handleSetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	password, err := encryption.Encrypt(request.Password)
+	user, err := s.repo.GetUserByID(token.UserID)
+	if err != nil {
+		response.Error(status.ErrorGetUser)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	// Deleted user can not log in, so he can not set the password either
+	if user.DeletedAt != nil {
+		response.Error(status.ErrorUserDeleted)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	// Only invited user is activated by setting the password, deactivated user stays inactive
+	if !user.Active && !token.Invitation {
+		response.Error(status.ErrorUserNotActive)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	if !api.ValidatePassword(s.passwordPolicy, request.Password, user.Email, response) {
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if !s.validatePasswordNotReused(w, r, response, user.ID, request.Password) {
+		return
+	}
+
+	hashedPassword, err := s.passwordHasher.Hash(request.Password)
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
 	}
 
-	user, err := s.repo.SetPassword(token.UserID, password, request.Token)
+	user, err = s.repo.SetPassword(token.UserID, hashedPassword, request.Token,
+		s.conf.Password.HistorySize)
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
 	}
 
-	sessionToken, err := s.createToken(r.Context(), user)
+	s.recordSelfAudit(r, store.GetAuditActions().PasswordReset, user.ID)
+
+	// Whoever knew the previous password should not stay logged in
+	if err := s.revokeSessions(r.Context(), user.ID, ""); err != nil {
+		logger.Error().Err(err).Msgf("failed to revoke sessions of user %v", user.ID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	sessionToken, sessionID, err := s.createToken(r.Context(), user, "")
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 		return
 	}
 
-	refreshToken, err := s.createRefreshToken(user.ID)
+	refreshToken, err := s.createRefreshToken(user, sessionID, "")
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 		return
@@ -390,6 +890,19 @@ func (s *service) handleGetUsers(w http.ResponseWriter, r *http.Request) {
 		filter.Active = &userActive
 	}
 
+	deletedStr := r.URL.Query().Get("deleted")
+	if deletedStr != "" {
+		userDeleted, err := strconv.ParseBool(deletedStr)
+		if err != nil {
+			response.Error(status.ErrorInvalidQueryURLParameters)
+			api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+			return
+		}
+
+		filter.Deleted = &userDeleted
+	}
+
 	search := r.URL.Query().Get("search")
 	if search != "" {
 		filter.Search = &search
@@ -432,6 +945,13 @@ func (s *service) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Token which was already rotated is presented again
+	if loginToken.Used {
+		s.handleRefreshTokenReuse(w, r, response, loginToken)
+
+		return
+	}
+
 	// Fetch user by ID
 	user, err := s.repo.GetUserByID(loginToken.UserID)
 	if err != nil {
@@ -449,23 +969,48 @@ func (s *service) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Delete old refresh token
-	if err = s.repo.DeleteTokenByID(loginToken.ID); err != nil {
+	// Handle case when user was deactivated after the token was issued
+	if !user.Active {
+		response.Error(status.ErrorUserNotActive)
+		api.ErrorResponse(response, http.StatusUnauthorized, w, r, nil)
+
+		return
+	}
+
+	// Keep the role user selected for the session, unless it was removed from the user in the meantime
+	if loginToken.RoleID != 0 && loginToken.RoleID != user.RoleID {
+		if _, err := s.selectRole(user, loginToken.RoleID); err != nil {
+			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+			return
+		}
+	}
+
+	// Rotate old refresh token. It is kept until it expires, so its reuse can be detected
+	rotated, err := s.repo.UseLoginToken(loginToken.ID)
+	if err != nil {
 		response.Error(status.ErrorDeleteToken)
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
 	}
 
-	// Generate access token
-	token, err := s.createToken(r.Context(), user)
+	// Token was rotated by another request in the meantime
+	if !rotated {
+		s.handleRefreshTokenReuse(w, r, response, loginToken)
+
+		return
+	}
+
+	// Generate access token, session refresh token was issued with is renewed
+	token, sessionID, err := s.createToken(r.Context(), user, loginToken.SessionID)
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 		return
 	}
 
-	// Generate refresh token
-	refreshToken, err := s.createRefreshToken(user.ID)
+	// Generate refresh token in the same family
+	refreshToken, err := s.createRefreshToken(user, sessionID, loginToken.FamilyID)
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 		return
@@ -487,7 +1032,32 @@ func (s *service) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
 	api.SuccessResponse(response, http.StatusOK, w)
 }
 
-// Activate will activate or deactivate user.
+// handleRefreshTokenReuse revokes the whole token family along with its session, since it is not known
+// whether the legitimate user or an attacker is presenting the rotated token.
+func (s *service) handleRefreshTokenReuse(w http.ResponseWriter, r *http.Request, response *api.BaseResponse,
+	loginToken *store.LoginToken,
+) {
+	requestData := api.RequestData(r)
+
+	logger.Warn().
+		Str("userID", loginToken.UserID.String()).
+		Str("familyID", loginToken.FamilyID).
+		Str("sessionID", loginToken.SessionID).
+		Str("ipAddress", requestData.IPAddress).
+		Msg("security event: refresh token reuse detected, revoking token family")
+
+	if err := s.revokeTokenFamily(r.Context(), loginToken); err != nil {
+		logger.Error().Err(err).Msgf("failed to revoke token family %v", loginToken.FamilyID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Error(status.ErrorTokenReused)
+	api.ErrorResponse(response, http.StatusUnauthorized, w, r, nil)
+}
+
+// handleActivateUser sets the active status of the user, setting the status the user already has succeeds without changes.
 func (s *service) handleActivateUser(w http.ResponseWriter, r *http.Request) {
 	requestData := api.RequestData(r)
 	request := new(api.UserActivateRequest)
@@ -509,14 +1079,78 @@ func (s *service) handleActivateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.repo.ActivateUser(request.UserID)
+	user, err := s.repo.GetUserByID(request.UserID)
+	if err != nil && err.Error() == store.UserNotFound {
+		response.Error(status.ErrorGetUser)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if user.DeletedAt != nil {
+		response.Error(status.ErrorUserDeleted)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+
+	// Setting the status the user already has is a no-op, nothing is logged or sent
+	changed, err := s.repo.SetUserActive(user.ID, *request.Active, reason, requestData.UserID)
 	if err != nil {
 		response.Error(status.ErrorActivateUser)
-		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
 	}
 
+	if changed {
+		action := store.GetAuditActions().UserActivated
+		if !*request.Active {
+			action = store.GetAuditActions().UserDeactivated
+		}
+
+		s.recordAudit(r, action, user.ID, map[string]string{"reason": reason})
+
+		// Deactivated user must not be able to use sessions created before, nor set password over the link
+		// sent before
+		if !*request.Active {
+			if err := s.revokeSessions(r.Context(), user.ID, ""); err != nil {
+				logger.Error().Err(err).Msgf("failed to revoke sessions of user %v", user.ID)
+				api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+				return
+			}
+
+			if err := s.repo.DeletePasswordTokens(user.ID); err != nil {
+				logger.Error().Err(err).Msgf("failed to delete password tokens of user %v", user.ID)
+				api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+				return
+			}
+		}
+
+		templateID := s.conf.Email.AccountActivatedTemplateID
+		if !*request.Active {
+			templateID = s.conf.Email.AccountDeactivatedTemplateID
+		}
+
+		// Send email in a new thread
+		go s.mailjetClient.SendEmailAccountStatusChanged(templateID, user.Name,
+			s.conf.Email.SenderEmail, user.Email, reason)
+	}
+
+	response.Data = api.UserActivateDataResponse{
+		ID:      user.ID,
+		Active:  *request.Active,
+		Changed: changed,
+	}
+
 	api.SuccessResponse(response, http.StatusOK, w)
 }
 
@@ -545,7 +1179,7 @@ func (s *service) handleChangePassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if current supplied password match password in DB
-	err = encryption.IsValid(user.Password, request.CurrentPassword)
+	err = s.passwordHasher.Verify(user.Password, request.CurrentPassword)
 	if err != nil {
 		response.Error(status.ErrorCurrentPasswordMismatch)
 		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
@@ -553,21 +1187,46 @@ func (s *service) handleChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !api.ValidatePassword(s.passwordPolicy, request.NewPassword, user.Email, response) {
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if !s.validatePasswordNotReused(w, r, response, user.ID, request.NewPassword) {
+		return
+	}
+
 	// All good save new password into database
-	newPassword, err := encryption.Encrypt(request.NewPassword)
+	newPassword, err := s.passwordHasher.Hash(request.NewPassword)
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
 	}
 
-	err = s.repo.SetNewPassword(requestData.UserID, newPassword)
+	err = s.repo.SetNewPassword(requestData.UserID, newPassword, s.conf.Password.HistorySize)
 	if err != nil {
 		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
 
 		return
 	}
 
+	s.recordAudit(r, store.GetAuditActions().PasswordChanged, requestData.UserID, nil)
+
+	// Log the user out everywhere else, current session is kept unless user asked otherwise
+	exceptSID := requestData.SessionID
+	if request.KeepCurrentSession != nil && !*request.KeepCurrentSession {
+		exceptSID = ""
+	}
+
+	if err := s.revokeSessions(r.Context(), requestData.UserID, exceptSID); err != nil {
+		logger.Error().Err(err).Msgf("failed to revoke sessions of user %v", requestData.UserID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
 	api.SuccessResponse(response, http.StatusOK, w)
 }
 
@@ -586,7 +1245,7 @@ func (s *service) handleUpdateUserProfile(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if requestData.UserID != request.UserID && requestData.Role != store.GetRoles().Admin.Name {
+	if requestData.UserID != request.UserID && !requestData.HasPermission(store.GetPermissions().UsersUpdate) {
 		api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
 
 		return
@@ -608,6 +1267,15 @@ func (s *service) handleUpdateUserProfile(w http.ResponseWriter, r *http.Request
 		user.Phone = *request.Phone
 	}
 
+	pendingEmail := ""
+	if request.Email != nil && !strings.EqualFold(*request.Email, user.Email) {
+		pendingEmail = *request.Email
+
+		if !s.validateNewEmail(w, r, response, pendingEmail) {
+			return
+		}
+	}
+
 	user, err = s.repo.UpdateUser(user)
 	if err != nil {
 		logger.Error().Err(err).Msgf("unable to update user with id %v", user.ID)
@@ -616,13 +1284,26 @@ func (s *service) handleUpdateUserProfile(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	s.recordAudit(r, store.GetAuditActions().ProfileUpdated, user.ID, nil)
+
+	// Email is changed only once the user proves they own the new address
+	if pendingEmail != "" {
+		if err := s.sendVerificationEmail(user, pendingEmail); err != nil {
+			logger.Error().Err(err).Msgf("unable to create email verification token for user with id %v", user.ID)
+			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+			return
+		}
+	}
+
 	response.Data = api.UserProfileDataResponse{
-		ID:       user.ID,
-		Name:     user.Name,
-		Email:    user.Email,
-		Phone:    user.Phone,
-		Language: user.Language,
-		Role:     user.Role,
+		ID:           user.ID,
+		Name:         user.Name,
+		Email:        user.Email,
+		Phone:        user.Phone,
+		Language:     user.Language,
+		Role:         user.Role,
+		PendingEmail: pendingEmail,
 	}
 
 	api.SuccessResponse(response, http.StatusOK, w)
@@ -643,8 +1324,8 @@ func (s *service) handleLogout(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	middlewareData := api.MiddlewareDataFromContext(ctx)
-	if middlewareData != nil && middlewareData.SessionKey != "" {
-		if err := s.inMemRepo.DelSessionWithKey(ctx, middlewareData.SessionKey); err != nil {
+	if middlewareData != nil && middlewareData.SessionID != "" {
+		if err := s.inMemRepo.DelSession(ctx, middlewareData.UserID, middlewareData.SessionID); err != nil {
 			logger.Warn().Err(err).Msgf("failed to delete session with key %v", middlewareData.SessionKey)
 		}
 	}
@@ -665,6 +1346,8 @@ func (s *service) handleLogout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, store.GetAuditActions().Logout, token.UserID, nil)
+
 	api.SuccessResponse(response, http.StatusOK, w)
 }
 
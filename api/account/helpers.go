@@ -2,78 +2,194 @@ package account
 
 import (
 	"context"
-	"encoding/json"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/api/audit"
 	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
 	"github.com/adinovcina/golang-setup/tools/utils"
 	"github.com/twinj/uuid"
 )
 
-// createToken will generate claims and sign in response which will go into header.
-func (s *service) createToken(ctx context.Context, in *store.User) (token string, err error) {
-	userData := &api.Data{
-		UserID:     in.ID,
-		Email:      in.Email,
-		Active:     in.Active,
-		Role:       in.Role,
-		UserRoleID: in.RoleID,
+// createSessionData builds data kept in the session of the user authorized with the role set on the user.
+// Permissions are kept in the session, so they are checked without hitting the database.
+func (s *service) createSessionData(in *store.User, sessionID string) (*api.Data, error) {
+	role, err := s.repo.GetRoleByID(in.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Data{
+		UserID:      in.ID,
+		Email:       in.Email,
+		Active:      in.Active,
+		Role:        in.Role,
+		UserRoleID:  in.RoleID,
+		SessionID:   sessionID,
+		Permissions: role.Permissions,
+	}, nil
+}
+
+// createToken will generate claims and sign in response which will go into header. Session with
+// the provided sessionID is renewed, otherwise a new session is created. ID of the session is returned.
+// Changes of role permissions are applied to the session once it is renewed.
+func (s *service) createToken(ctx context.Context, in *store.User, sessionID string) (token, sid string, err error) {
+	userData, err := s.createSessionData(in, sessionID)
+	if err != nil {
+		return "", "", err
 	}
 
 	// If Session is not created then notify clients but does not expose issue
-	jwtClaim, err := s.createSessionData(ctx, userData, s.conf.Redis.TokenTTL)
+	jwtClaim, err := api.NewSession(ctx, s.inMemRepo, userData, s.conf.Redis.TokenTTL)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	// Generate JWT Token
-	token, err = jwtClaim.CreateToken(s.conf.MFA.AccessTokenExpiration, s.conf.Redis.SecretKey)
+	token, err = jwtClaim.CreateToken(s.conf.MFA.AccessTokenExpiration, s.keyring)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	return token, nil
+	return token, jwtClaim.SessionID, nil
 }
 
-// createTemporaryToken will generate and persist short lasting token.
-func (s *service) createRefreshToken(userID uuid.UUID) (string, error) {
+// createRefreshToken will generate and persist refresh token bound to the user session and the role
+// user is authorized with. Token rotated from the previous one is added to its family, otherwise a new
+// family is started.
+func (s *service) createRefreshToken(user *store.User, sessionID, familyID string) (string, error) {
 	refreshToken := api.NewRefreshToken()
 
-	// Persist temp token
-	err := s.repo.AddLoginToken(userID, int64(s.conf.MFA.RefreshTokenExpiration.Minutes()), refreshToken, store.GetTokenTypes().RefreshToken)
+	if familyID == "" {
+		familyID = utils.GenerateUniqueID()
+	}
+
+	// Persist refresh token
+	err := s.repo.AddLoginToken(user.ID, int64(s.conf.MFA.RefreshTokenExpiration.Minutes()), refreshToken,
+		store.GetTokenTypes().RefreshToken, sessionID, familyID, user.RoleID)
 
 	return refreshToken, err
 }
 
+// selectRole sets the role user is authorized with, if it is one of the roles assigned to the user.
+func (s *service) selectRole(user *store.User, roleID int64) (bool, error) {
+	roles, err := s.repo.GetUserRoles(user.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range roles {
+		if role.ID == roleID {
+			user.Role = role.Name
+			user.RoleID = role.ID
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// revokeTokenFamily is used once rotated refresh token is presented again. Since it is not known whether
+// the legitimate user or an attacker presented it, all tokens of the family and the session are revoked.
+func (s *service) revokeTokenFamily(ctx context.Context, token *store.LoginToken) error {
+	if token.SessionID != "" {
+		if err := s.inMemRepo.DelSession(ctx, token.UserID, token.SessionID); err != nil {
+			return err
+		}
+	}
+
+	if token.FamilyID == "" {
+		return s.repo.DeleteTokenByID(token.ID)
+	}
+
+	return s.repo.DeleteTokenFamily(token.UserID, token.FamilyID)
+}
+
 // createTemporaryToken will generate and persist short lasting token.
 func (s *service) createTemporaryToken(user *store.User) (string, error) {
 	// Create temp token
 	temporaryToken := utils.GenerateUniqueID() + utils.GenerateUniqueID()
 	// Persist temp token
-	err := s.repo.AddLoginToken(user.ID, int64(s.conf.MFA.TemporaryTokenExpiration.Minutes()), temporaryToken, store.GetTokenTypes().MFA)
+	err := s.repo.AddLoginToken(user.ID, int64(s.conf.MFA.TemporaryTokenExpiration.Minutes()), temporaryToken, store.GetTokenTypes().MFA, "", "", 0)
 
 	return temporaryToken, err
 }
 
-// CreateSessionData will create a session object and store in redis.
-func (s *service) createSessionData(ctx context.Context, userData *api.Data, redisTokenTTL time.Duration) (claim *api.Claim, err error) {
-	claim = new(api.Claim)
+// createPasswordToken will generate and persist password token used in set password link. Invitation token
+// activates the user once password is set.
+func (s *service) createPasswordToken(userID uuid.UUID, ttl time.Duration, invitation bool) (*store.PasswordToken, error) {
+	token := strings.ReplaceAll(utils.GenerateUniqueID()+utils.GenerateUniqueID()+utils.GenerateUniqueID(), "-", "")
+	tokenExpiresAt := time.Now().Add(ttl).Unix()
 
-	claim.NewID()
-	claim.UserID = userData.UserID
+	return s.repo.AddPasswordResetToken(userID, token, tokenExpiresAt, invitation)
+}
 
-	userData.SessionKey = utils.FormatSessionKey(userData.UserID, claim.SessionID)
+// validatePasswordNotReused checks the password against the current and recently used passwords of the user.
+// Error response is written if password was used recently.
+func (s *service) validatePasswordNotReused(w http.ResponseWriter, r *http.Request, response *api.BaseResponse, userID uuid.UUID, password string) bool {
+	if s.conf.Password.HistorySize <= 0 {
+		return true
+	}
 
-	userDataMarshaled, err := json.Marshal(userData)
+	history, err := s.repo.GetPasswordHistory(userID, s.conf.Password.HistorySize)
 	if err != nil {
-		return nil, err
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
 	}
 
-	err = s.inMemRepo.SetSession(ctx, userData.UserID, claim.SessionID, string(userDataMarshaled), redisTokenTTL)
+	for _, hash := range history {
+		if s.passwordHasher.Verify(hash, password) == nil {
+			response.Error(status.ErrorPasswordRecentlyUsed)
+			api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// rehashPassword replaces the stored hash of the user password with a hash generated with the current
+// algorithm and parameters. Failure is only logged, since the old hash is still valid.
+func (s *service) rehashPassword(user *store.User, password string) {
+	hashedPassword, err := s.passwordHasher.Hash(password)
 	if err != nil {
-		return nil, err
+		logger.Error().Err(err).Msgf("failed to rehash password of user %v", user.ID)
+
+		return
+	}
+
+	if err := s.repo.UpdatePasswordHash(user.ID, user.Password, hashedPassword); err != nil {
+		logger.Error().Err(err).Msgf("failed to update password hash of user %v", user.ID)
+
+		return
 	}
 
-	return claim, nil
+	user.Password = hashedPassword
+}
+
+// recordAudit records the action performed on the target user. Actor is the logged user, so on public
+// routes, like failed login, actor stays empty.
+func (s *service) recordAudit(r *http.Request, action string, targetID uuid.UUID, details map[string]string) {
+	audit.Record(r, s.repo, &store.AuditEvent{
+		Action:   action,
+		TargetID: &targetID,
+		Details:  details,
+	})
+}
+
+// recordSelfAudit records the action user performed on his own account before he is logged in,
+// like login or password reset, where user already proved who he is.
+func (s *service) recordSelfAudit(r *http.Request, action string, userID uuid.UUID) {
+	audit.Record(r, s.repo, &store.AuditEvent{
+		Action:   action,
+		ActorID:  &userID,
+		TargetID: &userID,
+	})
 }
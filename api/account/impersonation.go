@@ -0,0 +1,168 @@
+package account
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+)
+
+// impersonationTTL is how long admin can act as the user. Session is not refreshed, admin has to
+// start impersonation again once it expires.
+const impersonationTTL = 15 * time.Minute
+
+// handleImpersonate is used by admin to act as another user. A new session of the user is created,
+// marked with the admin and the admin session it was started from.
+func (s *service) handleImpersonate(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.ImpersonateRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if request.UserID == requestData.UserID {
+		response.Error(status.ErrorImpersonationNotAllowed)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	user, err := s.repo.GetUserByID(request.UserID)
+	if err != nil && err.Error() == store.UserNotFound {
+		response.Error(status.ErrorGetUser)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if user.DeletedAt != nil {
+		response.Error(status.ErrorUserDeleted)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	if !user.Active {
+		response.Error(status.ErrorUserNotActive)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	sessionData, err := s.createSessionData(user, "")
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	// Admin can not gain permissions by impersonating user with more privileged role
+	for _, permission := range sessionData.Permissions {
+		if !requestData.HasPermission(permission) {
+			response.Error(status.ErrorImpersonationNotAllowed)
+			api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
+
+			return
+		}
+	}
+
+	adminID := requestData.UserID
+	sessionData.ImpersonatorID = &adminID
+	sessionData.ImpersonatorSessionID = requestData.SessionID
+
+	claim, err := api.NewSession(r.Context(), s.inMemRepo, sessionData, impersonationTTL)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	claim.ImpersonatorID = &adminID
+
+	token, err := claim.CreateToken(impersonationTTL, s.keyring)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().ImpersonationStarted, user.ID,
+		map[string]string{"sessionID": claim.SessionID})
+
+	response.Data = api.ImpersonationDataResponse{
+		ExpiresAt:      time.Unix(claim.ExpiresAt, 0).UTC(),
+		Token:          token,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           user.Role,
+		UserID:         user.ID,
+		ImpersonatorID: adminID,
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleEndImpersonation ends the session admin used to act as the user and returns a new token
+// of the admin session impersonation was started from.
+func (s *service) handleEndImpersonation(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	if !requestData.IsImpersonated() {
+		response.Error(status.ErrorNotImpersonating)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if err := s.inMemRepo.DelSession(r.Context(), requestData.UserID, requestData.SessionID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().ImpersonationEnded, requestData.UserID,
+		map[string]string{"sessionID": requestData.SessionID})
+
+	// Admin session could have expired or been revoked in the meantime, then admin has to log in again
+	adminID := *requestData.ImpersonatorID
+
+	_, err := s.inMemRepo.GetSession(r.Context(), adminID, requestData.ImpersonatorSessionID)
+	if err != nil {
+		response.Error(status.ErrorSessionNotFound)
+		api.ErrorResponse(response, http.StatusUnauthorized, w, r, err)
+
+		return
+	}
+
+	claim := &api.Claim{UserID: adminID, SessionID: requestData.ImpersonatorSessionID}
+
+	token, err := claim.CreateToken(s.conf.MFA.AccessTokenExpiration, s.keyring)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.EndImpersonationDataResponse{
+		Token:  token,
+		UserID: adminID,
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
@@ -0,0 +1,129 @@
+package account
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/api/audit"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/utils"
+	"github.com/twinj/uuid"
+)
+
+// verifyLoginBackoff checks if login from the IP address, and to the account when user is known, is delayed
+// because of too many failed logins. Error response with Retry-After header is written if it is. In strict
+// mode, response is the same as for incorrect password and the delay is only recorded to the audit log.
+func (s *service) verifyLoginBackoff(w http.ResponseWriter, r *http.Request, response *api.BaseResponse,
+	user *store.User, password string,
+) bool {
+	var userID *uuid.UUID
+	if user != nil {
+		userID = &user.ID
+	}
+
+	failures, err := s.inMemRepo.GetLoginFailures(r.Context(), userID, s.clientIP(r))
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
+	}
+
+	now := time.Now()
+
+	backoff := s.lockoutPolicy.Backoff(failures, now)
+	if backoff <= 0 {
+		return true
+	}
+
+	if s.conf.Account.StrictResponses {
+		s.verifyDummyPassword(password)
+
+		audit.Record(r, s.repo, &store.AuditEvent{
+			Action:   store.GetAuditActions().LoginFailed,
+			TargetID: userID,
+			Details:  map[string]string{"reason": "login_delayed"},
+		})
+
+		s.rejectLogin(w, r, response)
+
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(backoff.Seconds()))))
+
+	response.Errors = append(response.Errors, api.Error{
+		Code: status.ErrorUserSuspended,
+		Message: fmt.Sprintf("%s %s", status.ErrorStatusText(status.ErrorUserSuspended),
+			now.Add(backoff).UTC().Format(time.RFC3339)),
+	})
+	api.ErrorResponse(response, http.StatusTooManyRequests, w, r, nil)
+
+	return false
+}
+
+// recordLoginFailure counts failed login from the IP address, and to the account when user is known. Returns
+// how long the next login is delayed. Once login to the account is delayed regardless of the IP address,
+// number of failures is saved to the user as well, so admin can see it. Failure is only logged, since
+// the login fails anyway.
+func (s *service) recordLoginFailure(r *http.Request, user *store.User) time.Duration {
+	ip := s.clientIP(r)
+
+	var userID *uuid.UUID
+	if user != nil {
+		userID = &user.ID
+	}
+
+	failures, err := s.inMemRepo.AddLoginFailure(r.Context(), userID, ip, s.conf.Account.Lockout.Window)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to record failed login from %s", ip)
+
+		return 0
+	}
+
+	now := time.Now()
+
+	if user != nil {
+		if backoff := s.lockoutPolicy.AccountBackoff(failures.Account, now); backoff > 0 {
+			blockedUntil := now.Add(backoff).UTC()
+
+			err := s.repo.SetLoginFailures(user.ID, failures.Account.Count, blockedUntil)
+			if err != nil {
+				logger.Error().Err(err).Msgf("failed to save failed logins of user %v", user.ID)
+			}
+
+			// In strict mode user is not told their login is delayed, so they are notified once it starts
+			if s.conf.Account.StrictResponses && failures.Account.Count == int64(s.conf.Account.Lockout.AccountThreshold) {
+				go s.mailjetClient.SendEmailLoginDelayed(s.conf.Email.LoginDelayedTemplateID, user.Name,
+					s.conf.Email.SenderEmail, user.Email, blockedUntil.Format(time.RFC3339))
+			}
+		}
+	}
+
+	return s.lockoutPolicy.Backoff(failures, now)
+}
+
+// resetLoginFailures forgets failed logins to the account once user logs in. Failures from the IP address
+// to other accounts are kept.
+func (s *service) resetLoginFailures(r *http.Request, user *store.User) error {
+	if err := s.inMemRepo.DelLoginFailures(r.Context(), user.ID, s.clientIP(r)); err != nil {
+		return err
+	}
+
+	if user.FailedLoginCount > 0 {
+		return s.repo.ResetFailedLoginCounter(user.ID)
+	}
+
+	return nil
+}
+
+// clientIP returns IP address failed logins are counted for. Proxy headers are only used when the request
+// is sent by trusted proxy, so the client can not reset its counters by sending a different address.
+func (s *service) clientIP(r *http.Request) string {
+	return utils.TrustedClientIP(r, s.conf.RateLimit.TrustedProxies)
+}
@@ -0,0 +1,349 @@
+package account
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/encryption"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/totp"
+)
+
+// handleEnrollMFA generates a new TOTP secret for logged user. MFA is not enforced
+// until user confirms enrollment with the code from authenticator app.
+func (s *service) handleEnrollMFA(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	mfa, err := s.repo.GetUserMFA(requestData.UserID)
+	if err != nil && err.Error() != store.MFANotFound {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if mfa != nil && mfa.Enabled {
+		response.Error(status.ErrorMFAAlreadyEnabled)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if err := s.repo.SetUserMFASecret(requestData.UserID, secret); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.MFAEnrollDataResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.conf.MFA.Issuer, requestData.Email, secret),
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleConfirmMFA enables MFA for logged user once the code from authenticator app is verified
+// and returns recovery codes.
+func (s *service) handleConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.MFACodeRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	// Enrollment can only be confirmed with the code generated from the new secret
+	if valid && strings.TrimSpace(request.Code) == "" {
+		response.Error(status.ErrorMissingMFACode)
+		valid = false
+	}
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	mfa, err := s.repo.GetUserMFA(requestData.UserID)
+	if err != nil && err.Error() == store.MFANotFound {
+		response.Error(status.ErrorMFANotEnrolled)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if mfa.Enabled {
+		response.Error(status.ErrorMFAAlreadyEnabled)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	step, ok := totp.Validate(mfa.Secret, request.Code, time.Now())
+	if !ok {
+		response.Error(status.ErrorInvalidMFACode)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if err := s.repo.EnableUserMFA(requestData.UserID, step); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().MFAEnabled, requestData.UserID, nil)
+
+	recoveryCodes, err := s.createRecoveryCodes(mfa)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.MFARecoveryCodesDataResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleRegenerateRecoveryCodes replaces recovery codes of logged user with the new ones.
+func (s *service) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.MFACodeRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	mfa, ok := s.verifyMFARequest(w, r, response, request)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := s.createRecoveryCodes(mfa)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.MFARecoveryCodesDataResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleDisableMFA disables MFA for logged user. Current code or recovery code is required.
+func (s *service) handleDisableMFA(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.MFACodeRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if _, ok := s.verifyMFARequest(w, r, response, request); !ok {
+		return
+	}
+
+	if err := s.repo.DisableUserMFA(requestData.UserID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().MFADisabled, requestData.UserID, nil)
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// verifyMFARequest checks if logged user has enabled MFA and if code from the request is valid.
+// Error response is written if verification fails.
+func (s *service) verifyMFARequest(w http.ResponseWriter, r *http.Request,
+	response *api.BaseResponse, request *api.MFACodeRequest,
+) (*store.UserMFA, bool) {
+	requestData := api.RequestData(r)
+
+	mfa, err := s.repo.GetUserMFA(requestData.UserID)
+	if err != nil && err.Error() != store.MFANotFound {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	if mfa == nil || !mfa.Enabled {
+		response.Error(status.ErrorMFANotEnrolled)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return nil, false
+	}
+
+	valid, err := s.verifyMFACode(mfa, request.Code, request.RecoveryCode)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	if !valid {
+		response.Error(status.ErrorInvalidMFACode)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return nil, false
+	}
+
+	return mfa, true
+}
+
+// authorizeSecondFactor checks second factor of the user during login. Users without
+// enabled MFA pass without code. Error response is written if verification fails.
+func (s *service) authorizeSecondFactor(w http.ResponseWriter, r *http.Request,
+	response *api.BaseResponse, user *store.User, request *api.AuthorizeRequest,
+) bool {
+	mfa, err := s.repo.GetUserMFA(user.ID)
+	if err != nil && err.Error() != store.MFANotFound {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
+	}
+
+	if mfa == nil || !mfa.Enabled {
+		return true
+	}
+
+	if strings.TrimSpace(request.Code) == "" && strings.TrimSpace(request.RecoveryCode) == "" {
+		response.Error(status.ErrorMissingMFACode)
+		api.ErrorResponse(response, http.StatusUnauthorized, w, r, nil)
+
+		return false
+	}
+
+	valid, err := s.verifyMFACode(mfa, request.Code, request.RecoveryCode)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
+	}
+
+	if valid {
+		return true
+	}
+
+	s.recordAudit(r, store.GetAuditActions().LoginFailed, user.ID, map[string]string{"reason": "invalid_mfa_code"})
+
+	response.Error(status.ErrorInvalidMFACode)
+	api.ErrorResponse(response, http.StatusUnauthorized, w, r, nil)
+
+	// Failed codes count as failed logins, so the code can not be brute forced. Once login is delayed or
+	// too many codes were sent with the temporary token, it is revoked and user must authenticate again
+	backoff := s.recordLoginFailure(r, user)
+
+	token, err := s.repo.GetTokenByTokenAndType(request.Token, store.GetTokenTypes().MFA)
+	if err != nil {
+		logger.Error().Err(err).Msgf("unable to get temporary token for user %v", user.ID)
+
+		return false
+	}
+
+	attempts, err := s.repo.AddLoginTokenFailedAttempt(token.ID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("unable to count failed attempt of temporary token for user %v", user.ID)
+	}
+
+	// Token is revoked if the attempt could not be counted as well, so the cap can not be avoided
+	if err == nil && backoff <= 0 && attempts < int64(s.conf.MFA.MaxAttempts) {
+		return false
+	}
+
+	if err := s.repo.DeleteTokenByID(token.ID); err != nil {
+		logger.Error().Err(err).Msgf("unable to delete temporary token for user %v", user.ID)
+	}
+
+	return false
+}
+
+// verifyMFACode checks code from authenticator app or recovery code. Accepted code can not be used again.
+func (s *service) verifyMFACode(mfa *store.UserMFA, code, recoveryCode string) (bool, error) {
+	if strings.TrimSpace(code) != "" {
+		step, valid := totp.Validate(mfa.Secret, code, time.Now())
+		if !valid {
+			return false, nil
+		}
+
+		// Step is stored only if it is newer than the last used one, which prevents replay
+		return s.repo.UpdateMFALastUsedStep(mfa.UserID, step)
+	}
+
+	recoveryCodes, err := s.repo.GetRecoveryCodes(mfa.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	normalizedCode := totp.NormalizeRecoveryCode(recoveryCode)
+
+	for _, recoveryCode := range recoveryCodes {
+		if encryption.IsValid(recoveryCode.CodeHash, normalizedCode) == nil {
+			return s.repo.UseRecoveryCode(recoveryCode.ID)
+		}
+	}
+
+	return false, nil
+}
+
+// createRecoveryCodes generates new recovery codes and persists their hashes.
+func (s *service) createRecoveryCodes(mfa *store.UserMFA) ([]string, error) {
+	recoveryCodes, err := totp.GenerateRecoveryCodes(s.conf.MFA.RecoveryCodesCount)
+	if err != nil {
+		return nil, err
+	}
+
+	codeHashes := make([]string, 0, len(recoveryCodes))
+
+	for _, recoveryCode := range recoveryCodes {
+		codeHash, err := encryption.Encrypt(totp.NormalizeRecoveryCode(recoveryCode))
+		if err != nil {
+			return nil, err
+		}
+
+		codeHashes = append(codeHashes, codeHash)
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(mfa.UserID, codeHashes); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
@@ -3,25 +3,44 @@ package account
 import (
 	"github.com/adinovcina/golang-setup/config"
 	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/encryption"
+	"github.com/adinovcina/golang-setup/tools/lockout"
 	mailjet "github.com/adinovcina/golang-setup/tools/mailjet"
+	"github.com/adinovcina/golang-setup/tools/password"
+	"github.com/adinovcina/golang-setup/tools/signing"
 )
 
 type service struct {
-	conf          *config.Config
-	repo          store.Repository
-	inMemRepo     store.InMemRepository
-	mailjetClient *mailjet.Client
+	conf           *config.Config
+	repo           store.Repository
+	inMemRepo      store.InMemRepository
+	mailjetClient  *mailjet.Client
+	passwordPolicy *password.Policy
+	passwordHasher encryption.Hasher
+	lockoutPolicy  *lockout.Policy
+	keyring        *signing.Keyring
+	// dummyPasswordHash is verified for unknown users in strict mode, so they take as long as the existing ones
+	dummyPasswordHash string
 }
 
 func newService(conf *config.Config,
 	repo store.Repository,
 	inMemRepo store.InMemRepository,
 	mailjetClient *mailjet.Client,
+	passwordPolicy *password.Policy,
+	passwordHasher encryption.Hasher,
+	lockoutPolicy *lockout.Policy,
+	keyring *signing.Keyring,
 ) service {
 	return service{
 		conf,
 		repo,
 		inMemRepo,
 		mailjetClient,
+		passwordPolicy,
+		passwordHasher,
+		lockoutPolicy,
+		keyring,
+		newDummyPasswordHash(passwordHasher),
 	}
 }
@@ -0,0 +1,205 @@
+package account
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/go-chi/chi/v5"
+	"github.com/twinj/uuid"
+)
+
+// handleGetSessions retrieves list of active sessions of logged user.
+func (s *service) handleGetSessions(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	sessions, err := s.getSessions(r.Context(), requestData.UserID, requestData.SessionID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = sessions
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleRevokeSession logs the user out of one of their sessions.
+func (s *service) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	if !s.revokeSession(w, r, response, requestData.UserID, chi.URLParam(r, "sessionID")) {
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleRevokeSessions logs the user out everywhere except from the session used to send the request.
+func (s *service) handleRevokeSessions(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	if err := s.revokeSessions(r.Context(), requestData.UserID, requestData.SessionID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleGetUserSessions is used by admin to retrieve list of active sessions of any user.
+func (s *service) handleGetUserSessions(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	userID, ok := s.getUserIDParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	sessions, err := s.getSessions(r.Context(), userID, requestData.SessionID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = sessions
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleRevokeUserSession is used by admin to log any user out of one of their sessions.
+func (s *service) handleRevokeUserSession(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	userID, ok := s.getUserIDParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	if !s.revokeSession(w, r, response, userID, chi.URLParam(r, "sessionID")) {
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleRevokeUserSessions is used by admin to log any user out everywhere. Session used
+// to send the request is kept when admin revokes their own sessions.
+func (s *service) handleRevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	userID, ok := s.getUserIDParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	exceptSID := ""
+	if userID == requestData.UserID {
+		exceptSID = requestData.SessionID
+	}
+
+	if err := s.revokeSessions(r.Context(), userID, exceptSID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().SessionsRevoked, userID, nil)
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// getSessions retrieves active sessions of the user and marks the one used to send the request.
+func (s *service) getSessions(ctx context.Context, userID uuid.UUID, currentSID string) ([]*store.Session, error) {
+	sessions, err := s.inMemRepo.GetSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.ID == currentSID
+	}
+
+	return sessions, nil
+}
+
+// revokeSession deletes the user session along with refresh token issued for it.
+// Error response is written if session can not be revoked.
+func (s *service) revokeSession(w http.ResponseWriter, r *http.Request, response *api.BaseResponse,
+	userID uuid.UUID, sessionID string,
+) bool {
+	sessions, err := s.inMemRepo.GetSessions(r.Context(), userID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
+	}
+
+	found := false
+
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		response.Error(status.ErrorSessionNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, nil)
+
+		return false
+	}
+
+	if err := s.inMemRepo.DelSession(r.Context(), userID, sessionID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
+	}
+
+	if err := s.repo.DeleteSessionTokens(userID, sessionID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
+	}
+
+	return true
+}
+
+// revokeSessions deletes all user sessions and login tokens, except the ones belonging
+// to the session provided in exceptSID.
+func (s *service) revokeSessions(ctx context.Context, userID uuid.UUID, exceptSID string) error {
+	if _, err := s.inMemRepo.DelSessions(ctx, userID, exceptSID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteUserTokens(userID, exceptSID)
+}
+
+// getUserIDParam parses the user id URL param and checks if user exists.
+// Error response is written if user can not be retrieved.
+func (s *service) getUserIDParam(w http.ResponseWriter, r *http.Request, response *api.BaseResponse) (uuid.UUID, bool) {
+	user, ok := s.getUserParam(w, r, response)
+	if !ok {
+		return uuid.UUID{}, false
+	}
+
+	return user.ID, true
+}
@@ -0,0 +1,34 @@
+package account
+
+import (
+	"net/http"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/tools/encryption"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/twinj/uuid"
+)
+
+// newDummyPasswordHash hashes random password with the configured algorithm and parameters, so verifying
+// it costs the same as verifying password of the existing user.
+func newDummyPasswordHash(passwordHasher encryption.Hasher) string {
+	hash, err := passwordHasher.Hash(uuid.NewV4().String())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to hash dummy password")
+	}
+
+	return hash
+}
+
+// verifyDummyPassword takes as long as verifying password of the existing user, so response time
+// does not reveal that the account does not exist.
+func (s *service) verifyDummyPassword(password string) {
+	_ = s.passwordHasher.Verify(s.dummyPasswordHash, password)
+}
+
+// rejectLogin writes the same response for every failed login in strict mode, whatever the reason.
+func (s *service) rejectLogin(w http.ResponseWriter, r *http.Request, response *api.BaseResponse) {
+	response.Error(status.ErrorIncorrectEmailOrPassword)
+	api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+}
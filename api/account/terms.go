@@ -0,0 +1,147 @@
+package account
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+)
+
+// handleGetTerms retrieves the current version of terms of service.
+func (s *service) handleGetTerms(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	terms, ok := s.getCurrentTerms(w, r, response)
+	if !ok {
+		return
+	}
+
+	response.Data = terms
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleGetTermsAcceptance tells logged user if they accepted the current version of terms of service.
+func (s *service) handleGetTermsAcceptance(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	acceptance, err := s.repo.GetUserTermsAcceptance(requestData.UserID)
+	if err != nil && err.Error() == store.TermsNotFound {
+		response.Error(status.ErrorTermsNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = acceptance
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleAcceptTerms records that logged user accepted the current version of terms of service.
+func (s *service) handleAcceptTerms(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.AcceptTermsRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	terms, ok := s.getCurrentTerms(w, r, response)
+	if !ok {
+		return
+	}
+
+	// New version might be published while user was reading the previous one
+	if terms.Version != strings.TrimSpace(request.Version) {
+		response.Error(status.ErrorTermsVersionMismatch)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	if err := s.repo.AcceptTerms(requestData.UserID, terms.ID, requestData.IPAddress); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleCreateTerms is used by admin to publish a new version of terms of service. Once the version
+// is published, users have to accept it before they can continue using the application.
+func (s *service) handleCreateTerms(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.CreateTermsRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	terms := &store.Terms{
+		Version: strings.TrimSpace(request.Version),
+		Content: request.Content,
+	}
+
+	if request.PublishedAt != nil {
+		terms.PublishedAt = *request.PublishedAt
+	}
+
+	terms, err := s.repo.CreateTerms(terms, requestData.UserID)
+	if err != nil && err.Error() == store.TermsDuplicated {
+		response.Error(status.ErrorTermsVersionAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = terms
+
+	api.SuccessResponse(response, http.StatusCreated, w)
+}
+
+// getCurrentTerms retrieves the current version of terms of service.
+// Error response is written if no version is published.
+func (s *service) getCurrentTerms(w http.ResponseWriter, r *http.Request, response *api.BaseResponse) (*store.Terms, bool) {
+	terms, err := s.repo.GetCurrentTerms()
+	if err != nil && err.Error() == store.TermsNotFound {
+		response.Error(status.ErrorTermsNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return nil, false
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	return terms, true
+}
@@ -0,0 +1,131 @@
+package account
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// handleGetAccessTokens retrieves list of personal access tokens of logged user.
+func (s *service) handleGetAccessTokens(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	tokens, err := s.repo.GetPersonalAccessTokens(requestData.UserID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.PersonalAccessTokensDataResponse{Tokens: tokens}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleCreateAccessToken creates personal access token acting with the role user is authorized with.
+// Token is returned only once, only its hash is stored.
+func (s *service) handleCreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.PersonalAccessTokenRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if request.ExpiresAt.After(time.Now().Add(s.conf.Account.PersonalAccessTokenMaxLifetime)) {
+		response.Error(status.ErrorInvalidExpiration)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	// Token can not be granted more than the role user is authorized with
+	scopes := make([]string, 0, len(request.Scopes))
+
+	for _, scope := range request.Scopes {
+		if !requestData.HasPermission(scope) {
+			response.Error(status.ErrorInvalidPermission)
+			api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+			return
+		}
+
+		if !utils.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	plainToken := api.NewPersonalAccessToken()
+
+	token, err := s.repo.CreatePersonalAccessToken(&store.PersonalAccessToken{
+		UserID:    requestData.UserID,
+		Name:      strings.TrimSpace(request.Name),
+		TokenHash: api.HashToken(plainToken),
+		RoleID:    requestData.UserRoleID,
+		Scopes:    scopes,
+		ExpiresAt: request.ExpiresAt.UTC(),
+	})
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().AccessTokenCreated, requestData.UserID,
+		map[string]string{"tokenID": strconv.FormatInt(token.ID, 10), "scopes": strings.Join(token.Scopes, " ")})
+
+	response.Data = api.PersonalAccessTokenDataResponse{
+		PersonalAccessToken: token,
+		Token:               plainToken,
+	}
+
+	api.SuccessResponse(response, http.StatusCreated, w)
+}
+
+// handleDeleteAccessToken revokes personal access token of logged user.
+func (s *service) handleDeleteAccessToken(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(status.ErrorInvalidURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	err = s.repo.DeletePersonalAccessToken(requestData.UserID, id)
+	if err != nil && err.Error() == store.PersonalAccessTokenNotFound {
+		response.Error(status.ErrorPersonalAccessTokenNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().AccessTokenRevoked, requestData.UserID,
+		map[string]string{"tokenID": strconv.FormatInt(id, 10)})
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
@@ -0,0 +1,330 @@
+package account
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/go-chi/chi/v5"
+	"github.com/twinj/uuid"
+)
+
+// handleGetUser is used by admin to fetch details of the user along with all roles assigned to the user.
+func (s *service) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	user, ok := s.getUserParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	s.writeUserDetail(w, r, response, user)
+}
+
+// handleUpdateUser is used by admin to edit details of the user. Email set by admin has to be verified
+// again, so the verification link is sent to the new address.
+func (s *service) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.UpdateUserRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	user, ok := s.getActiveUserParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	if request.Name != nil {
+		user.Name = strings.TrimSpace(*request.Name)
+	}
+
+	if request.Phone != nil {
+		user.Phone = *request.Phone
+	}
+
+	if request.Language != nil {
+		user.Language = strings.ToLower(*request.Language)
+	}
+
+	emailChanged := request.Email != nil && !strings.EqualFold(*request.Email, user.Email)
+	if emailChanged {
+		user.Email = *request.Email
+	}
+
+	err := s.repo.UpdateUserDetails(user)
+	if err != nil && err.Error() == store.UserDuplicated {
+		response.Error(status.ErrorEmailAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().UserUpdated, user.ID, nil)
+
+	// User is already updated, so admin can ask for a new verification link if this one fails
+	if emailChanged {
+		if err := s.sendVerificationEmail(user, user.Email); err != nil {
+			logger.Error().Err(err).Msgf("UpdateUser unable to create verification token for user id: %v.", user.ID)
+		}
+	}
+
+	user, err = s.repo.GetUserByID(user.ID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.writeUserDetail(w, r, response, user)
+}
+
+// handleSetUserRoles is used by admin to replace roles assigned to the user. User sessions are revoked,
+// so permissions of the removed roles can not be used anymore.
+func (s *service) handleSetUserRoles(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.SetUserRolesRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	user, ok := s.getActiveUserParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	if user.ID == requestData.UserID {
+		response.Error(status.ErrorUnableToChangeOwnRoles)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	for _, roleID := range request.RoleIDs {
+		_, err := s.repo.GetRoleByID(roleID)
+		if err != nil && err.Error() == store.RoleNotFound {
+			response.Error(status.ErrorRoleNotFound)
+			api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+			return
+		} else if err != nil {
+			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+			return
+		}
+	}
+
+	if err := s.repo.SetUserRoles(user.ID, request.RoleIDs); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().UserRolesChanged, user.ID,
+		map[string]string{"roleIDs": formatRoleIDs(request.RoleIDs)})
+
+	if err := s.revokeSessions(r.Context(), user.ID, ""); err != nil {
+		logger.Error().Err(err).Msgf("failed to revoke sessions of user %v", user.ID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	user, err := s.repo.GetUserByID(user.ID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.writeUserDetail(w, r, response, user)
+}
+
+// handleUnlockUser is used by admin to let the user suspended after too many failed logins log in again.
+func (s *service) handleUnlockUser(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	user, ok := s.getActiveUserParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	if err := s.repo.UnlockUser(user.ID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	// Failed logins from all IP addresses are forgotten, so the user can log in right away
+	if err := s.inMemRepo.DelLoginFailures(r.Context(), user.ID, ""); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().UserUnlocked, user.ID, nil)
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleDeleteUser is used by admin to delete the user. User is logged out everywhere, and can be restored later.
+func (s *service) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	user, ok := s.getActiveUserParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	if user.ID == requestData.UserID {
+		response.Error(status.ErrorUnableToDeleteOwnAccount)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if err := s.repo.DeleteUser(user.ID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().UserDeleted, user.ID, nil)
+
+	if err := s.revokeSessions(r.Context(), user.ID, ""); err != nil {
+		logger.Error().Err(err).Msgf("failed to revoke sessions of user %v", user.ID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleRestoreUser is used by admin to restore the deleted user.
+func (s *service) handleRestoreUser(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	user, ok := s.getUserParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	if user.DeletedAt == nil {
+		response.Error(status.ErrorUserNotDeleted)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	if err := s.repo.RestoreUser(user.ID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	s.recordAudit(r, store.GetAuditActions().UserRestored, user.ID, nil)
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// writeUserDetail writes details of the user along with all roles assigned to the user.
+func (s *service) writeUserDetail(w http.ResponseWriter, r *http.Request, response *api.BaseResponse, user *store.User) {
+	roles, err := s.repo.GetUserRoles(user.ID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.UserDetailDataResponse{
+		User:  user,
+		Roles: roles,
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// getUserParam parses the user id URL param and retrieves the user.
+// Error response is written if user can not be retrieved.
+func (s *service) getUserParam(w http.ResponseWriter, r *http.Request, response *api.BaseResponse) (*store.User, bool) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(status.ErrorInvalidURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return nil, false
+	}
+
+	user, err := s.repo.GetUserByID(*userID)
+	if err != nil && err.Error() == store.UserNotFound {
+		response.Error(status.ErrorGetUser)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return nil, false
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	return user, true
+}
+
+// getActiveUserParam retrieves the user from the URL param, which must not be deleted.
+// Error response is written if user can not be retrieved or is deleted.
+func (s *service) getActiveUserParam(w http.ResponseWriter, r *http.Request, response *api.BaseResponse) (*store.User, bool) {
+	user, ok := s.getUserParam(w, r, response)
+	if !ok {
+		return nil, false
+	}
+
+	if user.DeletedAt != nil {
+		response.Error(status.ErrorUserDeleted)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return nil, false
+	}
+
+	return user, true
+}
+
+// formatRoleIDs formats role IDs as comma separated list.
+func formatRoleIDs(roleIDs []int64) string {
+	ids := make([]string, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		ids = append(ids, strconv.FormatInt(roleID, 10))
+	}
+
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,193 @@
+package account
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/utils"
+)
+
+const verificationEmailThrottleAction = "verification-email"
+
+// handleVerifyEmail verifies the email address using the token from the verification link. If the token
+// was issued for a new email address, it becomes the user email.
+func (s *service) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.VerifyEmailRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	user, err := s.repo.VerifyEmail(api.HashToken(request.Token))
+	if err != nil && err.Error() == store.EmailVerificationTokenNotFound {
+		response.Error(status.ErrorTokenExpiredOrNotValid)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	} else if err != nil && err.Error() == store.UserDuplicated {
+		// New email address was taken by another account after the link was sent
+		response.Error(status.ErrorEmailAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.UserProfileDataResponse{
+		ID:    user.ID,
+		Name:  user.Name,
+		Email: user.Email,
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleResendVerificationEmail sends a new verification link to the user whose email address is not
+// verified. Link can be requested again for the same address once resend interval passes.
+func (s *service) handleResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.ResendVerificationEmailRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	// Throttle before the lookup, so the endpoint can not be used to flood any address
+	allowed, err := s.inMemRepo.Throttle(r.Context(),
+		utils.FormatThrottleKey(verificationEmailThrottleAction, strings.ToLower(request.Email)),
+		s.conf.Account.EmailVerification.ResendInterval)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if !allowed {
+		response.Error(status.ErrorTooManyRequests)
+		api.ErrorResponse(response, http.StatusTooManyRequests, w, r, nil)
+
+		return
+	}
+
+	user, err := s.repo.GetUserByEmail(request.Email)
+	if err != nil && err.Error() == store.UserNotFound {
+		// In strict mode the response is the same as if the email was sent
+		if s.conf.Account.StrictResponses {
+			api.SuccessResponse(response, http.StatusNoContent, w)
+
+			return
+		}
+
+		response.Error(status.ErrorEmailDoesNotExists)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if user.EmailVerified {
+		if s.conf.Account.StrictResponses {
+			api.SuccessResponse(response, http.StatusNoContent, w)
+
+			return
+		}
+
+		response.Error(status.ErrorEmailAlreadyVerified)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	if err := s.sendVerificationEmail(user, user.Email); err != nil {
+		logger.Error().Err(err).Msgf("ResendVerificationEmail unable to create verification token for user id: %v.", user.ID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// sendVerificationEmail issues verification token for the email address and sends the verification link
+// to it. Previously sent links of the user stop working.
+func (s *service) sendVerificationEmail(user *store.User, email string) error {
+	token := api.NewDoubleUUIDCode()
+
+	err := s.repo.CreateEmailVerificationToken(&store.EmailVerificationToken{
+		ExpiresAt: time.Now().Add(s.conf.Account.EmailVerification.Expiration),
+		TokenHash: api.HashToken(token),
+		Email:     email,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Send email in a new thread
+	go s.mailjetClient.SendEmailVerification(s.conf.Email.VerificationTemplateID, user.Name,
+		s.conf.Email.SenderEmail, email, token)
+
+	return nil
+}
+
+// validateNewEmail checks if the email address user wants to change to is not used by another account.
+// Error response is written if email address is taken.
+func (s *service) validateNewEmail(w http.ResponseWriter, r *http.Request, response *api.BaseResponse, email string) bool {
+	_, err := s.repo.GetUserByEmail(email)
+	if err == nil {
+		response.Error(status.ErrorEmailAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return false
+	}
+
+	if err.Error() != store.UserNotFound {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
+	}
+
+	return true
+}
+
+// isEmailVerificationRequired checks if configured policy prevents the user from logging in
+// until their email address is verified.
+func (s *service) isEmailVerificationRequired(user *store.User) bool {
+	if user.EmailVerified {
+		return false
+	}
+
+	switch s.conf.Account.EmailVerification.Policy {
+	case config.EmailVerificationPolicyBlock:
+		return true
+	case config.EmailVerificationPolicyGrace:
+		return time.Since(user.CreatedAt) > s.conf.Account.EmailVerification.GracePeriod
+	default:
+		return false
+	}
+}
@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	m "github.com/adinovcina/golang-setup/api/middleware"
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/go-chi/chi/v5"
+	"github.com/twinj/uuid"
+)
+
+func AttachAuditRoutes(r chi.Router,
+	conf *config.Config,
+	repo store.Repository,
+) {
+	svc := newService(conf, repo)
+	permissions := store.GetPermissions()
+
+	// Protected REST routes for "audit" resource
+	r.Route("/audit", func(r chi.Router) {
+		r.Use(m.RequirePermission(permissions.AuditRead))
+		// Used by admin to browse the audit log
+		r.With(m.PaginationCursor(repo)).Get("/", svc.handleGetAuditEvents)
+	})
+}
+
+// handleGetAuditEvents retrieves list of audit events, optionally filtered by actor, target,
+// action and time range. Time range is given in RFC3339 format with "to" being exclusive.
+func (s *service) handleGetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	filter := &store.AuditFilter{}
+
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	query := r.URL.Query()
+
+	actorID, err := parseUserIDParam(query.Get("actorID"))
+	if err != nil {
+		response.Error(status.ErrorInvalidQueryURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	filter.ActorID = actorID
+
+	targetID, err := parseUserIDParam(query.Get("targetID"))
+	if err != nil {
+		response.Error(status.ErrorInvalidQueryURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	filter.TargetID = targetID
+
+	if action := query.Get("action"); action != "" {
+		filter.Action = &action
+	}
+
+	from, err := parseTimeParam(query.Get("from"))
+	if err != nil {
+		response.Error(status.ErrorInvalidQueryURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	filter.From = from
+
+	to, err := parseTimeParam(query.Get("to"))
+	if err != nil {
+		response.Error(status.ErrorInvalidQueryURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	filter.To = to
+
+	events, err := s.repo.GetAuditEvents(filter)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.PaginatedCursorResponse{
+		Results:    events,
+		Pagination: s.repo.PaginatorCursor(),
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// parseUserIDParam parses optional user ID query parameter.
+func parseUserIDParam(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	return uuid.Parse(value)
+}
+
+// parseTimeParam parses optional time query parameter, the time is converted to UTC the events are stored in.
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	t = t.UTC()
+
+	return &t, nil
+}
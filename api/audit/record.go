@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"net/http"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/twinj/uuid"
+)
+
+// Record appends the event to the audit log. Request ID and the client which sent the request are
+// taken from request data, as well as the actor when request is sent by logged user. While admin
+// impersonates the user, admin is the actor and the user is added to details. Failing to record
+// the event is logged and does not fail the request.
+func Record(r *http.Request, repo store.AuditRepository, event *store.AuditEvent) {
+	if requestData := api.RequestData(r); requestData != nil {
+		event.RequestID = requestData.RequestID
+		event.IPAddress = requestData.IPAddress
+		event.UserAgent = requestData.UserAgent
+
+		if event.ActorID == nil && requestData.UserID != (uuid.UUID{}) {
+			actorID := requestData.UserID
+
+			if requestData.IsImpersonated() {
+				actorID = *requestData.ImpersonatorID
+
+				details := make(map[string]string, len(event.Details)+1)
+				for key, value := range event.Details {
+					details[key] = value
+				}
+
+				details["impersonatedUserID"] = requestData.UserID.String()
+				event.Details = details
+			}
+
+			event.ActorID = &actorID
+		}
+	}
+
+	if err := repo.CreateAuditEvent(event); err != nil {
+		logger.Error().Err(err).Msgf("failed to record audit event %s, request_id: %s", event.Action, event.RequestID)
+	}
+}
@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+)
+
+type service struct {
+	conf *config.Config
+	repo store.Repository
+}
+
+func newService(conf *config.Config,
+	repo store.Repository,
+) service {
+	return service{
+		conf,
+		repo,
+	}
+}
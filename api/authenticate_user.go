@@ -38,6 +38,10 @@ func (aur *AuthenticateUserRequest) Validate(r *http.Request) (bool, *BaseRespon
 // AuthenticateUserDataResponse contains response data after login is called.
 type AuthenticateUserDataResponse struct {
 	Token string `json:"token"`
+	// MFARequired tells client that code from authenticator app must be sent to authorize
+	MFARequired bool `json:"mfaRequired"`
+	// EmailVerified tells client to remind the user to verify their email address
+	EmailVerified bool `json:"emailVerified"`
 }
 
 // ValidateEmail will check if email match to regex.
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
 	"github.com/twinj/uuid"
@@ -11,6 +12,10 @@ import (
 // AuthorizeRequest used when user send request to authorize to the app.
 type AuthorizeRequest struct {
 	Token string `json:"token"`
+	// Code from authenticator app, required if user has MFA enabled
+	Code string `json:"code,omitempty"`
+	// RecoveryCode can be used instead of Code when user has no access to authenticator app
+	RecoveryCode string `json:"recoveryCode,omitempty"`
 }
 
 // Validate AuthorizeRequest.
@@ -55,3 +60,40 @@ type LoginDataResponse struct {
 	Language string    `json:"language"`
 	UserID   uuid.UUID `json:"userID"`
 }
+
+// ImpersonateRequest used when admin starts acting as another user.
+type ImpersonateRequest struct {
+	UserID uuid.UUID `json:"userID"`
+}
+
+// Validate ImpersonateRequest.
+func (ir *ImpersonateRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(ir, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if ir.UserID == (uuid.UUID{}) {
+			response.Error(status.ErrorMissingUserID)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// ImpersonationDataResponse contains token of the session admin uses to act as the user. No refresh
+// token is issued, impersonation ends once the token expires.
+type ImpersonationDataResponse struct {
+	ExpiresAt      time.Time `json:"expiresAt"`
+	Token          string    `json:"token"`
+	Name           string    `json:"name"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+	UserID         uuid.UUID `json:"userID"`
+	ImpersonatorID uuid.UUID `json:"impersonatorID"`
+}
+
+// EndImpersonationDataResponse contains a new token of the admin session impersonation was started from.
+type EndImpersonationDataResponse struct {
+	Token  string    `json:"token"`
+	UserID uuid.UUID `json:"userID"`
+}
@@ -77,6 +77,22 @@ func SuccessResponse(response *BaseResponse, statusCode int, w http.ResponseWrit
 	jsonResponse(response, statusCode, w)
 }
 
+// RawJSONResponse writes data without BaseResponse envelope. It is used for payloads
+// whose format is defined by standards, like JWKS.
+func RawJSONResponse(data any, statusCode int, w http.ResponseWriter) {
+	marshaledData, err := json.Marshal(data)
+	if err != nil {
+		logger.Error().Err(err).Msg("marshaling response data failed")
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	writeResponseData(statusCode, marshaledData, w)
+}
+
 func jsonResponse(response *BaseResponse, statusCode int, w http.ResponseWriter) {
 	// Set content type only if there is response data
 	if statusCode != http.StatusNoContent {
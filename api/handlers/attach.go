@@ -5,12 +5,18 @@ import (
 	"net/http"
 
 	"github.com/adinovcina/golang-setup/api/account"
+	"github.com/adinovcina/golang-setup/api/audit"
 	m "github.com/adinovcina/golang-setup/api/middleware"
+	"github.com/adinovcina/golang-setup/api/oauth"
+	"github.com/adinovcina/golang-setup/api/roles"
+	"github.com/adinovcina/golang-setup/api/tasks"
+	"github.com/adinovcina/golang-setup/api/wellknown"
 	"github.com/adinovcina/golang-setup/config"
 	"github.com/adinovcina/golang-setup/services"
 	"github.com/adinovcina/golang-setup/store"
 	"github.com/adinovcina/golang-setup/tools/logger"
 	s "github.com/adinovcina/golang-setup/tools/network/http"
+	"github.com/adinovcina/golang-setup/tools/signing"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -29,27 +35,59 @@ func Attach(server *s.Server,
 	inMemRepo store.InMemRepository,
 	conf *config.Config,
 	appServices *services.AppServices,
+	keyring *signing.Keyring,
 ) *s.Server {
 	// Apply default unprotected middlewares to root api group
 	publicGroup := server.Get().Route("/", func(r chi.Router) {
-		r.Use(m.InitMiddleware)
+		r.Use(m.InitMiddleware(conf.RateLimit.TrustedProxies))
 		r.Use(m.Logger)
 	})
 
-	// Apply protected middleware to group
-	_ = publicGroup.Route("/", func(r chi.Router) {
-		r.Use(m.AuthorizeRequest(&conf.Redis, inMemRepo))
+	// Apply protected middleware to group, users have to accept the current terms of service first
+	protectedGroup := publicGroup.Route("/", func(r chi.Router) {
+		r.Use(m.AuthorizeRequest(keyring, inMemRepo, repo))
+		r.Use(m.RequireTermsAccepted(repo))
 	})
 
 	// Health check route.
 	publicGroup.Get("/health", healthCheck)
 
+	// Attach routes with public metadata used by other services.
+	wellknown.AttachWellKnownRoutes(publicGroup, conf, keyring)
+
 	// Attach Account Routes.
 	account.AttachAccountRoutes(publicGroup,
 		conf,
 		repo,
 		inMemRepo,
-		appServices.GetMailjetClient())
+		appServices.GetMailjetClient(),
+		appServices.GetPasswordPolicy(),
+		appServices.GetPasswordHasher(),
+		appServices.GetLockoutPolicy(),
+		keyring)
+
+	// Attach OAuth2 / OpenID Connect Routes.
+	oauth.AttachOAuthRoutes(publicGroup,
+		conf,
+		repo,
+		inMemRepo,
+		keyring)
+
+	// Attach Task Routes.
+	tasks.AttachTaskRoutes(protectedGroup,
+		conf,
+		repo)
+
+	// Attach Role Routes.
+	roles.AttachRoleRoutes(protectedGroup,
+		conf,
+		repo,
+		inMemRepo)
+
+	// Attach Audit Routes.
+	audit.AttachAuditRoutes(protectedGroup,
+		conf,
+		repo)
 
 	return server
 }
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+)
+
+// MFACodeRequest used when user confirms MFA operation with the code from authenticator app
+// or with one of the recovery codes.
+type MFACodeRequest struct {
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recoveryCode,omitempty"`
+}
+
+// Validate MFACodeRequest.
+func (mcr *MFACodeRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(mcr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if strings.TrimSpace(mcr.Code) == "" && strings.TrimSpace(mcr.RecoveryCode) == "" {
+			response.Error(status.ErrorMissingMFACode)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// MFAEnrollDataResponse contains secret and provisioning URI which is rendered as QR code.
+type MFAEnrollDataResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningURI"`
+}
+
+// MFARecoveryCodesDataResponse contains recovery codes. Codes are returned only once.
+type MFARecoveryCodesDataResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
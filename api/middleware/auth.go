@@ -6,19 +6,49 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/adinovcina/golang-setup/api"
-	"github.com/adinovcina/golang-setup/config"
 	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/adinovcina/golang-setup/tools/signing"
+	"github.com/adinovcina/golang-setup/tools/utils"
 	jwt "github.com/golang-jwt/jwt"
 	"github.com/twinj/uuid"
 )
 
+// accessTokenTouchInterval limits how often last used time of personal access token is written.
+const accessTokenTouchInterval = time.Minute
+
 type sessionFetcher interface {
 	GetSession(ctx context.Context, uid uuid.UUID, sid string) (string, error)
+	TouchSession(ctx context.Context, uid uuid.UUID, sid string, seenAt time.Time) error
+}
+
+type accessTokenFetcher interface {
+	GetPersonalAccessTokenByHash(tokenHash string) (*store.PersonalAccessToken, error)
+	GetRoleByID(id int64) (*store.Role, error)
+	TouchPersonalAccessToken(id int64, usedAt time.Time) error
+}
+
+// AuthorizeRequest authorizes first-party requests made with session tokens or personal access tokens.
+// Tokens issued to OAuth clients are rejected, since they are limited to the scope user granted to the client.
+func AuthorizeRequest(keyring *signing.Keyring, inMemRepo sessionFetcher,
+	repo accessTokenFetcher,
+) func(http.Handler) http.Handler {
+	return authorizeRequest(keyring, inMemRepo, repo, false)
 }
 
-func AuthorizeRequest(conf *config.Redis, inMemRepo sessionFetcher) func(http.Handler) http.Handler {
+// AuthorizeOAuthRequest authorizes requests made with tokens issued to OAuth clients as well.
+func AuthorizeOAuthRequest(keyring *signing.Keyring, inMemRepo sessionFetcher,
+	repo accessTokenFetcher,
+) func(http.Handler) http.Handler {
+	return authorizeRequest(keyring, inMemRepo, repo, true)
+}
+
+func authorizeRequest(keyring *signing.Keyring, inMemRepo sessionFetcher, repo accessTokenFetcher,
+	allowScoped bool,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get Request data object
@@ -34,16 +64,21 @@ func AuthorizeRequest(conf *config.Redis, inMemRepo sessionFetcher) func(http.Ha
 				return
 			}
 
-			// 1. Validate the JWT token
-			token, tokenErr := jwt.ParseWithClaims(bearerToken, &api.Claim{},
-				func(token *jwt.Token) (interface{}, error) {
-					// Make sure token's signature wasn't changed
-					if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-						return nil, errors.New("unexpected signing method")
-					}
+			// Personal access tokens are not JWTs, they are looked up by their hash
+			if api.IsPersonalAccessToken(bearerToken) {
+				if !authorizeAccessToken(repo, bearerToken, data) {
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+					return
+				}
+
+				ctx := api.NewContextWithMiddlewareData(r.Context(), data)
+				next.ServeHTTP(w, r.WithContext(ctx))
 
-					return []byte(conf.SecretKey), nil
-				})
+				return
+			}
+
+			// 1. Validate the JWT token
+			token, tokenErr := jwt.ParseWithClaims(bearerToken, &api.Claim{}, keyring.Keyfunc)
 
 			if tokenErr != nil || !token.Valid {
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
@@ -69,12 +104,28 @@ func AuthorizeRequest(conf *config.Redis, inMemRepo sessionFetcher) func(http.Ha
 				return
 			}
 
+			// 5. Check if session created for OAuth client can be used
+			if userData.Scope != "" && !allowScoped {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
 			data.UserID = userData.UserID
 			data.Email = userData.Email
 			data.Active = userData.Active
 			data.Role = userData.Role
 			data.UserRoleID = userData.UserRoleID
 			data.SessionKey = userData.SessionKey
+			data.SessionID = claim.SessionID
+			data.Scope = userData.Scope
+			data.Permissions = userData.Permissions
+			data.ImpersonatorID = userData.ImpersonatorID
+			data.ImpersonatorSessionID = userData.ImpersonatorSessionID
+
+			// 6. Keep track of the last time session was used
+			if err := inMemRepo.TouchSession(r.Context(), claim.UserID, claim.SessionID, time.Now().UTC()); err != nil {
+				logger.Warn().Err(err).Msgf("failed to update last seen time of session %v", claim.SessionID)
+			}
 
 			// Create new context and pass new updated data
 			ctx := api.NewContextWithMiddlewareData(r.Context(), data)
@@ -83,27 +134,80 @@ func AuthorizeRequest(conf *config.Redis, inMemRepo sessionFetcher) func(http.Ha
 	}
 }
 
-func CheckAllowedRoles(roles ...store.Role) func(http.Handler) http.Handler {
+// authorizeAccessToken fills request data from personal access token. Token is granted only the scopes
+// its role still grants, so permissions removed from the role are removed from the token as well.
+func authorizeAccessToken(repo accessTokenFetcher, bearerToken string, data *api.Data) bool {
+	token, err := repo.GetPersonalAccessTokenByHash(api.HashToken(bearerToken))
+	if err != nil {
+		if err.Error() != store.PersonalAccessTokenNotFound {
+			logger.Error().Err(err).Msg("failed to fetch personal access token")
+		}
+
+		return false
+	}
+
+	role, err := repo.GetRoleByID(token.RoleID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to fetch role of personal access token %v", token.ID)
+
+		return false
+	}
+
+	permissions := make([]string, 0, len(token.Scopes))
+
+	for _, scope := range token.Scopes {
+		if utils.Contains(role.Permissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	data.UserID = token.UserID
+	data.Active = true
+	data.Role = token.Role
+	data.UserRoleID = token.RoleID
+	data.Permissions = permissions
+	data.AccessTokenID = token.ID
+
+	// Keep track of the last time token was used, without writing on every request
+	now := time.Now().UTC()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenTouchInterval {
+		if err := repo.TouchPersonalAccessToken(token.ID, now); err != nil {
+			logger.Warn().Err(err).Msgf("failed to update last used time of personal access token %v", token.ID)
+		}
+	}
+
+	return true
+}
+
+// RequireOwnSession rejects requests authorized with personal access token or with session admin created
+// to impersonate the user. It guards routes which manage the account itself, so neither leaked token nor
+// admin can take the account over.
+func RequireOwnSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := api.RequestData(r)
+		if data.AccessTokenID != 0 || data.IsImpersonated() {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePermission allows the request only if the role user is authorized with grants all the permissions.
+func RequirePermission(permissions ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			data := api.RequestData(r)
-			hasRole := false
 
-			for _, role := range roles {
-				if role.Name == data.Role {
-					hasRole = true
-					break
+			for _, permission := range permissions {
+				if !data.HasPermission(permission) {
+					http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+					return
 				}
 			}
 
-			if !hasRole {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-				return
-			}
-
-			// Create new context and pass new updated data
-			ctx := api.NewContextWithMiddlewareData(r.Context(), data)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r)
 		})
 	}
 }
@@ -2,72 +2,63 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/adinovcina/golang-setup/api"
-	"github.com/adinovcina/golang-setup/config"
 	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/signing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/twinj/uuid"
 )
 
-func TestCheckAllowedRoles(t *testing.T) {
-	// Define some sample roles
-	adminRole := store.Role{Name: "Admin"}
-	userRole := store.Role{Name: "User"}
+func TestRequirePermission(t *testing.T) {
+	permissions := store.GetPermissions()
 
 	// Create a test handler that always returns OK
 	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	// Define test cases
 	tests := []struct {
-		name           string
-		roles          []store.Role
-		requestRole    string
-		expectedStatus int
+		name            string
+		required        []string
+		userPermissions []string
+		expectedStatus  int
 	}{
 		{
-			name:           "Allowed Role",
-			roles:          []store.Role{adminRole, userRole},
-			requestRole:    "Admin",
-			expectedStatus: http.StatusOK,
+			name:            "Permission Granted",
+			required:        []string{permissions.UsersRead},
+			userPermissions: []string{permissions.UsersRead, permissions.UsersActivate},
+			expectedStatus:  http.StatusOK,
 		},
 		{
-			name:           "Denied Role",
-			roles:          []store.Role{adminRole},
-			requestRole:    "User",
-			expectedStatus: http.StatusUnauthorized,
+			name:            "One Of Permissions Missing",
+			required:        []string{permissions.UsersRead, permissions.UsersActivate},
+			userPermissions: []string{permissions.UsersRead},
+			expectedStatus:  http.StatusForbidden,
 		},
 		{
-			name:           "No Roles Provided",
-			roles:          nil,
-			requestRole:    "User",
-			expectedStatus: http.StatusUnauthorized,
+			name:            "No Permissions In Session",
+			required:        []string{permissions.RolesManage},
+			userPermissions: nil,
+			expectedStatus:  http.StatusForbidden,
 		},
 	}
 
-	// Run tests
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			// Create a new request with the specified role
 			req := httptest.NewRequest(http.MethodGet, "/", nil)
-			req = req.WithContext(api.NewContextWithMiddlewareData(req.Context(), &api.Data{Role: tc.requestRole}))
+			req = req.WithContext(api.NewContextWithMiddlewareData(req.Context(), &api.Data{Permissions: tc.userPermissions}))
 
-			// Create a new recorder to record the response
 			rr := httptest.NewRecorder()
 
-			// Create a middleware with the specified roles
-			middleware := CheckAllowedRoles(tc.roles...)
+			RequirePermission(tc.required...)(testHandler).ServeHTTP(rr, req)
 
-			// Execute the middleware chain with the test handler
-			middleware(testHandler).ServeHTTP(rr, req)
-
-			// Check if the status code matches the expected status code
 			assert.Equal(t, tc.expectedStatus, rr.Code)
 		})
 	}
@@ -80,6 +71,10 @@ func (m *mockSessionFetcher) GetSession(ctx context.Context, uid uuid.UUID, sid
 	return `{"userID": "0a15f901-55a7-4dac-b1ae-c602fb775bd1", "email": "admin@gmail.com", "active": true, "role": "Admin", "userRoleID": 1, "sessionKey": "sessionKey"}`, nil
 }
 
+func (m *mockSessionFetcher) TouchSession(ctx context.Context, uid uuid.UUID, sid string, seenAt time.Time) error {
+	return nil
+}
+
 func TestAuthorizeRequest(t *testing.T) {
 	// Define test cases
 	testCases := []struct {
@@ -107,10 +102,12 @@ func TestAuthorizeRequest(t *testing.T) {
 	// Iterate over test cases
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			// Prepare a mock Redis configuration
-			mockConf := &config.Redis{
-				SecretKey: "test",
-			}
+			// Prepare a keyring signing tokens with shared secret
+			keyring, err := signing.NewKeyring(signing.Options{
+				Algorithm: signing.AlgorithmHS256,
+				Secret:    "test",
+			})
+			require.NoError(t, err)
 
 			// Create a mock session fetcher
 			mockSession := &mockSessionFetcher{}
@@ -154,10 +151,192 @@ func TestAuthorizeRequest(t *testing.T) {
 			rr := httptest.NewRecorder()
 
 			// Call the middleware with the mock session fetcher
-			AuthorizeRequest(mockConf, mockSession)(mockHandler).ServeHTTP(rr, req)
+			AuthorizeRequest(keyring, mockSession, &mockAccessTokenFetcher{})(mockHandler).ServeHTTP(rr, req)
 
 			// Check the status code
 			assert.Equal(t, tc.expected, rr.Code)
 		})
 	}
 }
+
+type mockScopedSessionFetcher struct {
+	mockSessionFetcher
+}
+
+func (m *mockScopedSessionFetcher) GetSession(ctx context.Context, uid uuid.UUID, sid string) (string, error) {
+	// Mock session created for OAuth client
+	return `{"userID": "0a15f901-55a7-4dac-b1ae-c602fb775bd1", "email": "admin@gmail.com", "active": true, "role": "Admin", "userRoleID": 1, "sessionKey": "sessionKey", "scope": "openid email"}`, nil
+}
+
+func TestAuthorizeRequestScopedSession(t *testing.T) {
+	keyring, err := signing.NewKeyring(signing.Options{
+		Algorithm: signing.AlgorithmHS256,
+		Secret:    "test",
+	})
+	require.NoError(t, err)
+
+	userID, _ := uuid.Parse("0a15f901-55a7-4dac-b1ae-c602fb775bd1")
+
+	claim := &api.Claim{UserID: *userID, SessionID: "-NxmwJUswoID7LWCqLNC"}
+	token, err := claim.CreateToken(time.Hour, keyring)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		expected   int
+	}{
+		{
+			name:       "FirstPartyRoute",
+			middleware: AuthorizeRequest(keyring, &mockScopedSessionFetcher{}, &mockAccessTokenFetcher{}),
+			expected:   http.StatusForbidden,
+		},
+		{
+			name:       "OAuthRoute",
+			middleware: AuthorizeOAuthRequest(keyring, &mockScopedSessionFetcher{}, &mockAccessTokenFetcher{}),
+			expected:   http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "openid email", api.MiddlewareDataFromContext(r.Context()).Scope)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req = req.WithContext(api.NewContextWithMiddlewareData(req.Context(), &api.Data{}))
+
+			rr := httptest.NewRecorder()
+			tc.middleware(mockHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expected, rr.Code)
+		})
+	}
+}
+
+type mockAccessTokenFetcher struct {
+	touched bool
+}
+
+func (m *mockAccessTokenFetcher) GetPersonalAccessTokenByHash(tokenHash string) (*store.PersonalAccessToken, error) {
+	if tokenHash != api.HashToken("pat_valid") {
+		return nil, errors.New(store.PersonalAccessTokenNotFound)
+	}
+
+	userID, _ := uuid.Parse("0a15f901-55a7-4dac-b1ae-c602fb775bd1")
+
+	return &store.PersonalAccessToken{
+		ID:     1,
+		UserID: *userID,
+		RoleID: 1,
+		Role:   "Admin",
+		Scopes: []string{store.GetPermissions().UsersRead, store.GetPermissions().RolesManage},
+	}, nil
+}
+
+func (m *mockAccessTokenFetcher) GetRoleByID(id int64) (*store.Role, error) {
+	// Role no longer grants roles:manage, so the token loses it as well
+	return &store.Role{ID: id, Name: "Admin", Permissions: []string{store.GetPermissions().UsersRead}}, nil
+}
+
+func (m *mockAccessTokenFetcher) TouchPersonalAccessToken(id int64, usedAt time.Time) error {
+	m.touched = true
+
+	return nil
+}
+
+func TestAuthorizeRequestPersonalAccessToken(t *testing.T) {
+	keyring, err := signing.NewKeyring(signing.Options{
+		Algorithm: signing.AlgorithmHS256,
+		Secret:    "test",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		token    string
+		expected int
+	}{
+		{
+			name:     "ValidToken",
+			token:    "pat_valid",
+			expected: http.StatusOK,
+		},
+		{
+			name:     "UnknownToken",
+			token:    "pat_unknown",
+			expected: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fetcher := &mockAccessTokenFetcher{}
+
+			mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data := api.MiddlewareDataFromContext(r.Context())
+				assert.Equal(t, int64(1), data.AccessTokenID)
+				assert.Equal(t, []string{store.GetPermissions().UsersRead}, data.Permissions)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			req = req.WithContext(api.NewContextWithMiddlewareData(req.Context(), &api.Data{}))
+
+			rr := httptest.NewRecorder()
+			AuthorizeRequest(keyring, &mockSessionFetcher{}, fetcher)(mockHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expected, rr.Code)
+			assert.Equal(t, tc.expected == http.StatusOK, fetcher.touched)
+		})
+	}
+}
+
+func TestRequireOwnSession(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	adminID := uuid.NewV4()
+
+	tests := []struct {
+		name           string
+		data           *api.Data
+		expectedStatus int
+	}{
+		{
+			name:           "Session",
+			data:           &api.Data{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Personal Access Token",
+			data:           &api.Data{AccessTokenID: 1},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Impersonated Session",
+			data:           &api.Data{ImpersonatorID: &adminID},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(api.NewContextWithMiddlewareData(req.Context(), tc.data))
+
+			rr := httptest.NewRecorder()
+
+			RequireOwnSession(testHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/utils"
+)
+
+// rateLimitMaxBodySize limits how much of the request body is read to find the email address.
+const rateLimitMaxBodySize = 1 << 20
+
+// rateLimit is the limit applied to the key within the window.
+type rateLimit struct {
+	key   string
+	limit int
+}
+
+type rateLimiter interface {
+	RateLimit(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+}
+
+// RateLimit limits requests sent to the route from the same IP address, and with byEmail for the same email
+// address when the request body contains one. Email limit is only meant for recovery routes which send emails,
+// since anyone could use it to block login of the victim. Proxy headers are only used to find the IP address of requests sent by
+// trusted proxies. Rejected requests receive 429 with Retry-After header. Requests are allowed if the limiter
+// is not available, so the login does not depend on it.
+func RateLimit(limiter rateLimiter, conf config.RateLimit, byEmail bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !conf.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data := api.RequestData(r)
+
+			response := &api.BaseResponse{}
+			response.RequestID = data.RequestID
+
+			limits := []rateLimit{
+				{key: utils.FormatRateLimitKey(r.URL.Path, "ip", utils.TrustedClientIP(r, conf.TrustedProxies)), limit: conf.IPLimit},
+			}
+
+			if byEmail {
+				if email := requestEmail(r); email != "" {
+					limits = append(limits,
+						rateLimit{key: utils.FormatRateLimitKey(r.URL.Path, "email", email), limit: conf.EmailLimit})
+				}
+			}
+
+			for _, l := range limits {
+				allowed, retryAfter, err := limiter.RateLimit(r.Context(), l.key, l.limit, conf.Window)
+				if err != nil {
+					logger.Error().Err(err).Msgf("rate limit check failed, request_id: %s", data.RequestID)
+
+					continue
+				}
+
+				if !allowed {
+					w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds())))))
+
+					response.Error(status.ErrorTooManyRequests)
+					api.ErrorResponse(response, http.StatusTooManyRequests, w, r, nil)
+
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestEmail returns lowercased email address from JSON request body. Body is restored, so it can
+// be read again by the handler.
+func requestEmail(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, rateLimitMaxBodySize))
+	if err != nil {
+		return ""
+	}
+
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+	var request struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.Unmarshal(body, &request); err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(request.Email))
+}
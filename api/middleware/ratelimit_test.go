@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/config"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRateLimiter struct {
+	// rejected contains keys which reached the limit
+	rejected map[string]bool
+	keys     []string
+	err      error
+}
+
+func (m *mockRateLimiter) RateLimit(_ context.Context, key string, _ int, _ time.Duration) (bool, time.Duration, error) {
+	m.keys = append(m.keys, key)
+
+	if m.err != nil {
+		return false, 0, m.err
+	}
+
+	if m.rejected[key] {
+		return false, 1500 * time.Millisecond, nil
+	}
+
+	return true, 0, nil
+}
+
+func TestRateLimit(t *testing.T) {
+	conf := config.RateLimit{Enabled: true, Window: time.Minute, IPLimit: 10, EmailLimit: 5}
+
+	tests := []struct {
+		name               string
+		conf               config.RateLimit
+		byEmail            bool
+		limiter            *mockRateLimiter
+		body               string
+		headers            map[string]string
+		expectedStatus     int
+		expectedKeys       []string
+		expectedRetryAfter string
+	}{
+		{
+			name:           "Allowed",
+			conf:           conf,
+			byEmail:        true,
+			limiter:        &mockRateLimiter{},
+			body:           `{"email": " User@Example.com ", "password": "secret"}`,
+			expectedStatus: http.StatusOK,
+			expectedKeys: []string{
+				"ratelimit:/account/authenticate:ip:127.0.0.1",
+				"ratelimit:/account/authenticate:email:user@example.com",
+			},
+		},
+		{
+			name:           "Allowed - Login route not limited per email",
+			conf:           conf,
+			limiter:        &mockRateLimiter{},
+			body:           `{"email": "user@example.com", "password": "secret"}`,
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"ratelimit:/account/authenticate:ip:127.0.0.1"},
+		},
+		{
+			name:           "Allowed - No email in body",
+			conf:           conf,
+			limiter:        &mockRateLimiter{},
+			body:           `{"token": "token"}`,
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"ratelimit:/account/authenticate:ip:127.0.0.1"},
+		},
+		{
+			name: "Rejected - IP limit reached",
+			conf: conf,
+			limiter: &mockRateLimiter{rejected: map[string]bool{
+				"ratelimit:/account/authenticate:ip:127.0.0.1": true,
+			}},
+			body:               `{"email": "user@example.com"}`,
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedKeys:       []string{"ratelimit:/account/authenticate:ip:127.0.0.1"},
+			expectedRetryAfter: "2",
+		},
+		{
+			name:    "Rejected - Email limit reached",
+			conf:    conf,
+			byEmail: true,
+			limiter: &mockRateLimiter{rejected: map[string]bool{
+				"ratelimit:/account/authenticate:email:user@example.com": true,
+			}},
+			body:           `{"email": "user@example.com"}`,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedKeys: []string{
+				"ratelimit:/account/authenticate:ip:127.0.0.1",
+				"ratelimit:/account/authenticate:email:user@example.com",
+			},
+			expectedRetryAfter: "2",
+		},
+		{
+			name:           "Allowed - Limiter not available",
+			conf:           conf,
+			byEmail:        true,
+			limiter:        &mockRateLimiter{err: errors.New("connection refused")},
+			body:           `{"email": "user@example.com"}`,
+			expectedStatus: http.StatusOK,
+			expectedKeys: []string{
+				"ratelimit:/account/authenticate:ip:127.0.0.1",
+				"ratelimit:/account/authenticate:email:user@example.com",
+			},
+		},
+		{
+			name:           "Allowed - Forwarded header of untrusted client ignored",
+			conf:           conf,
+			limiter:        &mockRateLimiter{},
+			body:           `{"token": "token"}`,
+			headers:        map[string]string{"X-Forwarded-For": "203.0.113.5", "X-Real-IP": "203.0.113.6"},
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"ratelimit:/account/authenticate:ip:127.0.0.1"},
+		},
+		{
+			name: "Allowed - Forwarded header of trusted proxy",
+			conf: config.RateLimit{
+				Enabled: true, Window: time.Minute, IPLimit: 10, EmailLimit: 5,
+				TrustedProxies: []*net.IPNet{{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)}},
+			},
+			limiter:        &mockRateLimiter{},
+			body:           `{"token": "token"}`,
+			headers:        map[string]string{"X-Forwarded-For": "203.0.113.5"},
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"ratelimit:/account/authenticate:ip:203.0.113.5"},
+		},
+		{
+			name:           "Disabled",
+			conf:           config.RateLimit{},
+			limiter:        &mockRateLimiter{},
+			body:           `{"email": "user@example.com"}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var handlerBody string
+
+			// Create a test handler which checks the body can still be read
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				handlerBody = string(body)
+
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/account/authenticate", strings.NewReader(tc.body))
+			req.RemoteAddr = "127.0.0.1:51234"
+			req = req.WithContext(api.NewContextWithMiddlewareData(req.Context(), &api.Data{IPAddress: "127.0.0.1"}))
+
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			rr := httptest.NewRecorder()
+
+			RateLimit(tc.limiter, tc.conf, tc.byEmail)(testHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			assert.Equal(t, tc.expectedKeys, tc.limiter.keys)
+
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, tc.body, handlerBody)
+
+				return
+			}
+
+			assert.Equal(t, tc.expectedRetryAfter, rr.Header().Get("Retry-After"))
+
+			response := new(api.BaseResponse)
+
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), response))
+			assert.Len(t, response.Errors, 1)
+			assert.Equal(t, status.ErrorTooManyRequests, response.Errors[0].Code)
+		})
+	}
+}
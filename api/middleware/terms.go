@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/twinj/uuid"
+)
+
+type termsAcceptanceFetcher interface {
+	GetUserTermsAcceptance(userID uuid.UUID) (*store.TermsAcceptance, error)
+}
+
+// RequireTermsAccepted allows the request only if logged user accepted the current version of terms
+// of service. Requests are allowed while no version is published.
+func RequireTermsAccepted(repo termsAcceptanceFetcher) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data := api.RequestData(r)
+
+			response := &api.BaseResponse{}
+			response.RequestID = data.RequestID
+
+			acceptance, err := repo.GetUserTermsAcceptance(data.UserID)
+			if err != nil && err.Error() == store.TermsNotFound {
+				next.ServeHTTP(w, r)
+				return
+			} else if err != nil {
+				api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+				return
+			}
+
+			if !acceptance.Accepted() {
+				response.Error(status.ErrorTermsNotAccepted)
+				api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
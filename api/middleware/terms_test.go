@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/stretchr/testify/assert"
+	"github.com/twinj/uuid"
+)
+
+type mockTermsAcceptanceFetcher struct {
+	acceptance *store.TermsAcceptance
+	err        error
+}
+
+func (m *mockTermsAcceptanceFetcher) GetUserTermsAcceptance(userID uuid.UUID) (*store.TermsAcceptance, error) {
+	return m.acceptance, m.err
+}
+
+func TestRequireTermsAccepted(t *testing.T) {
+	acceptedAt := time.Now()
+
+	// Create a test handler that always returns OK
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		fetcher        *mockTermsAcceptanceFetcher
+		expectedStatus int
+		expectedCode   int
+	}{
+		{
+			name: "Current Version Accepted",
+			fetcher: &mockTermsAcceptanceFetcher{
+				acceptance: &store.TermsAcceptance{TermsID: 1, Version: "1.0", AcceptedAt: &acceptedAt},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Current Version Not Accepted",
+			fetcher: &mockTermsAcceptanceFetcher{
+				acceptance: &store.TermsAcceptance{TermsID: 2, Version: "2.0"},
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   status.ErrorTermsNotAccepted,
+		},
+		{
+			name:           "No Terms Published",
+			fetcher:        &mockTermsAcceptanceFetcher{err: errors.New(store.TermsNotFound)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Repository Error",
+			fetcher:        &mockTermsAcceptanceFetcher{err: errors.New("connection refused")},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(api.NewContextWithMiddlewareData(req.Context(), &api.Data{UserID: uuid.NewV4()}))
+
+			rr := httptest.NewRecorder()
+
+			RequireTermsAccepted(tc.fetcher)(testHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+
+			if tc.expectedCode != 0 {
+				response := new(api.BaseResponse)
+
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), response))
+				assert.Len(t, response.Errors, 1)
+				assert.Equal(t, tc.expectedCode, response.Errors[0].Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/store"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/utils"
+	jwt "github.com/golang-jwt/jwt"
+)
+
+// OAuth2 error codes defined by RFC 6749.
+const (
+	OAuthErrorInvalidRequest          = "invalid_request"
+	OAuthErrorInvalidClient           = "invalid_client"
+	OAuthErrorInvalidGrant            = "invalid_grant"
+	OAuthErrorUnauthorizedClient      = "unauthorized_client"
+	OAuthErrorUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrorUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrorInvalidScope            = "invalid_scope"
+	OAuthErrorAccessDenied            = "access_denied"
+	OAuthErrorInsufficientScope       = "insufficient_scope"
+	OAuthErrorServerError             = "server_error"
+)
+
+// OpenID Connect scopes.
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// CreateOAuthClientRequest used when admin registers a new OAuth client.
+type CreateOAuthClientRequest struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirectURIs"`
+	GrantTypes   []string `json:"grantTypes"`
+	Scopes       []string `json:"scopes"`
+	// Confidential clients get a secret, public clients (SPA, mobile) must use PKCE only
+	Confidential bool `json:"confidential"`
+}
+
+// Validate CreateOAuthClientRequest.
+func (ocr *CreateOAuthClientRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(ocr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+		grantTypes := store.GetGrantTypes()
+
+		// Validate body params
+		if strings.TrimSpace(ocr.Name) == "" {
+			response.Error(status.ErrorMissingName)
+		}
+
+		if len(ocr.GrantTypes) == 0 {
+			response.Error(status.ErrorInvalidGrantType)
+		}
+
+		for _, grantType := range ocr.GrantTypes {
+			// Client credentials can be used only by clients which can keep the secret
+			if grantType != grantTypes.AuthorizationCode &&
+				(grantType != grantTypes.ClientCredentials || !ocr.Confidential) {
+				response.Error(status.ErrorInvalidGrantType)
+			}
+		}
+
+		if utils.Contains(ocr.GrantTypes, grantTypes.AuthorizationCode) && len(ocr.RedirectURIs) == 0 {
+			response.Error(status.ErrorMissingRedirectURI)
+		}
+
+		for _, redirectURI := range ocr.RedirectURIs {
+			if !validateRedirectURI(redirectURI) {
+				response.Error(status.ErrorInvalidRedirectURI)
+			}
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// OAuthClientDataResponse contains registered client. Secret is returned only once.
+type OAuthClientDataResponse struct {
+	*store.OAuthClient
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+// OAuthAuthorizeDataResponse contains URI where user is redirected back to the client.
+type OAuthAuthorizeDataResponse struct {
+	RedirectURI string `json:"redirectURI"`
+}
+
+// OAuthTokenResponse is token endpoint response defined by RFC 6749.
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// OAuthErrorResponse is error response defined by RFC 6749.
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// IDTokenClaim contains claims of OpenID Connect ID token.
+type IDTokenClaim struct {
+	jwt.StandardClaims
+	Nonce string `json:"nonce,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// UserInfoResponse contains claims about logged user, filtered by granted scope.
+type UserInfoResponse struct {
+	Subject string `json:"sub"`
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Locale  string `json:"locale,omitempty"`
+}
+
+// OpenIDConfiguration is OpenID Connect discovery document.
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// validateRedirectURI checks if redirect URI is absolute URL without fragment.
+func validateRedirectURI(redirectURI string) bool {
+	u, err := url.Parse(redirectURI)
+
+	return err == nil && u.Scheme != "" && u.Host != "" && u.Fragment == "" && !strings.ContainsAny(redirectURI, " ")
+}
@@ -0,0 +1,91 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/store"
+)
+
+const (
+	codeChallengeMethodS256 = "S256"
+	// authorizationCodeLength - length of generated authorization code in bytes.
+	authorizationCodeLength = 32
+	// Code verifier length limits defined by RFC 7636.
+	codeVerifierMinLength = 43
+	codeVerifierMaxLength = 128
+)
+
+// createAuthorizationCode generates authorization code and persists its hash.
+func (s *service) createAuthorizationCode(authorizationCode *store.AuthorizationCode) (string, error) {
+	code := make([]byte, authorizationCodeLength)
+	if _, err := rand.Read(code); err != nil {
+		return "", err
+	}
+
+	encodedCode := base64.RawURLEncoding.EncodeToString(code)
+
+	authorizationCode.CodeHash = hashCode(encodedCode)
+	authorizationCode.ExpiresAt = time.Now().Add(s.conf.OAuth.AuthorizationCodeExpiration)
+
+	if err := s.repo.CreateAuthorizationCode(authorizationCode); err != nil {
+		return "", err
+	}
+
+	return encodedCode, nil
+}
+
+// hashCode returns SHA-256 hash of authorization code. Codes are random, so there is no need for slow hash.
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyCodeChallenge checks PKCE code verifier against S256 code challenge (RFC 7636).
+func verifyCodeChallenge(codeVerifier, codeChallenge string) bool {
+	if len(codeVerifier) < codeVerifierMinLength || len(codeVerifier) > codeVerifierMaxLength {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(codeVerifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(codeChallenge)) == 1
+}
+
+// appendQuery adds params to the redirect URI, keeping params which are already part of it.
+func appendQuery(redirectURI string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(redirectURI, "?") {
+		separator = "&"
+	}
+
+	return redirectURI + separator + params.Encode()
+}
+
+// tokenSuccessResponse writes token response. Tokens must not be cached.
+func tokenSuccessResponse(w http.ResponseWriter, tokenResponse api.OAuthTokenResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	api.RawJSONResponse(tokenResponse, http.StatusOK, w)
+}
+
+// oauthErrorResponse writes error response in format defined by RFC 6749.
+func oauthErrorResponse(w http.ResponseWriter, statusCode int, errorCode, description string) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	api.RawJSONResponse(api.OAuthErrorResponse{
+		Error:            errorCode,
+		ErrorDescription: description,
+	}, statusCode, w)
+}
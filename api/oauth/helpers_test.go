@@ -0,0 +1,64 @@
+package oauth
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	t.Parallel()
+
+	// RFC 7636 Appendix B example
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		want      bool
+	}{
+		{"valid_verifier", verifier, challenge, true},
+		{"invalid_verifier", verifier + "x", challenge, false},
+		{"plain_verifier", challenge, challenge, false},
+		{"short_verifier", "abc", "ungWv48Bz-pBQUDeXa4iI7ADYaOWF3qctBD_YfIAFa0", false},
+		{"empty_verifier", "", challenge, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := verifyCodeChallenge(tt.verifier, tt.challenge); got != tt.want {
+				t.Errorf("verifyCodeChallenge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendQuery(t *testing.T) {
+	t.Parallel()
+
+	params := url.Values{"code": {"abc"}, "state": {"xyz"}}
+
+	tests := []struct {
+		name        string
+		redirectURI string
+		want        string
+	}{
+		{"without_query", "https://app.example.com/callback", "https://app.example.com/callback?code=abc&state=xyz"},
+		{"with_query", "https://app.example.com/callback?tenant=1", "https://app.example.com/callback?tenant=1&code=abc&state=xyz"},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := appendQuery(tt.redirectURI, params); got != tt.want {
+				t.Errorf("appendQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
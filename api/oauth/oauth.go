@@ -0,0 +1,473 @@
+package oauth
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adinovcina/golang-setup/api"
+	m "github.com/adinovcina/golang-setup/api/middleware"
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/encryption"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/signing"
+	"github.com/adinovcina/golang-setup/tools/utils"
+	"github.com/go-chi/chi/v5"
+	jwt "github.com/golang-jwt/jwt"
+)
+
+func AttachOAuthRoutes(r chi.Router,
+	conf *config.Config,
+	repo store.Repository,
+	inMemRepo store.InMemRepository,
+	keyring *signing.Keyring,
+) {
+	svc := newService(conf, repo, inMemRepo, keyring)
+
+	// REST routes for OAuth2 authorization server
+	r.Route("/oauth", func(r chi.Router) {
+		// Used by clients to exchange authorization code or client credentials for tokens
+		r.Post("/token", svc.handleToken)
+
+		r.Group(func(r chi.Router) {
+			// Private API group
+			r.Use(m.AuthorizeRequest(keyring, inMemRepo, repo))
+
+			// Used by login page on behalf of logged user to authorize the client
+			r.With(m.RequireOwnSession).Get("/authorize", svc.handleAuthorize)
+
+			r.Group(func(r chi.Router) {
+				r.Use(m.RequirePermission(store.GetPermissions().ClientsManage))
+				// Used by admin to register a new client
+				r.Post("/clients", svc.handleCreateClient)
+				// Used by admin to remove the client
+				r.Delete("/clients/{id}", svc.handleDeleteClient)
+			})
+		})
+	})
+
+	// Used by clients to fetch claims about the user token was issued for
+	r.With(m.AuthorizeOAuthRequest(keyring, inMemRepo, repo)).Get("/userinfo", svc.handleUserInfo)
+}
+
+// handleCreateClient registers a new OAuth client. Secret of confidential client is returned only once.
+func (s *service) handleCreateClient(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.CreateOAuthClientRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	client := &store.OAuthClient{
+		ID:           strings.ReplaceAll(utils.GenerateUniqueID(), "-", ""),
+		Name:         strings.TrimSpace(request.Name),
+		RedirectURIs: request.RedirectURIs,
+		GrantTypes:   request.GrantTypes,
+		Scopes:       request.Scopes,
+	}
+
+	if len(client.Scopes) == 0 {
+		client.Scopes = []string{api.ScopeOpenID, api.ScopeProfile, api.ScopeEmail}
+	}
+
+	var clientSecret string
+
+	if request.Confidential {
+		clientSecret = api.NewDoubleUUIDCode()
+
+		secretHash, err := encryption.Encrypt(clientSecret)
+		if err != nil {
+			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+			return
+		}
+
+		client.SecretHash = secretHash
+	}
+
+	createdClient, err := s.repo.CreateOAuthClient(client, requestData.UserID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = api.OAuthClientDataResponse{
+		OAuthClient:  createdClient,
+		ClientSecret: clientSecret,
+	}
+
+	api.SuccessResponse(response, http.StatusCreated, w)
+}
+
+// handleDeleteClient removes OAuth client. Tokens already issued to the client stay valid until they expire.
+func (s *service) handleDeleteClient(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	err := s.repo.DeleteOAuthClient(chi.URLParam(r, "id"))
+	if err != nil && err.Error() == store.OAuthClientNotFound {
+		response.Error(status.ErrorOAuthClientNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleAuthorize issues authorization code to the client on behalf of logged user. Login page calls
+// this endpoint with query params it received from the client and redirects user to returned URI.
+func (s *service) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	query := r.URL.Query()
+
+	client, err := s.repo.GetOAuthClientByID(query.Get("client_id"))
+	if err != nil && err.Error() == store.OAuthClientNotFound {
+		response.Error(status.ErrorOAuthClientNotFound)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	redirectURI := query.Get("redirect_uri")
+	if redirectURI == "" && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	}
+
+	// User must never be redirected to URI which is not registered for the client
+	if !client.AllowsRedirectURI(redirectURI) {
+		response.Error(status.ErrorInvalidRedirectURI)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	// From now on errors are sent to the client over redirect URI
+	params := url.Values{}
+	if state := query.Get("state"); state != "" {
+		params.Set("state", state)
+	}
+
+	scopes := strings.Fields(query.Get("scope"))
+	codeChallenge := query.Get("code_challenge")
+
+	switch {
+	case query.Get("response_type") != "code":
+		params.Set("error", api.OAuthErrorUnsupportedResponseType)
+	case !client.AllowsGrantType(store.GetGrantTypes().AuthorizationCode):
+		params.Set("error", api.OAuthErrorUnauthorizedClient)
+	case len(scopes) == 0 || !client.AllowsScopes(scopes):
+		params.Set("error", api.OAuthErrorInvalidScope)
+	case codeChallenge == "" || query.Get("code_challenge_method") != codeChallengeMethodS256:
+		params.Set("error", api.OAuthErrorInvalidRequest)
+		params.Set("error_description", "PKCE with S256 code challenge method is required")
+	case !requestData.Active:
+		params.Set("error", api.OAuthErrorAccessDenied)
+	default:
+		code, err := s.createAuthorizationCode(&store.AuthorizationCode{
+			ClientID:            client.ID,
+			UserID:              requestData.UserID,
+			RedirectURI:         redirectURI,
+			Scope:               strings.Join(scopes, " "),
+			CodeChallenge:       codeChallenge,
+			CodeChallengeMethod: codeChallengeMethodS256,
+			Nonce:               query.Get("nonce"),
+		})
+		if err != nil {
+			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+			return
+		}
+
+		params.Set("code", code)
+	}
+
+	response.Data = api.OAuthAuthorizeDataResponse{
+		RedirectURI: appendQuery(redirectURI, params),
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleToken issues access token for authorization code or client credentials grant.
+// Request and response format is defined by RFC 6749.
+func (s *service) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		oauthErrorResponse(w, http.StatusBadRequest, api.OAuthErrorInvalidRequest, "request body must be form encoded")
+
+		return
+	}
+
+	client, ok := s.authenticateClient(w, r)
+	if !ok {
+		return
+	}
+
+	grantType := r.PostForm.Get("grant_type")
+	if !client.AllowsGrantType(grantType) {
+		oauthErrorResponse(w, http.StatusBadRequest, api.OAuthErrorUnauthorizedClient, "")
+
+		return
+	}
+
+	switch grantType {
+	case store.GetGrantTypes().AuthorizationCode:
+		s.exchangeAuthorizationCode(w, r, client)
+	case store.GetGrantTypes().ClientCredentials:
+		s.issueClientCredentialsToken(w, r, client)
+	default:
+		oauthErrorResponse(w, http.StatusBadRequest, api.OAuthErrorUnsupportedGrantType, "")
+	}
+}
+
+// exchangeAuthorizationCode verifies authorization code and PKCE code verifier and issues tokens for the user.
+func (s *service) exchangeAuthorizationCode(w http.ResponseWriter, r *http.Request, client *store.OAuthClient) {
+	code, err := s.repo.ConsumeAuthorizationCode(hashCode(r.PostForm.Get("code")))
+	if err != nil && err.Error() == store.AuthorizationCodeNotFound {
+		oauthErrorResponse(w, http.StatusBadRequest, api.OAuthErrorInvalidGrant, "")
+
+		return
+	} else if err != nil {
+		logger.Error().Err(err).Msgf("unable to consume authorization code of client %v", client.ID)
+		oauthErrorResponse(w, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+
+		return
+	}
+
+	if code.Used || code.Expired || code.ClientID != client.ID ||
+		code.RedirectURI != r.PostForm.Get("redirect_uri") ||
+		!verifyCodeChallenge(r.PostForm.Get("code_verifier"), code.CodeChallenge) {
+		oauthErrorResponse(w, http.StatusBadRequest, api.OAuthErrorInvalidGrant, "")
+
+		return
+	}
+
+	user, err := s.repo.GetUserByID(code.UserID)
+	if err != nil || !user.Active {
+		oauthErrorResponse(w, http.StatusBadRequest, api.OAuthErrorInvalidGrant, "")
+
+		return
+	}
+
+	userData := &api.Data{
+		UserID:     user.ID,
+		Email:      user.Email,
+		Active:     user.Active,
+		Role:       user.Role,
+		UserRoleID: user.RoleID,
+		Scope:      code.Scope,
+	}
+
+	// Access token is backed by session, same as tokens issued to our own frontend
+	claim, err := api.NewSession(r.Context(), s.inMemRepo, userData, s.conf.OAuth.AccessTokenExpiration)
+	if err != nil {
+		logger.Error().Err(err).Msgf("unable to create session for user %v", user.ID)
+		oauthErrorResponse(w, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+
+		return
+	}
+
+	claim.StandardClaims = s.standardClaims(user.ID.String(), client.ID)
+	claim.ClientID = client.ID
+	claim.Scope = code.Scope
+
+	accessToken, err := claim.CreateToken(s.conf.OAuth.AccessTokenExpiration, s.keyring)
+	if err != nil {
+		logger.Error().Err(err).Msgf("unable to sign access token for user %v", user.ID)
+		oauthErrorResponse(w, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+
+		return
+	}
+
+	tokenResponse := api.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		Scope:       code.Scope,
+		ExpiresIn:   int64(s.conf.OAuth.AccessTokenExpiration.Seconds()),
+	}
+
+	scopes := strings.Fields(code.Scope)
+
+	if utils.Contains(scopes, api.ScopeOpenID) {
+		idTokenClaim := &api.IDTokenClaim{
+			StandardClaims: s.standardClaims(user.ID.String(), client.ID),
+			Nonce:          code.Nonce,
+		}
+
+		if utils.Contains(scopes, api.ScopeProfile) {
+			idTokenClaim.Name = user.Name
+		}
+
+		if utils.Contains(scopes, api.ScopeEmail) {
+			idTokenClaim.Email = user.Email
+		}
+
+		tokenResponse.IDToken, err = s.keyring.Sign(idTokenClaim)
+		if err != nil {
+			logger.Error().Err(err).Msgf("unable to sign ID token for user %v", user.ID)
+			oauthErrorResponse(w, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+
+			return
+		}
+	}
+
+	tokenSuccessResponse(w, tokenResponse)
+}
+
+// issueClientCredentialsToken issues access token for the client itself. Token is not backed
+// by user session, so it can only be used by other services which verify it with JWKS.
+func (s *service) issueClientCredentialsToken(w http.ResponseWriter, r *http.Request, client *store.OAuthClient) {
+	scopes := strings.Fields(r.PostForm.Get("scope"))
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+
+	if !client.AllowsScopes(scopes) {
+		oauthErrorResponse(w, http.StatusBadRequest, api.OAuthErrorInvalidScope, "")
+
+		return
+	}
+
+	claim := &api.Claim{
+		StandardClaims: s.standardClaims(client.ID, client.ID),
+		ClientID:       client.ID,
+		Scope:          strings.Join(scopes, " "),
+	}
+
+	accessToken, err := claim.CreateToken(s.conf.OAuth.AccessTokenExpiration, s.keyring)
+	if err != nil {
+		logger.Error().Err(err).Msgf("unable to sign access token for client %v", client.ID)
+		oauthErrorResponse(w, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+
+		return
+	}
+
+	tokenSuccessResponse(w, api.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		Scope:       claim.Scope,
+		ExpiresIn:   int64(s.conf.OAuth.AccessTokenExpiration.Seconds()),
+	})
+}
+
+// handleUserInfo returns claims about logged user. Claims are filtered by scope granted to the client.
+func (s *service) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+
+	scopes := strings.Fields(requestData.Scope)
+	firstParty := len(scopes) == 0
+
+	if !firstParty && !utils.Contains(scopes, api.ScopeOpenID) {
+		w.Header().Set("WWW-Authenticate", `Bearer error="`+api.OAuthErrorInsufficientScope+`"`)
+		oauthErrorResponse(w, http.StatusForbidden, api.OAuthErrorInsufficientScope, "")
+
+		return
+	}
+
+	user, err := s.repo.GetUserByID(requestData.UserID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("unable to get user %v", requestData.UserID)
+		oauthErrorResponse(w, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+
+		return
+	}
+
+	userInfo := api.UserInfoResponse{
+		Subject: user.ID.String(),
+	}
+
+	if firstParty || utils.Contains(scopes, api.ScopeProfile) {
+		userInfo.Name = user.Name
+		userInfo.Locale = user.Language
+	}
+
+	if firstParty || utils.Contains(scopes, api.ScopeEmail) {
+		userInfo.Email = user.Email
+	}
+
+	api.RawJSONResponse(userInfo, http.StatusOK, w)
+}
+
+// authenticateClient authenticates client with HTTP basic auth or form params. Public
+// clients are identified by client_id only. Error response is written if authentication fails.
+func (s *service) authenticateClient(w http.ResponseWriter, r *http.Request) (*store.OAuthClient, bool) {
+	clientID, clientSecret, basicAuth := r.BasicAuth()
+	if basicAuth {
+		// Credentials in basic auth header are form encoded as well
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	unauthorized := func() {
+		if basicAuth {
+			w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+
+		oauthErrorResponse(w, http.StatusUnauthorized, api.OAuthErrorInvalidClient, "")
+	}
+
+	client, err := s.repo.GetOAuthClientByID(clientID)
+	if err != nil && err.Error() == store.OAuthClientNotFound {
+		unauthorized()
+
+		return nil, false
+	} else if err != nil {
+		logger.Error().Err(err).Msgf("unable to get client %v", clientID)
+		oauthErrorResponse(w, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+
+		return nil, false
+	}
+
+	if client.IsConfidential() && encryption.IsValid(client.SecretHash, clientSecret) != nil {
+		unauthorized()
+
+		return nil, false
+	}
+
+	if !client.IsConfidential() && clientSecret != "" {
+		unauthorized()
+
+		return nil, false
+	}
+
+	return client, true
+}
+
+// standardClaims returns registered claims shared by access and ID tokens.
+func (s *service) standardClaims(subject, audience string) jwt.StandardClaims {
+	now := time.Now()
+
+	return jwt.StandardClaims{
+		Issuer:    s.conf.OAuth.Issuer,
+		Subject:   subject,
+		Audience:  audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.conf.OAuth.AccessTokenExpiration).Unix(),
+	}
+}
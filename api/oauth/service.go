@@ -0,0 +1,27 @@
+package oauth
+
+import (
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/signing"
+)
+
+type service struct {
+	conf      *config.Config
+	repo      store.Repository
+	inMemRepo store.InMemRepository
+	keyring   *signing.Keyring
+}
+
+func newService(conf *config.Config,
+	repo store.Repository,
+	inMemRepo store.InMemRepository,
+	keyring *signing.Keyring,
+) service {
+	return service{
+		conf,
+		repo,
+		inMemRepo,
+		keyring,
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/password"
+)
+
+// passwordViolationToStatus maps password policy violations to status codes.
+func passwordViolationToStatus() map[error]int {
+	return map[error]int{
+		password.ErrTooShort:      status.ErrorPasswordTooShort,
+		password.ErrTooLong:       status.ErrorPasswordTooLong,
+		password.ErrMissingUpper:  status.ErrorPasswordMissingUppercase,
+		password.ErrMissingLower:  status.ErrorPasswordMissingLowercase,
+		password.ErrMissingDigit:  status.ErrorPasswordMissingDigit,
+		password.ErrMissingSymbol: status.ErrorPasswordMissingSymbol,
+		password.ErrEqualsEmail:   status.ErrorPasswordEqualsEmail,
+		password.ErrBreached:      status.ErrorPasswordBreached,
+	}
+}
+
+// ValidatePassword checks the password against the password policy. Error is added to the
+// response for every violation. Returns true if password is valid.
+func ValidatePassword(policy *password.Policy, pwd, email string, response *BaseResponse) bool {
+	statuses := passwordViolationToStatus()
+
+	violations := policy.Validate(pwd, email)
+	for _, violation := range violations {
+		response.Error(statuses[violation])
+	}
+
+	return len(violations) == 0
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+)
+
+// RoleRequest used when admin creates or updates the role. Permissions granted
+// to the role are replaced with the provided ones.
+type RoleRequest struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// Validate RoleRequest.
+func (rr *RoleRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(rr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if strings.TrimSpace(rr.Name) == "" {
+			response.Error(status.ErrorMissingName)
+		}
+
+		return response.HasErrors(), response
+	})
+}
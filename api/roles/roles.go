@@ -0,0 +1,451 @@
+package roles
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/api"
+	m "github.com/adinovcina/golang-setup/api/middleware"
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/adinovcina/golang-setup/tools/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/twinj/uuid"
+)
+
+func AttachRoleRoutes(r chi.Router,
+	conf *config.Config,
+	repo store.Repository,
+	inMemRepo store.InMemRepository,
+) {
+	svc := newService(conf, repo, inMemRepo)
+	permissions := store.GetPermissions()
+
+	// Protected REST routes for "roles" resource
+	r.Route("/roles", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(m.RequirePermission(permissions.RolesRead))
+			// Used to retrieve list of roles with their permissions
+			r.Get("/", svc.handleGetRoles)
+			// Used to retrieve list of permissions which can be granted to the roles
+			r.Get("/permissions", svc.handleGetPermissions)
+			// Used to fetch a single role
+			r.Get("/{id}", svc.handleGetRole)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(m.RequirePermission(permissions.RolesManage))
+			// Used to create a new role
+			r.Post("/", svc.handleCreateRole)
+			// Used to update role name and permissions
+			r.Put("/{id}", svc.handleUpdateRole)
+			// Used to delete the role which is not assigned to any user
+			r.Delete("/{id}", svc.handleDeleteRole)
+			// Used to assign the role to the user
+			r.Put("/{id}/users/{userID}", svc.handleAssignRole)
+			// Used to remove the role from the user
+			r.Delete("/{id}/users/{userID}", svc.handleRemoveRole)
+		})
+	})
+}
+
+// handleGetRoles retrieves list of all roles.
+func (s *service) handleGetRoles(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	roles, err := s.repo.GetAllRoles()
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+		return
+	}
+
+	response.Data = roles
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleGetPermissions retrieves list of all permissions.
+func (s *service) handleGetPermissions(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	permissions, err := s.repo.GetAllPermissions()
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+		return
+	}
+
+	response.Data = permissions
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleGetRole retrieves a single role.
+func (s *service) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	role, ok := s.getRole(w, r, response)
+	if !ok {
+		return
+	}
+
+	response.Data = role
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleCreateRole creates a new role with the given permissions.
+func (s *service) handleCreateRole(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.RoleRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if !s.validatePermissions(w, r, response, request.Permissions, nil) {
+		return
+	}
+
+	role, err := s.repo.CreateRole(&store.Role{
+		Name:        strings.TrimSpace(request.Name),
+		Permissions: request.Permissions,
+	})
+	if err != nil && err.Error() == store.RoleDuplicated {
+		response.Error(status.ErrorRoleAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = role
+
+	api.SuccessResponse(response, http.StatusCreated, w)
+}
+
+// handleUpdateRole updates role name and replaces its permissions. When permissions are removed, sessions
+// of users the role is assigned to are revoked, so removed permissions can not be used anymore.
+func (s *service) handleUpdateRole(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.RoleRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	role, ok := s.getRole(w, r, response)
+	if !ok {
+		return
+	}
+
+	if !s.validatePermissions(w, r, response, request.Permissions, role.Permissions) {
+		return
+	}
+
+	permissionsRemoved := false
+
+	for _, permission := range role.Permissions {
+		if !utils.Contains(request.Permissions, permission) {
+			permissionsRemoved = true
+
+			break
+		}
+	}
+
+	role.Name = strings.TrimSpace(request.Name)
+	role.Permissions = request.Permissions
+
+	updatedRole, err := s.repo.UpdateRole(role)
+	if err != nil && err.Error() == store.RoleDuplicated {
+		response.Error(status.ErrorRoleAlreadyExists)
+		api.ErrorResponse(response, http.StatusConflict, w, r, err)
+
+		return
+	} else if err != nil {
+		logger.Error().Err(err).Msgf("unable to update role with id %v", role.ID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if permissionsRemoved {
+		if err := s.revokeRoleSessions(r.Context(), role.ID); err != nil {
+			logger.Error().Err(err).Msgf("unable to revoke sessions of users with role %v", role.ID)
+			api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+			return
+		}
+	}
+
+	response.Data = updatedRole
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleDeleteRole deletes the role. Role which is assigned to users can not be deleted.
+func (s *service) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	role, ok := s.getRole(w, r, response)
+	if !ok {
+		return
+	}
+
+	err := s.repo.DeleteRole(role.ID)
+	if err != nil && err.Error() == store.RoleInUse {
+		response.Error(status.ErrorRoleInUse)
+		api.ErrorResponse(response, http.StatusConflict, w, r, err)
+
+		return
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleAssignRole assigns the role to the user.
+func (s *service) handleAssignRole(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	role, ok := s.getRole(w, r, response)
+	if !ok {
+		return
+	}
+
+	userID, ok := s.getUserIDParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	if err := s.repo.AddUserRole(userID, role.ID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleRemoveRole removes the role from the user. User sessions are revoked, so permissions
+// of the removed role can not be used anymore.
+func (s *service) handleRemoveRole(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	roleID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(status.ErrorInvalidURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	userID, ok := s.getUserIDParam(w, r, response)
+	if !ok {
+		return
+	}
+
+	userRoles, err := s.repo.GetUserRoles(userID)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if !hasRole(userRoles, roleID) {
+		response.Error(status.ErrorRoleNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, nil)
+
+		return
+	}
+
+	// User without a role would not be able to log in
+	if len(userRoles) == 1 {
+		response.Error(status.ErrorLastUserRole)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	if err := s.repo.RemoveUserRole(userID, roleID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	if err := s.revokeSessions(r.Context(), userID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// revokeRoleSessions revokes sessions and refresh tokens of all users the role is assigned to.
+func (s *service) revokeRoleSessions(ctx context.Context, roleID int64) error {
+	userIDs, err := s.repo.GetRoleUserIDs(roleID)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if err := s.revokeSessions(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeSessions revokes all sessions and refresh tokens of the user.
+func (s *service) revokeSessions(ctx context.Context, userID uuid.UUID) error {
+	if _, err := s.inMemRepo.DelSessions(ctx, userID, ""); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteUserTokens(userID, "")
+}
+
+// getRole retrieves the role from the URL param.
+// Error response is written if role can not be retrieved.
+func (s *service) getRole(w http.ResponseWriter, r *http.Request, response *api.BaseResponse) (*store.Role, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(status.ErrorInvalidURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return nil, false
+	}
+
+	role, err := s.repo.GetRoleByID(id)
+	if err != nil && err.Error() == store.RoleNotFound {
+		response.Error(status.ErrorRoleNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return nil, false
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	return role, true
+}
+
+// getUserIDParam parses the user id URL param and checks if user exists. Users can not
+// change their own roles. Error response is written if user can not be retrieved.
+func (s *service) getUserIDParam(w http.ResponseWriter, r *http.Request, response *api.BaseResponse) (uuid.UUID, bool) {
+	requestData := api.RequestData(r)
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		response.Error(status.ErrorInvalidURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return uuid.UUID{}, false
+	}
+
+	if *userID == requestData.UserID {
+		response.Error(status.ErrorUnableToChangeOwnRoles)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return uuid.UUID{}, false
+	}
+
+	if _, err := s.repo.GetUserByID(*userID); err != nil && err.Error() == store.UserNotFound {
+		response.Error(status.ErrorGetUser)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return uuid.UUID{}, false
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return uuid.UUID{}, false
+	}
+
+	return *userID, true
+}
+
+// validatePermissions checks if all permissions exist. Permissions not granted to the role yet can only be
+// granted by user who has them, so role managers can not gain more permissions through their own roles.
+// Error response is written if any of the permissions is invalid.
+func (s *service) validatePermissions(w http.ResponseWriter, r *http.Request, response *api.BaseResponse,
+	names, granted []string,
+) bool {
+	requestData := api.RequestData(r)
+
+	permissions, err := s.repo.GetAllPermissions()
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return false
+	}
+
+	available := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		available[permission.Name] = true
+	}
+
+	for _, name := range names {
+		if !available[name] {
+			response.Error(status.ErrorInvalidPermission)
+			api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+			return false
+		}
+
+		if !utils.Contains(granted, name) && !requestData.HasPermission(name) {
+			response.Error(status.ErrorInvalidPermission)
+			api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasRole checks if role is in the list of user roles.
+func hasRole(roles []*store.Role, roleID int64) bool {
+	for _, role := range roles {
+		if role.ID == roleID {
+			return true
+		}
+	}
+
+	return false
+}
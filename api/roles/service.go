@@ -0,0 +1,23 @@
+package roles
+
+import (
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+)
+
+type service struct {
+	conf      *config.Config
+	repo      store.Repository
+	inMemRepo store.InMemRepository
+}
+
+func newService(conf *config.Config,
+	repo store.Repository,
+	inMemRepo store.InMemRepository,
+) service {
+	return service{
+		conf,
+		repo,
+		inMemRepo,
+	}
+}
@@ -1,40 +1,120 @@
 package api
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"strings"
 	"time"
 
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/signing"
 	"github.com/adinovcina/golang-setup/tools/utils"
 	jwt "github.com/golang-jwt/jwt"
 	bg "github.com/kjk/betterguid"
 	"github.com/twinj/uuid"
 )
 
-// Data contains basic user data after user is authorized.
+// Data contains basic user data after user is authorized. Scope is set only for
+// sessions created for OAuth clients. IPAddress and UserAgent describe the client
+// which sent the current request and are not stored in the session. Permissions are
+// loaded from the user role when session is created or refreshed. ImpersonatorID and
+// ImpersonatorSessionID are set for sessions admin created to act as the user.
 type Data struct {
-	Email      string    `json:"email"`
-	Role       string    `json:"role"`
-	RequestID  string    `json:"requestID,omitempty"`
-	SessionKey string    `json:"sessionKey"`
-	UserID     uuid.UUID `json:"userID"`
-	UserRoleID int64     `json:"userRoleID"`
-	Active     bool      `json:"active"`
+	Email                 string     `json:"email"`
+	Role                  string     `json:"role"`
+	RequestID             string     `json:"requestID,omitempty"`
+	SessionKey            string     `json:"sessionKey"`
+	SessionID             string     `json:"sessionID"`
+	Scope                 string     `json:"scope,omitempty"`
+	IPAddress             string     `json:"-"`
+	UserAgent             string     `json:"-"`
+	Permissions           []string   `json:"permissions,omitempty"`
+	UserID                uuid.UUID  `json:"userID"`
+	UserRoleID            int64      `json:"userRoleID"`
+	ImpersonatorID        *uuid.UUID `json:"impersonatorID,omitempty"`
+	ImpersonatorSessionID string     `json:"impersonatorSessionID,omitempty"`
+	// AccessTokenID is set when request is authorized with personal access token instead of session
+	AccessTokenID int64 `json:"-"`
+	Active        bool  `json:"active"`
 }
 
-// Claim for JWT.
+// HasPermission checks if the role user is authorized with grants the permission.
+func (d *Data) HasPermission(permission string) bool {
+	return utils.Contains(d.Permissions, permission)
+}
+
+// IsImpersonated checks if the session was created by admin to act as the user.
+func (d *Data) IsImpersonated() bool {
+	return d.ImpersonatorID != nil
+}
+
+// Claim for JWT. ClientID and Scope are set only for tokens issued to OAuth clients. ImpersonatorID
+// marks tokens of sessions admin created to act as the user, so clients can show it.
 type Claim struct {
 	jwt.StandardClaims
-	SessionID string    `json:"sessionID"`
-	UserID    uuid.UUID `json:"userID"`
+	SessionID      string     `json:"sessionID"`
+	ClientID       string     `json:"clientID,omitempty"`
+	Scope          string     `json:"scope,omitempty"`
+	ImpersonatorID *uuid.UUID `json:"impersonatorID,omitempty"`
+	UserID         uuid.UUID  `json:"userID"`
 }
 
 // CreateToken Generate jwt new token for the user.
-func (c *Claim) CreateToken(tokenTTL time.Duration, secretKey string) (string, error) {
+func (c *Claim) CreateToken(tokenTTL time.Duration, keyring *signing.Keyring) (string, error) {
 	c.ExpiresAt = time.Now().Add(tokenTTL).Unix()
 	c.StandardClaims.ExpiresAt = c.ExpiresAt
 
-	// Create the token using your claims
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(secretKey))
+	// Sign the token with the current key from keyring
+	return keyring.Sign(c)
+}
+
+// sessionStore is implemented by in memory repository which keeps user sessions.
+type sessionStore interface {
+	SetSession(ctx context.Context, uid uuid.UUID, session *store.Session, v string, redisTokenTTL time.Duration) error
+}
+
+// NewSession will create a session object for the user, store it in memory repository
+// and return claim which is used to sign the token. Session provided in userData.SessionID
+// is renewed instead of creating a new one.
+func NewSession(ctx context.Context, inMemRepo sessionStore, userData *Data, redisTokenTTL time.Duration) (*Claim, error) {
+	claim := new(Claim)
+
+	claim.SessionID = userData.SessionID
+	if claim.SessionID == "" {
+		claim.NewID()
+	}
+
+	claim.UserID = userData.UserID
+
+	userData.SessionID = claim.SessionID
+	userData.SessionKey = utils.FormatSessionKey(userData.UserID, claim.SessionID)
+
+	userDataMarshaled, err := json.Marshal(userData)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	session := &store.Session{
+		ID:         claim.SessionID,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+
+	// Remember the device session was created from
+	if requestData := MiddlewareDataFromContext(ctx); requestData != nil {
+		session.IPAddress = requestData.IPAddress
+		session.UserAgent = requestData.UserAgent
+	}
+
+	err = inMemRepo.SetSession(ctx, userData.UserID, session, string(userDataMarshaled), redisTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return claim, nil
 }
 
 // NewID will generate a new random ID.
@@ -50,3 +130,11 @@ func NewRefreshToken() string {
 func NewDoubleUUIDCode() string {
 	return strings.ReplaceAll(utils.GenerateUniqueID()+utils.GenerateUniqueID(), "-", "")
 }
+
+// HashToken returns SHA-256 hash of the token which is stored instead of the token itself. Tokens are
+// random, so there is no need for slow hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
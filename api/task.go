@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adinovcina/golang-setup/store"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/twinj/uuid"
+)
+
+// CreateTaskRequest used when user creates a new task.
+type CreateTaskRequest struct {
+	DueDate     *time.Time `json:"dueDate"`
+	AssignedTo  *uuid.UUID `json:"assignedTo"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+}
+
+// Validate CreateTaskRequest.
+func (ctr *CreateTaskRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(ctr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if strings.TrimSpace(ctr.Title) == "" {
+			response.Error(status.ErrorMissingTitle)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// UpdateTaskRequest contains task details which should be updated.
+type UpdateTaskRequest struct {
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	DueDate     *time.Time `json:"dueDate"`
+}
+
+// Validate UpdateTaskRequest.
+func (utr *UpdateTaskRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(utr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if utr.Title != nil && strings.TrimSpace(*utr.Title) == "" {
+			response.Error(status.ErrorMissingTitle)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// AssignTaskRequest contains id of the user task should be assigned to. Empty value unassigns the task.
+type AssignTaskRequest struct {
+	AssignedTo *uuid.UUID `json:"assignedTo"`
+}
+
+// Validate AssignTaskRequest.
+func (atr *AssignTaskRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(atr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		return response.HasErrors(), response
+	})
+}
+
+// UpdateTaskStatusRequest contains new status of the task.
+type UpdateTaskStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// Validate UpdateTaskStatusRequest.
+func (utsr *UpdateTaskStatusRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(utsr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if !store.IsValidTaskStatus(utsr.Status) {
+			response.Error(status.ErrorInvalidTaskStatus)
+		}
+
+		return response.HasErrors(), response
+	})
+}
@@ -0,0 +1,393 @@
+package tasks
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/api"
+	m "github.com/adinovcina/golang-setup/api/middleware"
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+	"github.com/go-chi/chi/v5"
+	"github.com/twinj/uuid"
+)
+
+func AttachTaskRoutes(r chi.Router,
+	conf *config.Config,
+	repo store.Repository,
+) {
+	svc := newService(conf, repo)
+
+	// Protected REST routes for "tasks" resource
+	r.Route("/tasks", func(r chi.Router) {
+		// Used to retrieve list of tasks user created or is assigned to
+		r.With(m.PaginationCursor(repo)).Get("/", svc.handleGetTasks)
+		// Used to create a new task
+		r.Post("/", svc.handleCreateTask)
+		// Used to fetch a single task
+		r.Get("/{id}", svc.handleGetTask)
+		// Used to update task details
+		r.Patch("/{id}", svc.handleUpdateTask)
+		// Used to delete the task
+		r.Delete("/{id}", svc.handleDeleteTask)
+		// Used to assign the task to the user
+		r.Post("/{id}/assign", svc.handleAssignTask)
+		// Used to move the task to another status
+		r.Post("/{id}/status", svc.handleUpdateTaskStatus)
+	})
+}
+
+// handleCreateTask creates a new task for logged user.
+func (s *service) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.CreateTaskRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	if request.AssignedTo != nil && !s.validateAssignee(w, r, response, *request.AssignedTo) {
+		return
+	}
+
+	task, err := s.repo.CreateTask(&store.Task{
+		Title:       strings.TrimSpace(request.Title),
+		Description: request.Description,
+		DueDate:     request.DueDate,
+		AssignedTo:  request.AssignedTo,
+		CreatedBy:   requestData.UserID,
+	})
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = task
+
+	api.SuccessResponse(response, http.StatusCreated, w)
+}
+
+// handleGetTasks retrieves list of tasks. Users with permission to manage tasks can see all tasks,
+// while others only see the tasks they created or are assigned to.
+func (s *service) handleGetTasks(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	filter := &store.TaskFilter{}
+
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	query := r.URL.Query()
+
+	if taskStatus := query.Get("status"); taskStatus != "" {
+		if !store.IsValidTaskStatus(taskStatus) {
+			response.Error(status.ErrorInvalidTaskStatus)
+			api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+			return
+		}
+
+		filter.Status = &taskStatus
+	}
+
+	assignedTo, err := parseUserIDParam(query.Get("assignedTo"))
+	if err != nil {
+		response.Error(status.ErrorInvalidQueryURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	filter.AssignedTo = assignedTo
+
+	createdBy, err := parseUserIDParam(query.Get("createdBy"))
+	if err != nil {
+		response.Error(status.ErrorInvalidQueryURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return
+	}
+
+	filter.CreatedBy = createdBy
+
+	if search := query.Get("search"); search != "" {
+		filter.Search = &search
+	}
+
+	if !canManageAllTasks(requestData) {
+		filter.VisibleTo = &requestData.UserID
+	}
+
+	tasks, err := s.repo.GetTasks(filter)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+		return
+	}
+
+	response.Data = api.PaginatedCursorResponse{
+		Results:    tasks,
+		Pagination: s.repo.PaginatorCursor(),
+	}
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleGetTask retrieves a single task.
+func (s *service) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	task, ok := s.getTask(w, r, response)
+	if !ok {
+		return
+	}
+
+	response.Data = task
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleUpdateTask updates task details. Only creator of the task or admin can update the task.
+func (s *service) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.UpdateTaskRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	task, ok := s.getTask(w, r, response)
+	if !ok {
+		return
+	}
+
+	if !canManage(requestData, task) {
+		api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
+
+		return
+	}
+
+	if request.Title != nil {
+		task.Title = strings.TrimSpace(*request.Title)
+	}
+
+	if request.Description != nil {
+		task.Description = *request.Description
+	}
+
+	if request.DueDate != nil {
+		task.DueDate = request.DueDate
+	}
+
+	updatedTask, err := s.repo.UpdateTask(task)
+	if err != nil {
+		logger.Error().Err(err).Msgf("unable to update task with id %v", task.ID)
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = updatedTask
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleDeleteTask deletes the task. Only creator of the task or admin can delete the task.
+func (s *service) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	response := &api.BaseResponse{}
+	response.RequestID = requestData.RequestID
+
+	task, ok := s.getTask(w, r, response)
+	if !ok {
+		return
+	}
+
+	if !canManage(requestData, task) {
+		api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
+
+		return
+	}
+
+	if err := s.repo.DeleteTask(task.ID); err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	api.SuccessResponse(response, http.StatusNoContent, w)
+}
+
+// handleAssignTask assigns the task to another user. Only creator of the task or admin can assign the task.
+func (s *service) handleAssignTask(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.AssignTaskRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	task, ok := s.getTask(w, r, response)
+	if !ok {
+		return
+	}
+
+	if !canManage(requestData, task) {
+		api.ErrorResponse(response, http.StatusForbidden, w, r, nil)
+
+		return
+	}
+
+	if request.AssignedTo != nil && !s.validateAssignee(w, r, response, *request.AssignedTo) {
+		return
+	}
+
+	task, err := s.repo.AssignTask(task.ID, request.AssignedTo)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = task
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// handleUpdateTaskStatus moves the task to another status. Task status can be changed by
+// creator of the task, user task is assigned to or admin.
+func (s *service) handleUpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
+	requestData := api.RequestData(r)
+	request := new(api.UpdateTaskStatusRequest)
+
+	valid, response := request.Validate(r)
+	response.RequestID = requestData.RequestID
+
+	if !valid {
+		logger.Error().Msgf("invalid request received: %v", request)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return
+	}
+
+	task, ok := s.getTask(w, r, response)
+	if !ok {
+		return
+	}
+
+	if !task.CanTransitionTo(request.Status) {
+		response.Error(status.ErrorInvalidTaskStatusTransition)
+		api.ErrorResponse(response, http.StatusConflict, w, r, nil)
+
+		return
+	}
+
+	task, err := s.repo.UpdateTaskStatus(task.ID, request.Status)
+	if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return
+	}
+
+	response.Data = task
+
+	api.SuccessResponse(response, http.StatusOK, w)
+}
+
+// getTask retrieves the task from the URL param and checks if logged user can access it.
+// Error response is written if task can not be retrieved.
+func (s *service) getTask(w http.ResponseWriter, r *http.Request, response *api.BaseResponse) (*store.Task, bool) {
+	requestData := api.RequestData(r)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(status.ErrorInvalidURLParameters)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return nil, false
+	}
+
+	task, err := s.repo.GetTaskByID(id)
+	if err != nil && err.Error() == store.TaskNotFound {
+		response.Error(status.ErrorTaskNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, err)
+
+		return nil, false
+	} else if err != nil {
+		api.ErrorResponse(response, http.StatusInternalServerError, w, r, err)
+
+		return nil, false
+	}
+
+	// Do not expose tasks user has no access to
+	if !canManageAllTasks(requestData) && !task.IsVisibleTo(requestData.UserID) {
+		response.Error(status.ErrorTaskNotFound)
+		api.ErrorResponse(response, http.StatusNotFound, w, r, nil)
+
+		return nil, false
+	}
+
+	return task, true
+}
+
+// validateAssignee checks if task can be assigned to the user.
+// Error response is written if user does not exist or is not active.
+func (s *service) validateAssignee(w http.ResponseWriter, r *http.Request, response *api.BaseResponse, userID uuid.UUID) bool {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		response.Error(status.ErrorGetUser)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, err)
+
+		return false
+	}
+
+	if !user.Active {
+		response.Error(status.ErrorUserNotActive)
+		api.ErrorResponse(response, http.StatusBadRequest, w, r, nil)
+
+		return false
+	}
+
+	return true
+}
+
+// parseUserIDParam parses optional user id query param.
+func parseUserIDParam(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	return uuid.Parse(value)
+}
+
+// canManageAllTasks checks if user role grants access to tasks of all users.
+func canManageAllTasks(requestData *api.Data) bool {
+	return requestData.HasPermission(store.GetPermissions().TasksManage)
+}
+
+// canManage checks if user is allowed to edit, assign or delete the task.
+func canManage(requestData *api.Data, task *store.Task) bool {
+	return canManageAllTasks(requestData) || task.CreatedBy == requestData.UserID
+}
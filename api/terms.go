@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+)
+
+// AcceptTermsRequest contains version of terms of service user accepts. Only the current
+// version can be accepted, so user does not accept terms they have not seen.
+type AcceptTermsRequest struct {
+	Version string `json:"version"`
+}
+
+// Validate AcceptTermsRequest.
+func (atr *AcceptTermsRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(atr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if strings.TrimSpace(atr.Version) == "" {
+			response.Error(status.ErrorMissingVersion)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// CreateTermsRequest used when admin publishes a new version of terms of service.
+// Version is published immediately if PublishedAt is not set.
+type CreateTermsRequest struct {
+	PublishedAt *time.Time `json:"publishedAt"`
+	Version     string     `json:"version"`
+	Content     string     `json:"content"`
+}
+
+// Validate CreateTermsRequest.
+func (ctr *CreateTermsRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(ctr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if strings.TrimSpace(ctr.Version) == "" {
+			response.Error(status.ErrorMissingVersion)
+		}
+
+		if strings.TrimSpace(ctr.Content) == "" {
+			response.Error(status.ErrorMissingContent)
+		}
+
+		return response.HasErrors(), response
+	})
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/store"
+	status "github.com/adinovcina/golang-setup/tools/network/statuscodes"
+)
+
+// languageCodeLength is the length of ISO 639-1 language code.
+const languageCodeLength = 2
+
+// UpdateUserRequest contains user details edited by admin. Only provided fields are changed.
+type UpdateUserRequest struct {
+	Name     *string `json:"name"`
+	Email    *string `json:"email"`
+	Phone    *string `json:"phone"`
+	Language *string `json:"language"`
+}
+
+// Validate UpdateUserRequest.
+func (uur *UpdateUserRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(uur, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// Validate body params
+		if uur.Name != nil && strings.TrimSpace(*uur.Name) == "" {
+			response.Error(status.ErrorMissingName)
+		}
+
+		if uur.Email != nil && !validateEmail(*uur.Email) {
+			response.Error(status.ErrorEmailNotInCorrectFormat)
+		}
+
+		if uur.Phone != nil && strings.TrimSpace(*uur.Phone) == "" {
+			response.Error(status.ErrorMissingPhone)
+		}
+
+		if uur.Language != nil && len(*uur.Language) != languageCodeLength {
+			response.Error(status.ErrorInvalidLanguage)
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// SetUserRolesRequest contains ids of all roles user should have.
+type SetUserRolesRequest struct {
+	RoleIDs []int64 `json:"roleIDs"`
+}
+
+// Validate SetUserRolesRequest.
+func (surr *SetUserRolesRequest) Validate(r *http.Request) (bool, *BaseResponse) {
+	return ValidateRequestData(surr, r, func() (bool, *BaseResponse) {
+		response := new(BaseResponse)
+
+		// User without a role would not be able to log in
+		if len(surr.RoleIDs) == 0 {
+			response.Error(status.ErrorMissingRoleID)
+		}
+
+		for _, roleID := range surr.RoleIDs {
+			if roleID <= 0 {
+				response.Error(status.ErrorMissingRoleID)
+
+				break
+			}
+		}
+
+		return response.HasErrors(), response
+	})
+}
+
+// UserDetailDataResponse contains user details along with all roles assigned to the user.
+type UserDetailDataResponse struct {
+	*store.User
+	Roles []*store.Role `json:"roles"`
+}
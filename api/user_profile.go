@@ -10,8 +10,10 @@ import (
 
 // UpdateUserProfileRequest contains user profile info.
 type UpdateUserProfileRequest struct {
-	Name   *string   `json:"name"`
-	Phone  *string   `json:"phone"`
+	Name  *string `json:"name"`
+	Phone *string `json:"phone"`
+	// Email is changed once the new address is verified over the link sent to it
+	Email  *string   `json:"email"`
 	UserID uuid.UUID `json:"userID"`
 }
 
@@ -25,6 +27,10 @@ func (uupr *UpdateUserProfileRequest) Validate(r *http.Request) (bool, *BaseResp
 			response.Error(status.ErrorMissingPhone)
 		}
 
+		if uupr.Email != nil && !validateEmail(*uupr.Email) {
+			response.Error(status.ErrorEmailNotInCorrectFormat)
+		}
+
 		if _, err := uuid.Parse(uupr.UserID.String()); err != nil {
 			response.Error(status.ErrorMissingUserID)
 		}
@@ -34,10 +40,12 @@ func (uupr *UpdateUserProfileRequest) Validate(r *http.Request) (bool, *BaseResp
 }
 
 type UserProfileDataResponse struct {
-	Name     string    `json:"name,omitempty"`
-	Email    string    `json:"email,omitempty"`
-	Phone    string    `json:"phone,omitempty"`
-	Language string    `json:"language,omitempty"`
-	Role     string    `json:"role,omitempty"`
-	ID       uuid.UUID `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Language string `json:"language,omitempty"`
+	Role     string `json:"role,omitempty"`
+	// PendingEmail is the new email address waiting to be verified
+	PendingEmail string    `json:"pendingEmail,omitempty"`
+	ID           uuid.UUID `json:"id,omitempty"`
 }
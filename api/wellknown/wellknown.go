@@ -0,0 +1,68 @@
+package wellknown
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/api"
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/signing"
+	"github.com/go-chi/chi/v5"
+)
+
+type service struct {
+	conf    *config.Config
+	keyring *signing.Keyring
+}
+
+func AttachWellKnownRoutes(r chi.Router, conf *config.Config, keyring *signing.Keyring) {
+	svc := &service{conf: conf, keyring: keyring}
+
+	// Unprotected routes with metadata used by other services to verify issued tokens
+	r.Route("/.well-known", func(r chi.Router) {
+		// Used to retrieve public keys in JWK Set format
+		r.Get("/jwks.json", svc.handleGetJWKS)
+		// Used by OpenID Connect clients to discover endpoints and supported features
+		r.Get("/openid-configuration", svc.handleGetOpenIDConfiguration)
+	})
+}
+
+// handleGetJWKS returns public keys which can be used to verify access tokens.
+func (s *service) handleGetJWKS(w http.ResponseWriter, _ *http.Request) {
+	// Keys can be cached as long as new keys are published before they are used for signing
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(signing.PublishDelay.Seconds())))
+
+	api.RawJSONResponse(s.keyring.JWKS(), http.StatusOK, w)
+}
+
+// handleGetOpenIDConfiguration returns OpenID Connect discovery document.
+func (s *service) handleGetOpenIDConfiguration(w http.ResponseWriter, _ *http.Request) {
+	issuer := strings.TrimSuffix(s.conf.OAuth.Issuer, "/")
+
+	// Authorization requests are handled by login page, which calls authorize endpoint for logged user
+	authorizationEndpoint := s.conf.OAuth.AuthorizationPageURL
+	if authorizationEndpoint == "" {
+		authorizationEndpoint = issuer + "/oauth/authorize"
+	}
+
+	api.RawJSONResponse(api.OpenIDConfiguration{
+		Issuer:                 issuer,
+		AuthorizationEndpoint:  authorizationEndpoint,
+		TokenEndpoint:          issuer + "/oauth/token",
+		UserInfoEndpoint:       issuer + "/userinfo",
+		JWKSURI:                issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported: []string{"code"},
+		GrantTypesSupported: []string{
+			store.GetGrantTypes().AuthorizationCode,
+			store.GetGrantTypes().ClientCredentials,
+		},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{s.keyring.Algorithm()},
+		ScopesSupported:                   []string{api.ScopeOpenID, api.ScopeProfile, api.ScopeEmail},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "nonce", "name", "email", "locale"},
+	}, http.StatusOK, w)
+}
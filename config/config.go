@@ -23,9 +23,55 @@ const (
 	mfaRefreshTokenExpirationDefault   = 30 * 24 * time.Hour
 	redisTokenExpirationDefault        = 24 * time.Hour
 
-	maxLoginFailures       = 10
-	banDurationDefaultTime = 5 * time.Minute
-	timeoutDuration        = 30 * time.Second
+	jwtSigningAlgorithmDefault = "HS256"
+	jwtKeysDirectoryDefault    = "keys"
+
+	oauthIssuerDefault                      = "http://localhost:5500"
+	oauthAuthorizationCodeExpirationDefault = 5 * time.Minute
+	oauthAccessTokenExpirationDefault       = time.Hour
+
+	mfaIssuerDefault             = "golang-setup"
+	mfaRecoveryCodesCountDefault = 10
+	mfaMaxAttemptsDefault        = 5
+
+	invitationExpiration = 30 * 24 * time.Hour
+	registrationEnabled  = false
+	strictResponses      = false
+	timeoutDuration      = 30 * time.Second
+
+	personalAccessTokenMaxLifetimeDefault = 365 * 24 * time.Hour
+
+	lockoutWindowDefault             = 24 * time.Hour
+	lockoutAccountThresholdDefault   = 20
+	lockoutIPThresholdDefault        = 100
+	lockoutAccountIPThresholdDefault = 5
+	lockoutBaseDelayDefault          = time.Second
+	lockoutMaxDelayDefault           = 15 * time.Minute
+	lockoutAccountMaxDelayDefault    = time.Minute
+
+	passwordMinLengthDefault     = 8
+	passwordMaxLengthDefault     = 72
+	passwordRequireUpperDefault  = true
+	passwordRequireLowerDefault  = true
+	passwordRequireDigitDefault  = true
+	passwordRequireSymbolDefault = false
+	passwordHistorySizeDefault   = 5
+
+	passwordHashAlgorithmDefault     = "argon2id"
+	passwordBcryptCostDefault        = 12
+	passwordArgon2MemoryDefault      = 64 * 1024
+	passwordArgon2IterationsDefault  = 3
+	passwordArgon2ParallelismDefault = 2
+
+	rateLimitEnabledDefault    = true
+	rateLimitWindowDefault     = 15 * time.Minute
+	rateLimitIPLimitDefault    = 50
+	rateLimitEmailLimitDefault = 5
+
+	emailVerificationPolicyDefault         = EmailVerificationPolicyAllow
+	emailVerificationGracePeriodDefault    = 7 * 24 * time.Hour
+	emailVerificationExpirationDefault     = 24 * time.Hour
+	emailVerificationResendIntervalDefault = time.Minute
 )
 
 // Load application configuration.
@@ -1,10 +1,19 @@
 package config
 
 import (
+	"fmt"
+	"net"
+	"strings"
+
 	"github.com/adinovcina/golang-setup/tools/env"
 )
 
 func loadFromEnv() (*Config, error) {
+	trustedProxies, err := parseTrustedProxies(env.Get(env.RateLimitTrustedProxies))
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		Service: Service{
 			Port:        env.GetOr(env.ServicePort, apiPortDefault),
@@ -12,8 +21,48 @@ func loadFromEnv() (*Config, error) {
 			LogLevel:    env.GetOr(env.LogLevel, logLevelInfo),
 		},
 		Account: Account{
-			MaxLoginFailures: env.GetIntOr(env.MaxLoginFailures, maxLoginFailures),
-			BanDurationTime:  env.GetDateTime(env.BanDurationTime, banDurationDefaultTime),
+			InvitationExpiration: env.GetDurationOr(env.InvitationExpiration, invitationExpiration),
+			RegistrationEnabled:  env.GetBooleanOr(env.RegistrationEnabled, registrationEnabled),
+			StrictResponses:      env.GetBooleanOr(env.StrictResponses, strictResponses),
+			PersonalAccessTokenMaxLifetime: env.GetDurationOr(env.PersonalAccessTokenMaxLifetime,
+				personalAccessTokenMaxLifetimeDefault),
+			EmailVerification: EmailVerification{
+				Policy:         env.GetOr(env.EmailVerificationPolicy, emailVerificationPolicyDefault),
+				GracePeriod:    env.GetDurationOr(env.EmailVerificationGracePeriod, emailVerificationGracePeriodDefault),
+				Expiration:     env.GetDurationOr(env.EmailVerificationExpiration, emailVerificationExpirationDefault),
+				ResendInterval: env.GetDurationOr(env.EmailVerificationResendInterval, emailVerificationResendIntervalDefault),
+			},
+			Lockout: Lockout{
+				Window:             env.GetDurationOr(env.LockoutWindow, lockoutWindowDefault),
+				AccountThreshold:   env.GetIntOr(env.LockoutAccountThreshold, lockoutAccountThresholdDefault),
+				IPThreshold:        env.GetIntOr(env.LockoutIPThreshold, lockoutIPThresholdDefault),
+				AccountIPThreshold: env.GetIntOr(env.LockoutAccountIPThreshold, lockoutAccountIPThresholdDefault),
+				BaseDelay:          env.GetDurationOr(env.LockoutBaseDelay, lockoutBaseDelayDefault),
+				MaxDelay:           env.GetDurationOr(env.LockoutMaxDelay, lockoutMaxDelayDefault),
+				AccountMaxDelay:    env.GetDurationOr(env.LockoutAccountMaxDelay, lockoutAccountMaxDelayDefault),
+			},
+		},
+		RateLimit: RateLimit{
+			Enabled:        env.GetBooleanOr(env.RateLimitEnabled, rateLimitEnabledDefault),
+			Window:         env.GetDurationOr(env.RateLimitWindow, rateLimitWindowDefault),
+			IPLimit:        env.GetIntOr(env.RateLimitIPLimit, rateLimitIPLimitDefault),
+			EmailLimit:     env.GetIntOr(env.RateLimitEmailLimit, rateLimitEmailLimitDefault),
+			TrustedProxies: trustedProxies,
+		},
+		Password: Password{
+			MinLength:         env.GetIntOr(env.PasswordMinLength, passwordMinLengthDefault),
+			MaxLength:         env.GetIntOr(env.PasswordMaxLength, passwordMaxLengthDefault),
+			RequireUpper:      env.GetBooleanOr(env.PasswordRequireUpper, passwordRequireUpperDefault),
+			RequireLower:      env.GetBooleanOr(env.PasswordRequireLower, passwordRequireLowerDefault),
+			RequireDigit:      env.GetBooleanOr(env.PasswordRequireDigit, passwordRequireDigitDefault),
+			RequireSymbol:     env.GetBooleanOr(env.PasswordRequireSymbol, passwordRequireSymbolDefault),
+			BreachedListPath:  env.Get(env.PasswordBreachedListPath),
+			HistorySize:       env.GetIntOr(env.PasswordHistorySize, passwordHistorySizeDefault),
+			HashAlgorithm:     env.GetOr(env.PasswordHashAlgorithm, passwordHashAlgorithmDefault),
+			BcryptCost:        env.GetIntOr(env.PasswordBcryptCost, passwordBcryptCostDefault),
+			Argon2Memory:      env.GetIntOr(env.PasswordArgon2Memory, passwordArgon2MemoryDefault),
+			Argon2Iterations:  env.GetIntOr(env.PasswordArgon2Iterations, passwordArgon2IterationsDefault),
+			Argon2Parallelism: env.GetIntOr(env.PasswordArgon2Parallelism, passwordArgon2ParallelismDefault),
 		},
 		Database: Database{
 			Username:         env.MustGet(env.DatabaseUsername),
@@ -32,6 +81,20 @@ func loadFromEnv() (*Config, error) {
 			TemporaryTokenExpiration: env.GetDateTime(env.MFATemporaryTokenExpiration, mfaTemporaryTokenExpirationDefault),
 			AccessTokenExpiration:    env.GetDateTime(env.MFAAccessTokenExpiration, mfaAccessTokenExpirationDefault),
 			RefreshTokenExpiration:   env.GetDateTime(env.MFARefreshTokenExpiration, mfaRefreshTokenExpirationDefault),
+			Issuer:                   env.GetOr(env.MFAIssuer, mfaIssuerDefault),
+			RecoveryCodesCount:       env.GetIntOr(env.MFARecoveryCodesCount, mfaRecoveryCodesCountDefault),
+			MaxAttempts:              env.GetIntOr(env.MFAMaxAttempts, mfaMaxAttemptsDefault),
+		},
+		JWT: JWT{
+			SigningAlgorithm: env.GetOr(env.JWTSigningAlgorithm, jwtSigningAlgorithmDefault),
+			KeysDirectory:    env.GetOr(env.JWTKeysDirectory, jwtKeysDirectoryDefault),
+			RotationInterval: env.GetDurationOr(env.JWTKeyRotationInterval, 0),
+		},
+		OAuth: OAuth{
+			Issuer:                      env.GetOr(env.OAuthIssuer, oauthIssuerDefault),
+			AuthorizationPageURL:        env.Get(env.OAuthAuthorizationPageURL),
+			AuthorizationCodeExpiration: env.GetDurationOr(env.OAuthAuthorizationCodeExpiration, oauthAuthorizationCodeExpirationDefault),
+			AccessTokenExpiration:       env.GetDurationOr(env.OAuthAccessTokenExpiration, oauthAccessTokenExpirationDefault),
 		},
 		Redis: Redis{
 			Address:   env.MustGet(env.RedisAddress),
@@ -40,13 +103,112 @@ func loadFromEnv() (*Config, error) {
 			SecretKey: env.MustGet(env.RedisSecretKey),
 			TokenTTL:  env.GetDateTime(env.RedisTokenTTL, redisTokenExpirationDefault),
 		},
+		Encryption: Encryption{
+			HashKey:          env.MustGet(env.EncryptionProviderHashKey),
+			PreviousHashKeys: parseList(env.Get(env.EncryptionProviderPreviousHashKeys)),
+		},
 		Email: Email{
-			APIKeyPublic:             env.MustGet(env.APIKeyPublic),
-			APIKeyPrivate:            env.MustGet(env.APIKeyPrivate),
-			SenderEmail:              env.MustGet(env.SenderEmail),
-			ForgotPasswordTemplateID: env.GetIntOr(env.ForgotPasswordTemplateID, 0),
+			APIKeyPublic:                 env.MustGet(env.APIKeyPublic),
+			APIKeyPrivate:                env.MustGet(env.APIKeyPrivate),
+			SenderEmail:                  env.MustGet(env.SenderEmail),
+			ForgotPasswordTemplateID:     env.GetIntOr(env.ForgotPasswordTemplateID, 0),
+			InvitationTemplateID:         env.GetIntOr(env.InvitationTemplateID, 0),
+			VerificationTemplateID:       env.GetIntOr(env.VerificationTemplateID, 0),
+			AccountActivatedTemplateID:   env.GetIntOr(env.AccountActivatedTemplateID, 0),
+			AccountDeactivatedTemplateID: env.GetIntOr(env.AccountDeactivatedTemplateID, 0),
+			AccountNotActiveTemplateID:   env.GetIntOr(env.AccountNotActiveTemplateID, 0),
+			LoginDelayedTemplateID:       env.GetIntOr(env.LoginDelayedTemplateID, 0),
 		},
 	}
 
+	if err := config.RateLimit.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Account.EmailVerification.validate(); err != nil {
+		return nil, err
+	}
+
+	if config.MFA.MaxAttempts <= 0 {
+		return nil, fmt.Errorf("MFA max attempts must be greater than 0, got %d", config.MFA.MaxAttempts)
+	}
+
 	return config, nil
 }
+
+// parseTrustedProxies parses comma separated IP addresses and CIDR ranges. Single IP address is treated
+// as the range containing only that address.
+func parseTrustedProxies(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// validate checks limits are positive, since limiter can not apply 0 and would let every request through.
+// Rate limit is disabled with Enabled instead.
+func (c RateLimit) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Window <= 0 || c.IPLimit <= 0 || c.EmailLimit <= 0 {
+		return fmt.Errorf("rate limit window and limits must be greater than 0, got window %v, ip limit %d, "+
+			"email limit %d", c.Window, c.IPLimit, c.EmailLimit)
+	}
+
+	return nil
+}
+
+// validate checks the policy is one of the known ones, so misspelled policy does not let unverified users log in.
+func (c EmailVerification) validate() error {
+	switch c.Policy {
+	case EmailVerificationPolicyAllow, EmailVerificationPolicyGrace, EmailVerificationPolicyBlock:
+		return nil
+	default:
+		return fmt.Errorf("invalid email verification policy %q, must be one of %s, %s or %s", c.Policy,
+			EmailVerificationPolicyAllow, EmailVerificationPolicyGrace, EmailVerificationPolicyBlock)
+	}
+}
+
+// parseList parses comma separated values, empty values are skipped.
+func parseList(value string) []string {
+	var values []string
+
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
+	}
+
+	return values
+}
@@ -1,18 +1,24 @@
 package config
 
 import (
+	"net"
 	"time"
 )
 
 // Config stores application configuration.
 type Config struct {
-	Service  Service
-	Database Database
-	Redis    Redis
-	Email    Email
-	Timeouts Timeouts
-	MFA      MFA
-	Account  Account
+	Service    Service
+	Database   Database
+	Redis      Redis
+	Email      Email
+	Timeouts   Timeouts
+	MFA        MFA
+	Account    Account
+	Password   Password
+	JWT        JWT
+	OAuth      OAuth
+	Encryption Encryption
+	RateLimit  RateLimit
 }
 
 // Service contains configuration for service.
@@ -22,10 +28,93 @@ type Service struct {
 	LogLevel    string
 }
 
-// Account contains data related to login attempts.
+// Account contains data related to login attempts, account invitations and registration.
 type Account struct {
-	MaxLoginFailures int
-	BanDurationTime  time.Duration
+	InvitationExpiration time.Duration
+	// RegistrationEnabled lets users sign up on their own, otherwise accounts are created by admin
+	RegistrationEnabled bool
+	// StrictResponses hides whether the account exists, is not active or its login is delayed from
+	// login, password reset and verification email responses. The real reason of failed login or password
+	// reset is recorded to the audit log and sent to the user by email instead.
+	StrictResponses bool
+	// PersonalAccessTokenMaxLifetime limits how far in the future personal access token can expire
+	PersonalAccessTokenMaxLifetime time.Duration
+	EmailVerification              EmailVerification
+	Lockout                        Lockout
+}
+
+// Lockout contains thresholds of failed logins counted per account, per IP address and per account
+// from the same IP address. Once the threshold is reached, each next login is delayed twice as long,
+// starting with BaseDelay. Threshold of 0 disables the counter.
+type Lockout struct {
+	// Window - how long failed logins are remembered since the latest one
+	Window             time.Duration
+	AccountThreshold   int
+	IPThreshold        int
+	AccountIPThreshold int
+	BaseDelay          time.Duration
+	MaxDelay           time.Duration
+	// AccountMaxDelay is kept low, so anyone guessing the password can not lock the user out
+	AccountMaxDelay time.Duration
+}
+
+// Email verification policies applied on login of users with unverified email address.
+const (
+	// EmailVerificationPolicyAllow lets unverified users log in.
+	EmailVerificationPolicyAllow = "allow"
+	// EmailVerificationPolicyGrace lets unverified users log in until grace period since account creation expires.
+	EmailVerificationPolicyGrace = "grace"
+	// EmailVerificationPolicyBlock does not let unverified users log in.
+	EmailVerificationPolicyBlock = "block"
+)
+
+// EmailVerification contains configuration of email address verification.
+type EmailVerification struct {
+	// Policy is one of allow, grace or block
+	Policy      string
+	GracePeriod time.Duration
+	// Expiration - how long the verification link is valid
+	Expiration time.Duration
+	// ResendInterval - minimum time between two verification emails sent to the same address
+	ResendInterval time.Duration
+}
+
+// RateLimit contains limits of requests sent to authentication and recovery endpoints. Limits are
+// applied per endpoint within the sliding window and must be greater than 0 when enabled.
+type RateLimit struct {
+	Enabled bool
+	Window  time.Duration
+	// IPLimit - requests allowed from the same IP address
+	IPLimit int
+	// EmailLimit - requests allowed for the same email address sent in the request body
+	EmailLimit int
+	// TrustedProxies are reverse proxies whose X-Real-IP and X-Forwarded-For headers are used to find the
+	// client IP address. Headers of other clients are ignored, so they can not avoid the limit or record
+	// a different address with their sessions and audit events
+	TrustedProxies []*net.IPNet
+}
+
+// Password contains password policy applied when user sets their password.
+type Password struct {
+	MinLength int
+	// MaxLength can not be more than 72 bytes which is the most bcrypt can hash
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// BreachedListPath is a file with SHA-1 hashes of breached passwords, check is disabled if empty
+	BreachedListPath string
+	// HistorySize is the number of recent passwords which can not be reused, check is disabled if 0
+	HistorySize int
+	// HashAlgorithm is used for new password hashes, either bcrypt or argon2id. Hashes generated with
+	// a different algorithm or parameters are upgraded when user logs in
+	HashAlgorithm string
+	BcryptCost    int
+	// Argon2Memory is in KiB
+	Argon2Memory      int
+	Argon2Iterations  int
+	Argon2Parallelism int
 }
 
 // Timeouts contains configuration for read and write timeouts.
@@ -54,11 +143,45 @@ type Redis struct {
 	TokenTTL  time.Duration
 }
 
+// Encryption contains configuration for encryption of personal data stored in the database.
+type Encryption struct {
+	// HashKey is the secret keys used to encrypt columns and compute blind indexes are derived from
+	HashKey string
+	// PreviousHashKeys are secrets used before HashKey was rotated, values encrypted with them can
+	// still be decrypted and are encrypted with HashKey on startup
+	PreviousHashKeys []string
+}
+
 // MFA contains data for multi factor authentication.
 type MFA struct {
 	TemporaryTokenExpiration time.Duration
 	AccessTokenExpiration    time.Duration
 	RefreshTokenExpiration   time.Duration
+	// Issuer is shown in authenticator app next to the account name
+	Issuer             string
+	RecoveryCodesCount int
+	// MaxAttempts - invalid codes allowed with the same temporary token, it is revoked after that
+	MaxAttempts int
+}
+
+// JWT contains configuration used to sign and verify access tokens.
+type JWT struct {
+	// SigningAlgorithm is one of HS256, RS256 or EdDSA
+	SigningAlgorithm string
+	// KeysDirectory contains PEM encoded keys used by RS256 and EdDSA algorithms
+	KeysDirectory string
+	// RotationInterval - how often a new signing key is generated, rotation is disabled if zero
+	RotationInterval time.Duration
+}
+
+// OAuth contains configuration of OAuth2 / OpenID Connect authorization server.
+type OAuth struct {
+	// Issuer is public base URL of this service, used as "iss" claim
+	Issuer string
+	// AuthorizationPageURL is login page which handles authorization requests of third-party apps
+	AuthorizationPageURL        string
+	AuthorizationCodeExpiration time.Duration
+	AccessTokenExpiration       time.Duration
 }
 
 // Email is configuration for email service.
@@ -67,4 +190,12 @@ type Email struct {
 	APIKeyPublic             string
 	APIKeyPrivate            string
 	ForgotPasswordTemplateID int
+	InvitationTemplateID     int
+	VerificationTemplateID   int
+	// Account status templates are used to notify user their account was activated or deactivated
+	AccountActivatedTemplateID   int
+	AccountDeactivatedTemplateID int
+	// Templates sent in strict mode, where the reason of failed login or password reset is not returned
+	AccountNotActiveTemplateID int
+	LoginDelayedTemplateID     int
 }
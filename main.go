@@ -15,9 +15,11 @@ import (
 
 	"github.com/adinovcina/golang-setup/config"
 	"github.com/adinovcina/golang-setup/services"
+	"github.com/adinovcina/golang-setup/tools/encryption"
 	"github.com/adinovcina/golang-setup/tools/logger"
 	"github.com/adinovcina/golang-setup/tools/mysql"
 	r "github.com/adinovcina/golang-setup/tools/redis"
+	"github.com/adinovcina/golang-setup/tools/signing"
 	"github.com/rs/zerolog"
 
 	mysqlstore "github.com/adinovcina/golang-setup/store/mysql"
@@ -61,6 +63,8 @@ type Main struct {
 
 	DB          *mysql.DB
 	RedisClient *redis.Client
+
+	Keyring *signing.Keyring
 }
 
 // NewMain creates a new instance of Main.
@@ -119,8 +123,16 @@ func NewMain() *Main {
 
 // Run executes the main application logic.
 func (main *Main) Run() error {
+	// Keys used to encrypt personal data are derived from the configured secret, previous secrets are
+	// only used to decrypt data until it is encrypted with the current one
+	fieldEncryptor, err := encryption.NewFieldEncryptor(main.conf.Encryption.HashKey,
+		main.conf.Encryption.PreviousHashKeys...)
+	if err != nil {
+		return err
+	}
+
 	// Initialize the database store
-	mysqlStore := mysqlstore.New(main.DB.GetDB(), main.conf)
+	mysqlStore := mysqlstore.New(main.DB.GetDB(), main.conf, fieldEncryptor)
 
 	// Initialize the in-memory store
 	redisStore := redisstore.New(main.RedisClient)
@@ -133,14 +145,41 @@ func (main *Main) Run() error {
 		}
 	}
 
+	// Encrypt personal data and MFA secrets of users stored before encryption was enabled or the key was rotated
+	if err := mysqlStore.EncryptUserFields(); err != nil {
+		return err
+	}
+
+	if err := mysqlStore.EncryptMFASecrets(); err != nil {
+		return err
+	}
+
 	// Initialize third-party services
-	appServices := services.Init(main.conf)
+	appServices, err := services.Init(main.conf)
+	if err != nil {
+		return err
+	}
+
+	// Load keys used to sign access tokens and start scheduled key rotation
+	keyring, err := signing.NewKeyring(signing.Options{
+		Algorithm:          main.conf.JWT.SigningAlgorithm,
+		Secret:             main.conf.Redis.SecretKey,
+		KeysDirectory:      main.conf.JWT.KeysDirectory,
+		RotationInterval:   main.conf.JWT.RotationInterval,
+		VerificationPeriod: main.conf.MFA.AccessTokenExpiration,
+	})
+	if err != nil {
+		return err
+	}
+
+	keyring.Start()
+	main.Keyring = keyring
 
 	// Start the HTTP server and listen for incoming requests
 	go func() {
 		logger.Fatal().
 			Err(handlers.
-				Attach(main.HTTPServer, mysqlStore, redisStore, main.conf, appServices).
+				Attach(main.HTTPServer, mysqlStore, redisStore, main.conf, appServices, keyring).
 				Serve())
 	}()
 
@@ -155,6 +194,10 @@ func (main *Main) Close() error {
 		}
 	}
 
+	if main.Keyring != nil {
+		main.Keyring.Stop()
+	}
+
 	if main.DB != nil {
 		if err := main.DB.Close(); err != nil {
 			return err
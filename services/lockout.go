@@ -0,0 +1,19 @@
+package services
+
+import (
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/tools/lockout"
+)
+
+// newLockoutPolicy Initialize.
+func newLockoutPolicy(appConfig config.Lockout) *lockout.Policy {
+	// INITIALIZE LOCKOUT POLICY
+	return lockout.NewPolicy(lockout.Options{
+		AccountThreshold:   appConfig.AccountThreshold,
+		IPThreshold:        appConfig.IPThreshold,
+		AccountIPThreshold: appConfig.AccountIPThreshold,
+		BaseDelay:          appConfig.BaseDelay,
+		MaxDelay:           appConfig.MaxDelay,
+		AccountMaxDelay:    appConfig.AccountMaxDelay,
+	})
+}
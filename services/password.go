@@ -0,0 +1,35 @@
+package services
+
+import (
+	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/tools/encryption"
+	"github.com/adinovcina/golang-setup/tools/password"
+)
+
+// newPasswordPolicy Initialize.
+func newPasswordPolicy(appConfig config.Password) (*password.Policy, error) {
+	// INITIALIZE PASSWORD POLICY
+	return password.NewPolicy(password.Options{
+		MinLength:        appConfig.MinLength,
+		MaxLength:        appConfig.MaxLength,
+		RequireUpper:     appConfig.RequireUpper,
+		RequireLower:     appConfig.RequireLower,
+		RequireDigit:     appConfig.RequireDigit,
+		RequireSymbol:    appConfig.RequireSymbol,
+		BreachedListPath: appConfig.BreachedListPath,
+	})
+}
+
+// newPasswordHasher Initialize.
+func newPasswordHasher(appConfig config.Password) (encryption.Hasher, error) {
+	// INITIALIZE PASSWORD HASHER
+	return encryption.NewHasher(encryption.HasherOptions{
+		Algorithm:  appConfig.HashAlgorithm,
+		BcryptCost: appConfig.BcryptCost,
+		Argon2id: encryption.Argon2idParams{
+			Memory:      uint32(appConfig.Argon2Memory),     //nolint:gosec // configured by the operator
+			Iterations:  uint32(appConfig.Argon2Iterations), //nolint:gosec // configured by the operator
+			Parallelism: uint8(appConfig.Argon2Parallelism), //nolint:gosec // configured by the operator
+		},
+	})
+}
@@ -2,24 +2,60 @@ package services
 
 import (
 	"github.com/adinovcina/golang-setup/config"
+	"github.com/adinovcina/golang-setup/tools/encryption"
+	"github.com/adinovcina/golang-setup/tools/lockout"
 	"github.com/adinovcina/golang-setup/tools/mailjet"
+	"github.com/adinovcina/golang-setup/tools/password"
 )
 
 type AppServices struct {
 	mailjetService *mailjet.Client
+	passwordPolicy *password.Policy
+	passwordHasher encryption.Hasher
+	lockoutPolicy  *lockout.Policy
 }
 
 // Init will initialize services.
-func Init(appConfig *config.Config) *AppServices {
+func Init(appConfig *config.Config) (*AppServices, error) {
 	// Initialize Mailjet
 	mailjetService := newMailjetService(appConfig.Email)
 
+	// Initialize password policy, list of breached passwords is opened and searched on disk
+	passwordPolicy, err := newPasswordPolicy(appConfig.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize password hasher, unknown algorithm is a configuration error
+	passwordHasher, err := newPasswordHasher(appConfig.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	return &AppServices{
 		mailjetService: mailjetService,
-	}
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+		lockoutPolicy:  newLockoutPolicy(appConfig.Account.Lockout),
+	}, nil
 }
 
 // GetMailjetClient returns the Mailjet client.
 func (s *AppServices) GetMailjetClient() *mailjet.Client {
 	return s.mailjetService
 }
+
+// GetPasswordPolicy returns the password policy.
+func (s *AppServices) GetPasswordPolicy() *password.Policy {
+	return s.passwordPolicy
+}
+
+// GetPasswordHasher returns the password hasher.
+func (s *AppServices) GetPasswordHasher() encryption.Hasher {
+	return s.passwordHasher
+}
+
+// GetLockoutPolicy returns the policy which delays logins after too many failures.
+func (s *AppServices) GetLockoutPolicy() *lockout.Policy {
+	return s.lockoutPolicy
+}
@@ -4,23 +4,44 @@ import (
 	"context"
 	"time"
 
+	"github.com/adinovcina/golang-setup/tools/lockout"
 	"github.com/twinj/uuid"
 )
 
 type AccountRepository interface {
 	ResetFailedLoginCounter(userID uuid.UUID) error
-	UpdateLoginAttempt(loggedUserID uuid.UUID, minutes float64, maxLoginFailures int) (int64, error)
-	AddLoginToken(userID uuid.UUID, expirationTime int64, token, tokenType string) error
-	SetPassword(userID uuid.UUID, password, token string) (*User, error)
-	SetNewPassword(userID uuid.UUID, password string) error
+	SetLoginFailures(userID uuid.UUID, failedLoginCount int64, blockedUntil time.Time) error
+	AddLoginToken(userID uuid.UUID, expirationTime int64, token, tokenType, sessionID, familyID string, roleID int64) error
+	SetPassword(userID uuid.UUID, password, token string, historySize int) (*User, error)
+	SetNewPassword(userID uuid.UUID, password string, historySize int) error
+	GetPasswordHistory(userID uuid.UUID, historySize int) ([]string, error)
+	UpdatePasswordHash(userID uuid.UUID, currentHash, newHash string) error
 	GetUserRoles(userID uuid.UUID) ([]*Role, error)
 }
 
 type AccountInMemRepository interface {
-	SetSession(ctx context.Context, uid uuid.UUID, sid, v string, redisTokenTTL time.Duration) error
+	SetSession(ctx context.Context, uid uuid.UUID, session *Session, v string, redisTokenTTL time.Duration) error
 	GetSession(ctx context.Context, uid uuid.UUID, sid string) (string, error)
+	GetSessions(ctx context.Context, uid uuid.UUID) ([]*Session, error)
+	TouchSession(ctx context.Context, uid uuid.UUID, sid string, seenAt time.Time) error
 	DelSession(ctx context.Context, uid uuid.UUID, sid string) error
+	DelSessions(ctx context.Context, uid uuid.UUID, exceptSID string) ([]string, error)
 	DelSessionWithKey(ctx context.Context, key string) error
+	Throttle(ctx context.Context, key string, interval time.Duration) (bool, error)
+	RateLimit(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+	GetLoginFailures(ctx context.Context, uid *uuid.UUID, ip string) (*lockout.Failures, error)
+	AddLoginFailure(ctx context.Context, uid *uuid.UUID, ip string, window time.Duration) (*lockout.Failures, error)
+	DelLoginFailures(ctx context.Context, uid uuid.UUID, ip string) error
+}
+
+// Session contains metadata about the device user is logged in from.
+type Session struct {
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ID         string    `json:"id"`
+	IPAddress  string    `json:"ipAddress"`
+	UserAgent  string    `json:"userAgent"`
+	Current    bool      `json:"current"`
 }
 
 // GetRoles Get all available Roles.
@@ -37,11 +58,12 @@ type Roles struct {
 	Admin Role
 }
 
-// Role is object with name and value.
+// Role is object with name and value. Permissions contains names of the permissions granted to the role.
 type Role struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
-	ID    int64  `json:"id"`
+	Name        string   `json:"name"`
+	Value       string   `json:"value"`
+	Permissions []string `json:"permissions,omitempty"`
+	ID          int64    `json:"id"`
 }
 
 // RolesDataResponse contains list of all roles.
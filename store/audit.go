@@ -0,0 +1,99 @@
+package store
+
+import (
+	"time"
+
+	"github.com/twinj/uuid"
+)
+
+type AuditRepository interface {
+	CreateAuditEvent(event *AuditEvent) error
+	GetAuditEvents(filter *AuditFilter) ([]*AuditEvent, error)
+}
+
+// GetAuditActions get actions recorded in the audit log.
+func GetAuditActions() AuditActions {
+	return AuditActions{
+		LoginSucceeded:       "login.succeeded",
+		LoginFailed:          "login.failed",
+		Logout:               "logout",
+		PasswordResetRequest: "password.reset_requested",
+		PasswordReset:        "password.reset",
+		PasswordChanged:      "password.changed",
+		ProfileUpdated:       "profile.updated",
+		MFAEnabled:           "mfa.enabled",
+		MFADisabled:          "mfa.disabled",
+		UserCreated:          "user.created",
+		UserReinvited:        "user.reinvited",
+		UserUpdated:          "user.updated",
+		UserActivated:        "user.activated",
+		UserDeactivated:      "user.deactivated",
+		UserUnlocked:         "user.unlocked",
+		UserRolesChanged:     "user.roles_changed",
+		UserDeleted:          "user.deleted",
+		UserRestored:         "user.restored",
+		SessionsRevoked:      "sessions.revoked",
+		AccessTokenCreated:   "access_token.created",
+		AccessTokenRevoked:   "access_token.revoked",
+		ImpersonationStarted: "impersonation.started",
+		ImpersonationEnded:   "impersonation.ended",
+	}
+}
+
+// AuditActions struct used to describe audit actions.
+type AuditActions struct {
+	LoginSucceeded       string
+	LoginFailed          string
+	Logout               string
+	PasswordResetRequest string
+	PasswordReset        string
+	PasswordChanged      string
+	ProfileUpdated       string
+	MFAEnabled           string
+	MFADisabled          string
+	UserCreated          string
+	UserReinvited        string
+	UserUpdated          string
+	UserActivated        string
+	UserDeactivated      string
+	UserUnlocked         string
+	UserRolesChanged     string
+	UserDeleted          string
+	UserRestored         string
+	SessionsRevoked      string
+	AccessTokenCreated   string
+	AccessTokenRevoked   string
+	ImpersonationStarted string
+	ImpersonationEnded   string
+}
+
+// AuditEvent model. Actor is the user who performed the action and target the user action was
+// performed on, either of them is empty when not known, like for failed login with unknown email.
+type AuditEvent struct {
+	CreatedAt time.Time         `json:"createdAt"`
+	ActorID   *uuid.UUID        `json:"actorID,omitempty"`
+	TargetID  *uuid.UUID        `json:"targetID,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	Action    string            `json:"action"`
+	IPAddress string            `json:"ipAddress"`
+	UserAgent string            `json:"userAgent"`
+	RequestID string            `json:"requestID"`
+	ID        int64             `json:"id"`
+}
+
+type AuditFilter struct {
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+	Action   *string
+	From     *time.Time
+	To       *time.Time
+}
+
+// AuditKeyToColumnMap - a map of sorting keys matching their DB values.
+func AuditKeyToColumnMap() map[string]string {
+	auditToColumnMap := map[string]string{
+		"id": "a.id",
+	}
+
+	return auditToColumnMap
+}
@@ -0,0 +1,32 @@
+package store
+
+import "github.com/twinj/uuid"
+
+const (
+	MFANotFound = "mfa not found"
+)
+
+type MFARepository interface {
+	GetUserMFA(userID uuid.UUID) (*UserMFA, error)
+	SetUserMFASecret(userID uuid.UUID, secret string) error
+	EnableUserMFA(userID uuid.UUID, step int64) error
+	DisableUserMFA(userID uuid.UUID) error
+	UpdateMFALastUsedStep(userID uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	GetRecoveryCodes(userID uuid.UUID) ([]*RecoveryCode, error)
+	UseRecoveryCode(id int64) (bool, error)
+}
+
+// UserMFA contains TOTP enrollment of the user.
+type UserMFA struct {
+	LastUsedStep *int64
+	Secret       string
+	UserID       uuid.UUID
+	Enabled      bool
+}
+
+// RecoveryCode is single use code which can be used instead of TOTP code.
+type RecoveryCode struct {
+	CodeHash string
+	ID       int64
+}
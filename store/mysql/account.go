@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/adinovcina/golang-setup/store"
 	"github.com/adinovcina/golang-setup/tools/logger"
@@ -30,45 +31,42 @@ func (r *Repository) ResetFailedLoginCounter(userID uuid.UUID) error {
 	return nil
 }
 
-// UpdateLoginAttempt used for saving failed login count when code is incorrect.
-func (r *Repository) UpdateLoginAttempt(loggedUserID uuid.UUID, minutes float64, maxLoginFailures int) (int64, error) {
-	var failedLoginCount int64
-
-	query, err := r.db.Prepare("CALL UpdateLoginAttempt(?, ?, ?)")
+// SetLoginFailures saves number of failed logins to the account and time until login is delayed. Counters are
+// kept in memory, this is only a summary of the latest lockout.
+func (r *Repository) SetLoginFailures(userID uuid.UUID, failedLoginCount int64, blockedUntil time.Time) error {
+	query, err := r.db.Prepare("CALL SetLoginFailures(?, ?, ?)")
 	if err != nil {
-		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UpdateLoginAttempt(%v, %v, %v).",
-			loggedUserID, minutes, maxLoginFailures)
-		return failedLoginCount, err
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL SetLoginFailures(%v, %v, %v).",
+			userID, failedLoginCount, blockedUntil)
+		return err
 	}
 
 	defer query.Close()
 
-	err = query.QueryRow(loggedUserID, minutes, maxLoginFailures).Scan(&failedLoginCount)
-	if errors.Is(err, sql.ErrNoRows) {
-		return failedLoginCount, nil
-	}
-
+	_, err = query.Exec(userID, failedLoginCount, blockedUntil)
 	if err != nil {
-		logger.Error().Err(err).Msgf("failed to execute statement: CALL UpdateLoginAttempt(%v, %v, %v).",
-			loggedUserID, minutes, maxLoginFailures)
-		return failedLoginCount, err
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL SetLoginFailures(%v, %v, %v).",
+			userID, failedLoginCount, blockedUntil)
+		return err
 	}
 
-	return failedLoginCount, nil
+	return nil
 }
 
 // AddLoginToken will add login token to DB and return roles associated with user
-func (r *Repository) AddLoginToken(userID uuid.UUID, expirationTime int64, token, tokenType string) error {
-	query, err := r.db.Prepare("CALL AddLoginToken(?, ?, ?, ?)")
+func (r *Repository) AddLoginToken(userID uuid.UUID, expirationTime int64, token, tokenType, sessionID, familyID string,
+	roleID int64,
+) error {
+	query, err := r.db.Prepare("CALL AddLoginToken(?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
-		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AddLoginToken(%v, %v, %v, %v).",
-			userID, token, tokenType, expirationTime)
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AddLoginToken(%v, %v, %v, %v, %v, %v, %v).",
+			userID, token, tokenType, expirationTime, sessionID, familyID, roleID)
 		return err
 	}
 
 	defer query.Close()
 
-	rows, err := query.Query(userID, token, tokenType, expirationTime)
+	rows, err := query.Query(userID, token, tokenType, expirationTime, sessionID, familyID, roleID)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil
 	}
@@ -78,8 +76,8 @@ func (r *Repository) AddLoginToken(userID uuid.UUID, expirationTime int64, token
 	}
 
 	if err != nil {
-		logger.Error().Err(err).Msgf("There was an error executing query: CALL AddLoginToken(%v, %v, %v, %v).",
-			userID, token, tokenType, expirationTime)
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL AddLoginToken(%v, %v, %v, %v, %v, %v, %v).",
+			userID, token, tokenType, expirationTime, sessionID, familyID, roleID)
 		return err
 	}
 
@@ -121,9 +119,10 @@ func (r *Repository) GetUserRoles(userID uuid.UUID) ([]*store.Role, error) {
 	return userRoles, nil
 }
 
-// SetPassword used by user to update his password field in database.
-func (r *Repository) SetPassword(userID uuid.UUID, password, token string) (*store.User, error) {
-	query, err := r.db.Prepare("CALL SetPassword(?, ?, ?)")
+// SetPassword used by user to update his password field in database. Password is added to the
+// password history, which keeps the latest historySize passwords.
+func (r *Repository) SetPassword(userID uuid.UUID, password, token string, historySize int) (*store.User, error) {
+	query, err := r.db.Prepare("CALL SetPassword(?, ?, ?, ?)")
 	if err != nil {
 		logger.Error().Err(err).Msgf("failed to prepare statement: CALL SetPassword(%v, %v, %v).",
 			userID, password, token)
@@ -134,7 +133,7 @@ func (r *Repository) SetPassword(userID uuid.UUID, password, token string) (*sto
 
 	user := new(store.User)
 
-	err = query.QueryRow(userID, password, token).
+	err = query.QueryRow(userID, password, token, historySize).
 		Scan(&user.ID, &user.Name, &user.Email, &user.Phone, &user.Language, &user.Active,
 			&user.Phone, &user.Password, &user.Role, &user.RoleID, &user.CreatedAt)
 	if err != nil {
@@ -143,46 +142,58 @@ func (r *Repository) SetPassword(userID uuid.UUID, password, token string) (*sto
 		return nil, err
 	}
 
+	if err := r.decryptUser(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
-// Activate will update user's active status.
-func (r *Repository) ActivateUser(userID uuid.UUID) error {
-	query, err := r.db.Prepare("CALL ActivateUser(?)")
+// SetUserActive sets the active status of the user and logs the change along with the reason.
+// Setting the status user already has is a no-op, reported as not changed.
+func (r *Repository) SetUserActive(userID uuid.UUID, active bool, reason string, changedBy uuid.UUID) (bool, error) {
+	query, err := r.db.Prepare("CALL SetUserActive(?, ?, ?, ?)")
 	if err != nil {
-		logger.Error().Err(err).Msgf("failed to prepare statement: CALL ActivateUser(%v)", userID)
-
-		return err
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL SetUserActive(%v, %v)", userID, active)
+		return false, err
 	}
 
 	defer query.Close()
 
-	res, err := query.Exec(userID)
-	if err != nil {
-		logger.Error().Err(err).Msgf("failed to execute statement CALL ActivateUser(%v)", userID)
-		return err
+	var changed bool
+
+	if err = query.QueryRow(userID, active, reason, changedBy).Scan(&changed); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement CALL SetUserActive(%v, %v)", userID, active)
+		return false, err
 	}
 
-	ra, err := res.RowsAffected()
+	return changed, nil
+}
+
+// HasUserActivationLog checks if admin ever changed the active status of the user.
+func (r *Repository) HasUserActivationLog(userID uuid.UUID) (bool, error) {
+	query, err := r.db.Prepare("CALL HasUserActivationLog(?)")
 	if err != nil {
-		logger.Error().Err(err).Msgf(
-			"failed to execute statement CALL ActivateUser(%v)", userID)
-		return err
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL HasUserActivationLog(%v)", userID)
+		return false, err
 	}
 
-	if ra == 0 {
-		err = fmt.Errorf("unable to activate user %v", userID)
-		logger.Error().Err(err).Msgf("Rows Affected = 0 for statement: CALL ActivateUser(%v)", userID)
+	defer query.Close()
 
-		return err
+	var exists bool
+
+	if err = query.QueryRow(userID).Scan(&exists); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement CALL HasUserActivationLog(%v)", userID)
+		return false, err
 	}
 
-	return nil
+	return exists, nil
 }
 
-// SetNewPassword sets a new password for logged user.
-func (r *Repository) SetNewPassword(userID uuid.UUID, password string) error {
-	query, err := r.db.Prepare("CALL SetNewPassword(?, ?)")
+// SetNewPassword sets a new password for logged user. Password is added to the password history,
+// which keeps the latest historySize passwords.
+func (r *Repository) SetNewPassword(userID uuid.UUID, password string, historySize int) error {
+	query, err := r.db.Prepare("CALL SetNewPassword(?, ?, ?)")
 	if err != nil {
 		logger.Error().Err(err).Msgf("failed to prepare statement: CALL SetNewPassword(%v, %s).", userID, password)
 		return err
@@ -190,7 +201,7 @@ func (r *Repository) SetNewPassword(userID uuid.UUID, password string) error {
 
 	defer query.Close()
 
-	res, err := query.Exec(userID, password)
+	res, err := query.Exec(userID, password, historySize)
 	if err != nil {
 		logger.Error().Err(err).Msgf("failed to EXECUTE statement for:  CALL SetNewPassword(%v, %s).", userID, password)
 		return err
@@ -224,7 +235,17 @@ func (r *Repository) UpdateUser(user *store.User) (*store.User, error) {
 
 	defer query.Close()
 
-	err = query.QueryRow(user.ID, user.Name, user.Phone).
+	encryptedName, err := r.encryptor.Encrypt(user.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedPhone, err := r.encryptor.Encrypt(user.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.QueryRow(user.ID, encryptedName, encryptedPhone).
 		Scan(&user.ID,
 			&user.Name,
 			&user.Email,
@@ -245,6 +266,10 @@ func (r *Repository) UpdateUser(user *store.User) (*store.User, error) {
 		return nil, err
 	}
 
+	if err := r.decryptUser(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -272,3 +297,59 @@ func fetchUserRoles(rows *sql.Rows) ([]*store.Role, error) {
 
 	return userRoles, nil
 }
+
+// UpdatePasswordHash replaces hash of the user password with a hash of the same password generated with
+// the current algorithm and parameters. Hash is not replaced if the password was changed in the meantime.
+func (r *Repository) UpdatePasswordHash(userID uuid.UUID, currentHash, newHash string) error {
+	query, err := r.db.Prepare("CALL UpdatePasswordHash(?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UpdatePasswordHash(%v).", userID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID, currentHash, newHash); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL UpdatePasswordHash(%v).", userID)
+		return err
+	}
+
+	return nil
+}
+
+// GetPasswordHistory will retrieve hashes of the current and the latest historySize passwords of the user.
+func (r *Repository) GetPasswordHistory(userID uuid.UUID, historySize int) ([]string, error) {
+	query, err := r.db.Prepare("CALL GetPasswordHistory(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetPasswordHistory(%v, %v).", userID, historySize)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	rows, err := query.Query(userID, historySize)
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL GetPasswordHistory(%v, %v).", userID, historySize)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	passwords := make([]string, 0)
+
+	for rows.Next() {
+		var password string
+
+		if err := rows.Scan(&password); err != nil {
+			return nil, err
+		}
+
+		passwords = append(passwords, password)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return passwords, nil
+}
@@ -2,6 +2,7 @@ package mysqlstore
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/adinovcina/golang-setup/store"
@@ -61,3 +62,139 @@ func (s *RepositorySuite) TestGetUserRoles() {
 		})
 	}
 }
+
+func (s *RepositorySuite) TestGetPasswordHistory() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		historySize int
+		expected    []string
+	}{
+		{
+			name: "Success Case",
+			queryResult: sqlmock.NewRows([]string{"Password"}).
+				AddRow("$2a$10$current").
+				AddRow("$2a$10$previous"),
+			historySize: 5,
+			expected:    []string{"$2a$10$current", "$2a$10$previous"},
+		},
+		{
+			name:        "Success Case - No passwords set",
+			queryResult: sqlmock.NewRows([]string{"Password"}),
+			historySize: 5,
+			expected:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetPasswordHistory\\(\\?, \\?\\)$").
+				ExpectQuery().
+				WithArgs(userID, tt.historySize).
+				WillReturnRows(tt.queryResult)
+
+			passwords, err := s.repo.GetPasswordHistory(userID, tt.historySize)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, passwords)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestSetUserActive() {
+	userID := uuid.NewV4()
+	adminID := uuid.NewV4()
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		active      bool
+		expected    bool
+	}{
+		{
+			name:        "Success Case - Status changed",
+			queryResult: sqlmock.NewRows([]string{"Changed"}).AddRow(true),
+			active:      false,
+			expected:    true,
+		},
+		{
+			name:        "Success Case - User already has the status",
+			queryResult: sqlmock.NewRows([]string{"Changed"}).AddRow(false),
+			active:      true,
+			expected:    false,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL SetUserActive\\(\\?, \\?, \\?, \\?\\)$").
+				ExpectQuery().
+				WithArgs(userID, tt.active, "reason", adminID).
+				WillReturnRows(tt.queryResult)
+
+			changed, err := s.repo.SetUserActive(userID, tt.active, "reason", adminID)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, changed)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestHasUserActivationLog() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		expected    bool
+	}{
+		{
+			name:        "Success Case - Status changed by admin",
+			queryResult: sqlmock.NewRows([]string{"Exists"}).AddRow(true),
+			expected:    true,
+		},
+		{
+			name:        "Success Case - Status never changed",
+			queryResult: sqlmock.NewRows([]string{"Exists"}).AddRow(false),
+			expected:    false,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL HasUserActivationLog\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(userID).
+				WillReturnRows(tt.queryResult)
+
+			exists, err := s.repo.HasUserActivationLog(userID)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, exists)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestSetLoginFailures() {
+	userID := uuid.NewV4()
+	blockedUntil := time.Now().Add(time.Minute).UTC()
+
+	s.mock.ExpectPrepare("^CALL SetLoginFailures\\(\\?, \\?, \\?\\)$").
+		ExpectExec().
+		WithArgs(userID, int64(25), blockedUntil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.repo.SetLoginFailures(userID, 25, blockedUntil)
+	s.Require().NoError(err)
+
+	err = s.mock.ExpectationsWereMet()
+	s.Require().NoError(err)
+}
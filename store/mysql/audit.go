@@ -0,0 +1,213 @@
+package mysqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/adinovcina/golang-setup/tools/utils"
+)
+
+// Lengths of audit_events columns filled from request headers.
+const (
+	auditIPAddressMaxLength = 45
+	auditUserAgentMaxLength = 500
+	auditRequestIDMaxLength = 100
+)
+
+// CreateAuditEvent appends the event to the audit log. Values taken from request headers are truncated,
+// so the client can not prevent the event from being recorded by sending a long header.
+func (r *Repository) CreateAuditEvent(event *store.AuditEvent) error {
+	var details sql.NullString
+
+	if len(event.Details) > 0 {
+		marshaled, err := json.Marshal(event.Details)
+		if err != nil {
+			return err
+		}
+
+		details = sql.NullString{String: string(marshaled), Valid: true}
+	}
+
+	query, err := r.db.Prepare("CALL CreateAuditEvent(?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreateAuditEvent(%v, %v, %v).",
+			event.Action, event.ActorID, event.TargetID)
+		return err
+	}
+
+	defer query.Close()
+
+	_, err = query.Exec(event.Action, event.ActorID, event.TargetID,
+		utils.Truncate(event.IPAddress, auditIPAddressMaxLength),
+		utils.Truncate(event.UserAgent, auditUserAgentMaxLength),
+		utils.Truncate(event.RequestID, auditRequestIDMaxLength),
+		details)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL CreateAuditEvent(%v, %v, %v).",
+			event.Action, event.ActorID, event.TargetID)
+		return err
+	}
+
+	return nil
+}
+
+// GetAuditEvents will retrieve list of audit events based on filter and cursor pagination parameters.
+func (r *Repository) GetAuditEvents(filter *store.AuditFilter) ([]*store.AuditEvent, error) {
+	limit := r.PaginatorCursor().GetLimit()
+	// Get order key and direction values, with defaults specified
+	key, direction := r.PaginatorCursor().Order("a.id", "desc", store.AuditKeyToColumnMap())
+
+	var err error
+	// Build WHERE clause. Each segment of the clause is AND-ed together.
+	// Values are appended to args so we can avoid SQL injection.
+	where, args := []string{"1 = 1"}, []interface{}{}
+
+	// Build paginator where clause based on page direction
+	where, args, err = r.PaginatorCursor().BuildWhereClause(where, args, key, direction)
+	if err != nil {
+		return nil, fmt.Errorf("build where clause: %w", err)
+	}
+
+	if v := filter.ActorID; v != nil {
+		where, args = append(where, "a.actor_id = ?"), append(args, *v)
+	}
+	if v := filter.TargetID; v != nil {
+		where, args = append(where, "a.target_id = ?"), append(args, *v)
+	}
+	if v := filter.Action; v != nil {
+		where, args = append(where, "a.action = ?"), append(args, *v)
+	}
+	if v := filter.From; v != nil {
+		where, args = append(where, "a.created_at >= ?"), append(args, *v)
+	}
+	if v := filter.To; v != nil {
+		where, args = append(where, "a.created_at < ?"), append(args, *v)
+	}
+
+	// Determine default sorting.
+	sortBy := fmt.Sprintf("%s %s",
+		key, r.PaginatorCursor().GetOrderByCursorDirection(direction))
+
+	query := `SELECT a.id,
+		a.action,
+		a.actor_id,
+		a.target_id,
+		a.ip_address,
+		a.user_agent,
+		a.request_id,
+		a.details,
+		a.created_at
+	FROM
+		audit_events a
+	WHERE `
+
+	query += strings.Join(where, " AND ")
+	query += ` ORDER BY ` + sortBy + `
+	` + r.PaginatorCursor().FormatLimit(limit+1)
+
+	// For previous page, the main query will be subquery,
+	// because we need to reverse the result set order again to
+	// get the correct order
+	if r.PaginatorCursor().GetCursorDirection() == "previous" {
+		sortByReverse := fmt.Sprintf("%s %s", key, direction)
+
+		query = `SELECT a.id,
+					a.action,
+					a.actor_id,
+					a.target_id,
+					a.ip_address,
+					a.user_agent,
+					a.request_id,
+					a.details,
+					a.created_at
+				FROM(
+					` + query + `
+				) AS a
+				ORDER BY ` + sortByReverse
+	}
+
+	events := make([]*store.AuditEvent, 0)
+
+	// Prepare the query
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	// Execute the query
+	rows, err := stmt.Query(args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return events, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// get result length before slicing
+	resultLength := len(events)
+
+	if len(events) > 0 {
+		if r.PaginatorCursor().GetCursorDirection() == "next" {
+			if len(events) > limit {
+				events = events[:limit]
+			}
+		} else {
+			if len(events) > limit {
+				events = events[1:]
+			}
+		}
+
+		// Paginate
+		r.PaginatorCursor().Paginate(events[0].ID, events[len(events)-1].ID, resultLength)
+	}
+
+	return events, nil
+}
+
+func scanAuditEvent(row rowScanner) (*store.AuditEvent, error) {
+	event := new(store.AuditEvent)
+
+	var details []byte
+
+	err := row.Scan(&event.ID,
+		&event.Action,
+		&event.ActorID,
+		&event.TargetID,
+		&event.IPAddress,
+		&event.UserAgent,
+		&event.RequestID,
+		&details,
+		&event.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(details) > 0 {
+		if err := json.Unmarshal(details, &event.Details); err != nil {
+			return nil, err
+		}
+	}
+
+	return event, nil
+}
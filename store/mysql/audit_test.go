@@ -0,0 +1,79 @@
+package mysqlstore
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/stretchr/testify/require"
+	"github.com/twinj/uuid"
+)
+
+func (s *RepositorySuite) TestCreateAuditEvent() {
+	actorID := uuid.NewV4()
+	targetID := uuid.NewV4()
+
+	tests := []struct {
+		name     string
+		event    *store.AuditEvent
+		expected []driver.Value
+	}{
+		{
+			name: "Success Case",
+			event: &store.AuditEvent{
+				Action:    "user.deactivated",
+				ActorID:   &actorID,
+				TargetID:  &targetID,
+				IPAddress: "127.0.0.1",
+				UserAgent: "test",
+				RequestID: "request",
+				Details:   map[string]string{"reason": "left the company"},
+			},
+			expected: []driver.Value{
+				"user.deactivated", &actorID, &targetID, "127.0.0.1", "test", "request",
+				`{"reason":"left the company"}`,
+			},
+		},
+		{
+			name: "Success Case - Oversized headers",
+			event: &store.AuditEvent{
+				Action:    "login.failed",
+				IPAddress: strings.Repeat("1", 100),
+				UserAgent: strings.Repeat("a", 10000),
+				RequestID: strings.Repeat("r", 1000),
+			},
+			expected: []driver.Value{
+				"login.failed", nil, nil, strings.Repeat("1", 45), strings.Repeat("a", 500),
+				strings.Repeat("r", 100), nil,
+			},
+		},
+		{
+			name: "Success Case - Unknown actor and target",
+			event: &store.AuditEvent{
+				Action:    "login.failed",
+				IPAddress: "127.0.0.1",
+				RequestID: "request",
+			},
+			expected: []driver.Value{
+				"login.failed", nil, nil, "127.0.0.1", "", "request", nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL CreateAuditEvent\\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)$").
+				ExpectExec().
+				WithArgs(tt.expected...).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			err := s.repo.CreateAuditEvent(tt.event)
+
+			require.NoError(t, err)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
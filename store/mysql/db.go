@@ -5,6 +5,7 @@ import (
 
 	"github.com/adinovcina/golang-setup/config"
 	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/encryption"
 	"github.com/adinovcina/golang-setup/tools/paging"
 )
 
@@ -20,15 +21,19 @@ type Repository struct {
 
 	conf *config.Config
 
+	// encryptor encrypts personal data stored in the users table
+	encryptor *encryption.FieldEncryptor
+
 	paginator paging.Paginator
 
 	paginatorCursor paging.PaginatorCursor
 }
 
-func New(db *sql.DB, conf *config.Config) *Repository {
+func New(db *sql.DB, conf *config.Config, encryptor *encryption.FieldEncryptor) *Repository {
 	return &Repository{
-		db:   db,
-		conf: conf,
+		db:        db,
+		conf:      conf,
+		encryptor: encryptor,
 	}
 }
 
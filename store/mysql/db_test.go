@@ -2,10 +2,12 @@ package mysqlstore
 
 import (
 	"database/sql/driver"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adinovcina/golang-setup/tools/encryption"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -26,10 +28,14 @@ func TestProviderSuite(t *testing.T) {
 func (s *RepositorySuite) SetupSuite() {
 	// initializes a mocked DB and a mock object
 	testDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
 	s.mock = mock
-	repo := New(testDB, nil)
-	s.repo = repo
+
+	encryptor, err := encryption.NewFieldEncryptor("0123456789abcdef0123456789abcdef")
 	s.Require().NoError(err)
+
+	repo := New(testDB, nil, encryptor)
+	s.repo = repo
 	s.Require().NotNil(repo)
 }
 
@@ -45,3 +51,34 @@ func (a AnyTime) Match(v driver.Value) bool {
 	_, ok := v.(time.Time)
 	return ok
 }
+
+// EncryptedValue matches the value encrypted by the field encryptor with the current key.
+type EncryptedValue struct {
+	encryptor *encryption.FieldEncryptor
+	value     string
+}
+
+// Match satisfies sqlmock.Argument interface.
+func (e EncryptedValue) Match(v driver.Value) bool {
+	encrypted, ok := v.(string)
+	if !ok || !strings.HasPrefix(encrypted, e.encryptor.KeyPrefix()) {
+		return false
+	}
+
+	decrypted, err := e.encryptor.Decrypt(encrypted)
+
+	return err == nil && decrypted == e.value
+}
+
+// encrypt returns the value encrypted the same way users' personal data is stored.
+func (s *RepositorySuite) encrypt(value string) string {
+	encrypted, err := s.repo.encryptor.Encrypt(value)
+	s.Require().NoError(err)
+
+	return encrypted
+}
+
+// encryptLegacy returns the value encrypted the way it was stored before the key id was added to encrypted values.
+func (s *RepositorySuite) encryptLegacy(value string) string {
+	return "enc:v1:" + strings.TrimPrefix(s.encrypt(value), s.repo.encryptor.KeyPrefix())
+}
@@ -0,0 +1,189 @@
+package mysqlstore
+
+import (
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/twinj/uuid"
+)
+
+// EncryptUserFields encrypts name, email and phone of users stored before encryption was enabled or
+// before the key was rotated and computes their email blind index, so they can be looked up by email.
+func (r *Repository) EncryptUserFields() error {
+	query, err := r.db.Prepare("CALL GetUsersToEncrypt(?)")
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to prepare statement: CALL GetUsersToEncrypt(?).")
+		return err
+	}
+
+	defer query.Close()
+
+	rows, err := query.Query(r.encryptor.KeyPrefix())
+	if err != nil {
+		logger.Error().Err(err).Msg("There was an error executing query: CALL GetUsersToEncrypt(?).")
+		return err
+	}
+
+	defer rows.Close()
+
+	users := make([]*store.User, 0)
+
+	for rows.Next() {
+		user := new(store.User)
+
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Phone); err != nil {
+			return err
+		}
+
+		// Decrypted first, in case values were encrypted but index was not set or the key was rotated
+		if err := r.decryptUser(user); err != nil {
+			return err
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := r.setUserEncryptedFields(user); err != nil {
+			return err
+		}
+	}
+
+	if len(users) > 0 {
+		logger.Info().Msgf("encrypted personal data of %d users with the current key", len(users))
+	}
+
+	return nil
+}
+
+func (r *Repository) setUserEncryptedFields(user *store.User) error {
+	query, err := r.db.Prepare("CALL SetUserEncryptedFields(?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL SetUserEncryptedFields(%v).", user.ID)
+		return err
+	}
+
+	defer query.Close()
+
+	encryptedName, err := r.encryptor.Encrypt(user.Name)
+	if err != nil {
+		return err
+	}
+
+	encryptedEmail, err := r.encryptor.Encrypt(user.Email)
+	if err != nil {
+		return err
+	}
+
+	encryptedPhone, err := r.encryptor.Encrypt(user.Phone)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.Exec(user.ID, encryptedName, encryptedEmail, r.encryptor.BlindIndex(user.Email), encryptedPhone)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL SetUserEncryptedFields(%v).", user.ID)
+		return err
+	}
+
+	return nil
+}
+
+// decryptUser decrypts personal data of the user read from the database.
+func (r *Repository) decryptUser(user *store.User) error {
+	var err error
+
+	if user.Name, err = r.encryptor.Decrypt(user.Name); err != nil {
+		logger.Error().Err(err).Msgf("failed to decrypt name of user %v", user.ID)
+		return err
+	}
+
+	if user.Email, err = r.encryptor.Decrypt(user.Email); err != nil {
+		logger.Error().Err(err).Msgf("failed to decrypt email of user %v", user.ID)
+		return err
+	}
+
+	if user.Phone, err = r.encryptor.Decrypt(user.Phone); err != nil {
+		logger.Error().Err(err).Msgf("failed to decrypt phone of user %v", user.ID)
+		return err
+	}
+
+	return nil
+}
+
+// EncryptMFASecrets encrypts MFA secrets stored before encryption was enabled or before the key was rotated.
+func (r *Repository) EncryptMFASecrets() error {
+	query, err := r.db.Prepare("CALL GetUnencryptedMFASecrets(?)")
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to prepare statement: CALL GetUnencryptedMFASecrets(?).")
+		return err
+	}
+
+	defer query.Close()
+
+	rows, err := query.Query(r.encryptor.KeyPrefix())
+	if err != nil {
+		logger.Error().Err(err).Msg("There was an error executing query: CALL GetUnencryptedMFASecrets(?).")
+		return err
+	}
+
+	defer rows.Close()
+
+	secrets := make([]*store.UserMFA, 0)
+
+	for rows.Next() {
+		mfa := new(store.UserMFA)
+
+		if err := rows.Scan(&mfa.UserID, &mfa.Secret); err != nil {
+			return err
+		}
+
+		if mfa.Secret, err = r.encryptor.Decrypt(mfa.Secret); err != nil {
+			logger.Error().Err(err).Msgf("failed to decrypt MFA secret of user %v", mfa.UserID)
+			return err
+		}
+
+		secrets = append(secrets, mfa)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, mfa := range secrets {
+		if err := r.updateUserMFASecret(mfa.UserID, mfa.Secret); err != nil {
+			return err
+		}
+	}
+
+	if len(secrets) > 0 {
+		logger.Info().Msgf("encrypted MFA secrets of %d users with the current key", len(secrets))
+	}
+
+	return nil
+}
+
+func (r *Repository) updateUserMFASecret(userID uuid.UUID, secret string) error {
+	query, err := r.db.Prepare("CALL UpdateUserMFASecret(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UpdateUserMFASecret(%v).", userID)
+		return err
+	}
+
+	defer query.Close()
+
+	encryptedSecret, err := r.encryptor.Encrypt(secret)
+	if err != nil {
+		return err
+	}
+
+	if _, err = query.Exec(userID, encryptedSecret); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL UpdateUserMFASecret(%v).", userID)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package mysqlstore
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/twinj/uuid"
+)
+
+func (s *RepositorySuite) TestEncryptUserFields() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		userName    string
+		email       string
+		phone       string
+	}{
+		{
+			name: "Success Case - Plain text values",
+			queryResult: sqlmock.NewRows([]string{"ID", "Name", "Email", "Phone"}).
+				AddRow(userID, "test user", "admin@gmail.com", "+12341245"),
+			userName: "test user",
+			email:    "admin@gmail.com",
+			phone:    "+12341245",
+		},
+		{
+			name: "Success Case - Values encrypted without index",
+			queryResult: sqlmock.NewRows([]string{"ID", "Name", "Email", "Phone"}).
+				AddRow(userID, s.encrypt("test user"), s.encrypt("admin@gmail.com"), ""),
+			userName: "test user",
+			email:    "admin@gmail.com",
+			phone:    "",
+		},
+		{
+			name: "Success Case - Values encrypted without key id",
+			queryResult: sqlmock.NewRows([]string{"ID", "Name", "Email", "Phone"}).
+				AddRow(userID, s.encryptLegacy("test user"), s.encryptLegacy("admin@gmail.com"),
+					s.encryptLegacy("+12341245")),
+			userName: "test user",
+			email:    "admin@gmail.com",
+			phone:    "+12341245",
+		},
+		{
+			name:        "Success Case - All users encrypted",
+			queryResult: sqlmock.NewRows([]string{"ID", "Name", "Email", "Phone"}),
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetUsersToEncrypt\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(s.repo.encryptor.KeyPrefix()).
+				WillReturnRows(tt.queryResult)
+
+			if tt.email != "" {
+				var phone interface{} = ""
+				if tt.phone != "" {
+					phone = EncryptedValue{s.repo.encryptor, tt.phone}
+				}
+
+				s.mock.ExpectPrepare("^CALL SetUserEncryptedFields\\(\\?, \\?, \\?, \\?, \\?\\)$").
+					ExpectExec().
+					WithArgs(userID, EncryptedValue{s.repo.encryptor, tt.userName},
+						EncryptedValue{s.repo.encryptor, tt.email}, s.repo.encryptor.BlindIndex(tt.email), phone).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			err := s.repo.EncryptUserFields()
+			require.NoError(t, err)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestEncryptMFASecrets() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		secret      string
+	}{
+		{
+			name:        "Success Case - Plain text secret",
+			queryResult: sqlmock.NewRows([]string{"UserID", "Secret"}).AddRow(userID, "JBSWY3DPEHPK3PXP"),
+			secret:      "JBSWY3DPEHPK3PXP",
+		},
+		{
+			name: "Success Case - Secret encrypted without key id",
+			queryResult: sqlmock.NewRows([]string{"UserID", "Secret"}).
+				AddRow(userID, s.encryptLegacy("JBSWY3DPEHPK3PXP")),
+			secret: "JBSWY3DPEHPK3PXP",
+		},
+		{
+			name:        "Success Case - All secrets encrypted",
+			queryResult: sqlmock.NewRows([]string{"UserID", "Secret"}),
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetUnencryptedMFASecrets\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(s.repo.encryptor.KeyPrefix()).
+				WillReturnRows(tt.queryResult)
+
+			if tt.secret != "" {
+				s.mock.ExpectPrepare("^CALL UpdateUserMFASecret\\(\\?, \\?\\)$").
+					ExpectExec().
+					WithArgs(userID, EncryptedValue{s.repo.encryptor, tt.secret}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			err := s.repo.EncryptMFASecrets()
+			require.NoError(t, err)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package mysqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/twinj/uuid"
+)
+
+// GetUserMFA will retrieve MFA enrollment of the user.
+func (r *Repository) GetUserMFA(userID uuid.UUID) (*store.UserMFA, error) {
+	query, err := r.db.Prepare("CALL GetUserMFA(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetUserMFA(%v).", userID)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	mfa := new(store.UserMFA)
+
+	err = query.QueryRow(userID).Scan(&mfa.UserID, &mfa.Secret, &mfa.Enabled, &mfa.LastUsedStep)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.MFANotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL GetUserMFA(%v).", userID)
+		return nil, err
+	}
+
+	if mfa.Secret, err = r.encryptor.Decrypt(mfa.Secret); err != nil {
+		logger.Error().Err(err).Msgf("failed to decrypt MFA secret of user %v", userID)
+		return nil, err
+	}
+
+	return mfa, nil
+}
+
+// SetUserMFASecret stores a new secret for the user encrypted, since anyone who can read it can generate
+// valid codes. MFA stays disabled until the secret is confirmed.
+func (r *Repository) SetUserMFASecret(userID uuid.UUID, secret string) error {
+	encryptedSecret, err := r.encryptor.Encrypt(secret)
+	if err != nil {
+		return err
+	}
+
+	query, err := r.db.Prepare("CALL SetUserMFASecret(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL SetUserMFASecret(%v).", userID)
+		return err
+	}
+
+	defer query.Close()
+
+	_, err = query.Exec(userID, encryptedSecret)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL SetUserMFASecret(%v).", userID)
+		return err
+	}
+
+	return nil
+}
+
+// EnableUserMFA enables MFA for the user and stores time step of the code used for confirmation.
+func (r *Repository) EnableUserMFA(userID uuid.UUID, step int64) error {
+	query, err := r.db.Prepare("CALL EnableUserMFA(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL EnableUserMFA(%v, %v).", userID, step)
+		return err
+	}
+
+	defer query.Close()
+
+	res, err := query.Exec(userID, step)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL EnableUserMFA(%v, %v).", userID, step)
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed after statement is executed: CALL EnableUserMFA(%v, %v).", userID, step)
+		return err
+	}
+
+	if ra == 0 {
+		return errors.New(store.MFANotFound)
+	}
+
+	return nil
+}
+
+// DisableUserMFA removes MFA enrollment and recovery codes of the user.
+func (r *Repository) DisableUserMFA(userID uuid.UUID) error {
+	query, err := r.db.Prepare("CALL DisableUserMFA(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DisableUserMFA(%v).", userID)
+		return err
+	}
+
+	defer query.Close()
+
+	_, err = query.Exec(userID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DisableUserMFA(%v).", userID)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateMFALastUsedStep stores time step of the accepted code. False is returned if
+// the same or a newer step was already used, which means the code is replayed.
+func (r *Repository) UpdateMFALastUsedStep(userID uuid.UUID, step int64) (bool, error) {
+	query, err := r.db.Prepare("CALL UpdateMFALastUsedStep(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UpdateMFALastUsedStep(%v, %v).", userID, step)
+		return false, err
+	}
+
+	defer query.Close()
+
+	res, err := query.Exec(userID, step)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL UpdateMFALastUsedStep(%v, %v).", userID, step)
+		return false, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed after statement is executed: CALL UpdateMFALastUsedStep(%v, %v).", userID, step)
+		return false, err
+	}
+
+	return ra > 0, nil
+}
+
+// ReplaceRecoveryCodes deletes existing recovery codes of the user and stores the new ones.
+func (r *Repository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to begin transaction for recovery codes of user %v.", userID)
+		return err
+	}
+
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is no-op
+
+	deleteQuery, err := tx.Prepare("CALL DeleteRecoveryCodes(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteRecoveryCodes(%v).", userID)
+		return err
+	}
+
+	defer deleteQuery.Close()
+
+	if _, err = deleteQuery.Exec(userID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteRecoveryCodes(%v).", userID)
+		return err
+	}
+
+	addQuery, err := tx.Prepare("CALL AddRecoveryCode(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AddRecoveryCode(%v).", userID)
+		return err
+	}
+
+	defer addQuery.Close()
+
+	for _, codeHash := range codeHashes {
+		if _, err = addQuery.Exec(userID, codeHash); err != nil {
+			logger.Error().Err(err).Msgf("failed to execute statement: CALL AddRecoveryCode(%v).", userID)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetRecoveryCodes will retrieve unused recovery codes of the user.
+func (r *Repository) GetRecoveryCodes(userID uuid.UUID) ([]*store.RecoveryCode, error) {
+	query, err := r.db.Prepare("CALL GetRecoveryCodes(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetRecoveryCodes(%v).", userID)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	codes := make([]*store.RecoveryCode, 0)
+
+	rows, err := query.Query(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return codes, nil
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL GetRecoveryCodes(%v).", userID)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		code := new(store.RecoveryCode)
+
+		if err := rows.Scan(&code.ID, &code.CodeHash); err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// UseRecoveryCode marks recovery code as used. False is returned if code was already used.
+func (r *Repository) UseRecoveryCode(id int64) (bool, error) {
+	query, err := r.db.Prepare("CALL UseRecoveryCode(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UseRecoveryCode(%v).", id)
+		return false, err
+	}
+
+	defer query.Close()
+
+	res, err := query.Exec(id)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL UseRecoveryCode(%v).", id)
+		return false, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed after statement is executed: CALL UseRecoveryCode(%v).", id)
+		return false, err
+	}
+
+	return ra > 0, nil
+}
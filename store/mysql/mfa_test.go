@@ -0,0 +1,144 @@
+package mysqlstore
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/stretchr/testify/require"
+	"github.com/twinj/uuid"
+)
+
+func (s *RepositorySuite) TestGetUserMFA() {
+	userID := uuid.NewV4()
+	lastUsedStep := int64(37037036)
+
+	columns := []string{"UserID", "Secret", "Enabled", "LastUsedStep"}
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		queryParam  uuid.UUID
+		expected    *store.UserMFA
+		expectErr   bool
+		errorMsg    interface{}
+	}{
+		{
+			name:        "Success Case",
+			queryResult: sqlmock.NewRows(columns).AddRow(userID.String(), "JBSWY3DPEHPK3PXP", true, lastUsedStep),
+			queryParam:  userID,
+			expected: &store.UserMFA{
+				UserID:       userID,
+				Secret:       "JBSWY3DPEHPK3PXP",
+				Enabled:      true,
+				LastUsedStep: &lastUsedStep,
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name:        "Success Case - Encrypted secret",
+			queryResult: sqlmock.NewRows(columns).AddRow(userID.String(), s.encrypt("JBSWY3DPEHPK3PXP"), true, lastUsedStep),
+			queryParam:  userID,
+			expected: &store.UserMFA{
+				UserID:       userID,
+				Secret:       "JBSWY3DPEHPK3PXP",
+				Enabled:      true,
+				LastUsedStep: &lastUsedStep,
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name:        "Success Case - Enrollment not confirmed",
+			queryResult: sqlmock.NewRows(columns).AddRow(userID.String(), "JBSWY3DPEHPK3PXP", false, nil),
+			queryParam:  userID,
+			expected: &store.UserMFA{
+				UserID: userID,
+				Secret: "JBSWY3DPEHPK3PXP",
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name:        "Error Case - MFA not enrolled",
+			queryResult: sqlmock.NewRows([]string{}),
+			queryParam:  userID,
+			expectErr:   true,
+			errorMsg:    store.MFANotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetUserMFA\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(tt.queryParam).
+				WillReturnRows(tt.queryResult)
+
+			mfa, err := s.repo.GetUserMFA(tt.queryParam)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				require.Equal(t, tt.errorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, mfa)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestSetUserMFASecret() {
+	userID := uuid.NewV4()
+
+	s.mock.ExpectPrepare("^CALL SetUserMFASecret\\(\\?, \\?\\)$").
+		ExpectExec().
+		WithArgs(userID, EncryptedValue{s.repo.encryptor, "JBSWY3DPEHPK3PXP"}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.repo.SetUserMFASecret(userID, "JBSWY3DPEHPK3PXP")
+	s.Require().NoError(err)
+
+	err = s.mock.ExpectationsWereMet()
+	s.Require().NoError(err)
+}
+
+func (s *RepositorySuite) TestUpdateMFALastUsedStep() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{
+			name:         "Success Case - Step accepted",
+			rowsAffected: 1,
+			expected:     true,
+		},
+		{
+			name:         "Success Case - Step already used",
+			rowsAffected: 0,
+			expected:     false,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL UpdateMFALastUsedStep\\(\\?, \\?\\)$").
+				ExpectExec().
+				WithArgs(userID, int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			updated, err := s.repo.UpdateMFALastUsedStep(userID, 100)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, updated)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+-- *****************************************************************************************
+-- TABLE tasks
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS tasks (
+	id SERIAL,
+    title VARCHAR(250) NOT NULL,
+    description TEXT NOT NULL,
+    -- One of TODO, IN_PROGRESS, DONE, CANCELLED
+    status VARCHAR(50) NOT NULL DEFAULT 'TODO',
+    -- ID of the user who created the task
+    created_by CHAR(36) NOT NULL,
+    -- ID of the user this task is assigned to
+    assigned_to CHAR(36) NULL,
+    due_date DATETIME NULL,
+    -- required for tracking purposes
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	PRIMARY KEY (id),
+    INDEX `idx_tasks_status` (`status`),
+    CONSTRAINT fk_tasks_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_tasks_assigned_to FOREIGN KEY (assigned_to) REFERENCES users (id) ON DELETE SET NULL ON UPDATE CASCADE
+);
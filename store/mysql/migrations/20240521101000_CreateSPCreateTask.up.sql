@@ -0,0 +1,35 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreateTask
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreateTask;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreateTask (
+    IN inTitle VARCHAR(250),
+    IN inDescription TEXT,
+    IN inCreatedBy CHAR(36),
+    IN inAssignedTo CHAR(36),
+    IN inDueDate DATETIME
+)
+BEGIN
+
+    INSERT INTO tasks (title, description, created_by, assigned_to, due_date)
+    VALUES (inTitle, inDescription, inCreatedBy, inAssignedTo, inDueDate);
+
+    -- Select last inserted ID from the tasks
+    SET @taskID = LAST_INSERT_ID();
+
+    SELECT t.id,
+        t.title,
+        t.description,
+        t.status,
+        t.created_by,
+        t.assigned_to,
+        t.due_date,
+        t.created_at,
+        t.updated_at
+    FROM tasks t
+    WHERE t.id = @taskID;
+
+END;
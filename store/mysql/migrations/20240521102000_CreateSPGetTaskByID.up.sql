@@ -0,0 +1,25 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetTaskByID
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetTaskByID;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetTaskByID (
+    IN inID BIGINT
+)
+BEGIN
+
+    SELECT t.id,
+        t.title,
+        t.description,
+        t.status,
+        t.created_by,
+        t.assigned_to,
+        t.due_date,
+        t.created_at,
+        t.updated_at
+    FROM tasks t
+    WHERE t.id = inID;
+
+END;
@@ -0,0 +1,34 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UpdateTask
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UpdateTask;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UpdateTask (
+    IN inID BIGINT,
+    IN inTitle VARCHAR(250),
+    IN inDescription TEXT,
+    IN inDueDate DATETIME
+)
+BEGIN
+
+    UPDATE tasks
+    SET title = inTitle,
+        description = inDescription,
+        due_date = inDueDate
+    WHERE id = inID;
+
+    SELECT t.id,
+        t.title,
+        t.description,
+        t.status,
+        t.created_by,
+        t.assigned_to,
+        t.due_date,
+        t.created_at,
+        t.updated_at
+    FROM tasks t
+    WHERE t.id = inID;
+
+END;
@@ -0,0 +1,30 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE AssignTask
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS AssignTask;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE AssignTask (
+    IN inID BIGINT,
+    IN inAssignedTo CHAR(36)
+)
+BEGIN
+
+    UPDATE tasks
+    SET assigned_to = inAssignedTo
+    WHERE id = inID;
+
+    SELECT t.id,
+        t.title,
+        t.description,
+        t.status,
+        t.created_by,
+        t.assigned_to,
+        t.due_date,
+        t.created_at,
+        t.updated_at
+    FROM tasks t
+    WHERE t.id = inID;
+
+END;
@@ -0,0 +1,30 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UpdateTaskStatus
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UpdateTaskStatus;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UpdateTaskStatus (
+    IN inID BIGINT,
+    IN inStatus VARCHAR(50)
+)
+BEGIN
+
+    UPDATE tasks
+    SET status = inStatus
+    WHERE id = inID;
+
+    SELECT t.id,
+        t.title,
+        t.description,
+        t.status,
+        t.created_by,
+        t.assigned_to,
+        t.due_date,
+        t.created_at,
+        t.updated_at
+    FROM tasks t
+    WHERE t.id = inID;
+
+END;
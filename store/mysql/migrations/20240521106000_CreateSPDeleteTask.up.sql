@@ -0,0 +1,16 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteTask
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteTask;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteTask (
+    IN inID BIGINT
+)
+BEGIN
+
+    DELETE FROM tasks
+    WHERE id = inID;
+
+END;
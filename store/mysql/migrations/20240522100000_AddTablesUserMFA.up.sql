@@ -0,0 +1,35 @@
+-- *****************************************************************************************
+-- TABLE user_mfa
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id CHAR(36) NOT NULL,
+    -- Base32 encoded TOTP secret
+    secret VARCHAR(500) NOT NULL,
+    -- MFA is enforced on login only after user confirms enrollment with a valid code
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Last accepted TOTP time step, used to reject replay of the same code
+    last_used_step BIGINT NULL,
+    enabled_at DATETIME NULL,
+    -- required for tracking purposes
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_user_mfa_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+-- *****************************************************************************************
+-- TABLE user_recovery_codes
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id SERIAL,
+    user_id CHAR(36) NOT NULL,
+    -- Recovery codes are stored hashed, the same way as passwords
+    code_hash VARCHAR(500) NOT NULL,
+    used_at DATETIME NULL,
+    -- required for tracking purposes
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_user_recovery_codes_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
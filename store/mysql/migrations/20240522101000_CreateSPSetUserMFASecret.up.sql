@@ -0,0 +1,22 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE SetUserMFASecret
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS SetUserMFASecret;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE SetUserMFASecret (
+    IN inUserID CHAR(36),
+    IN inSecret VARCHAR(500)
+)
+BEGIN
+
+    INSERT INTO user_mfa (user_id, secret, enabled)
+    VALUES (inUserID, inSecret, FALSE)
+    ON DUPLICATE KEY UPDATE
+        secret = inSecret,
+        enabled = FALSE,
+        last_used_step = NULL,
+        enabled_at = NULL;
+
+END;
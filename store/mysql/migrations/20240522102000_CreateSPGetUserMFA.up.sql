@@ -0,0 +1,20 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetUserMFA
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetUserMFA;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetUserMFA (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    SELECT m.user_id,
+        m.secret,
+        m.enabled,
+        m.last_used_step
+    FROM user_mfa m
+    WHERE m.user_id = inUserID;
+
+END;
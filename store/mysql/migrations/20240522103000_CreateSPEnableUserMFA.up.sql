@@ -0,0 +1,20 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE EnableUserMFA
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS EnableUserMFA;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE EnableUserMFA (
+    IN inUserID CHAR(36),
+    IN inStep BIGINT
+)
+BEGIN
+
+    UPDATE user_mfa
+    SET enabled = TRUE,
+        enabled_at = NOW(),
+        last_used_step = inStep
+    WHERE user_id = inUserID;
+
+END;
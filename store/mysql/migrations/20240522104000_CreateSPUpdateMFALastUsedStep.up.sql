@@ -0,0 +1,21 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UpdateMFALastUsedStep
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UpdateMFALastUsedStep;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UpdateMFALastUsedStep (
+    IN inUserID CHAR(36),
+    IN inStep BIGINT
+)
+BEGIN
+
+    -- Update only if step is newer than the last accepted one, so the same code can not be used twice
+    UPDATE user_mfa
+    SET last_used_step = inStep
+    WHERE user_id = inUserID
+        AND enabled = TRUE
+        AND (last_used_step IS NULL OR last_used_step < inStep);
+
+END;
@@ -0,0 +1,19 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DisableUserMFA
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DisableUserMFA;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DisableUserMFA (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    DELETE FROM user_recovery_codes
+    WHERE user_id = inUserID;
+
+    DELETE FROM user_mfa
+    WHERE user_id = inUserID;
+
+END;
@@ -0,0 +1,16 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteRecoveryCodes
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteRecoveryCodes;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteRecoveryCodes (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    DELETE FROM user_recovery_codes
+    WHERE user_id = inUserID;
+
+END;
@@ -0,0 +1,17 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE AddRecoveryCode
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS AddRecoveryCode;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE AddRecoveryCode (
+    IN inUserID CHAR(36),
+    IN inCodeHash VARCHAR(500)
+)
+BEGIN
+
+    INSERT INTO user_recovery_codes (user_id, code_hash)
+    VALUES (inUserID, inCodeHash);
+
+END;
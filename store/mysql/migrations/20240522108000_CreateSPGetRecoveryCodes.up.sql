@@ -0,0 +1,19 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetRecoveryCodes
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetRecoveryCodes;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetRecoveryCodes (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    SELECT c.id,
+        c.code_hash
+    FROM user_recovery_codes c
+    WHERE c.user_id = inUserID
+        AND c.used_at IS NULL;
+
+END;
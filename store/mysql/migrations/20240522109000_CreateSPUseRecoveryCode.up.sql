@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UseRecoveryCode
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UseRecoveryCode;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UseRecoveryCode (
+    IN inID BIGINT
+)
+BEGIN
+
+    UPDATE user_recovery_codes
+    SET used_at = NOW()
+    WHERE id = inID
+        AND used_at IS NULL;
+
+END;
@@ -0,0 +1,44 @@
+-- *****************************************************************************************
+-- TABLE oauth_clients
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id VARCHAR(100) NOT NULL,
+    name VARCHAR(250) NOT NULL,
+    -- Hashed client secret, NULL for public clients (SPA, mobile) which must use PKCE
+    secret_hash VARCHAR(500) NULL,
+    -- Space separated lists
+    redirect_uris TEXT NOT NULL,
+    grant_types VARCHAR(250) NOT NULL,
+    scopes VARCHAR(500) NOT NULL,
+    -- ID of the admin who registered the client
+    created_by CHAR(36) NULL,
+    -- required for tracking purposes
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_oauth_clients_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+-- *****************************************************************************************
+-- TABLE oauth_authorization_codes
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+    -- SHA-256 hash of the code, plain code is only sent to the client
+    code_hash CHAR(64) NOT NULL,
+    client_id VARCHAR(100) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    redirect_uri TEXT NOT NULL,
+    scope VARCHAR(500) NOT NULL,
+    code_challenge VARCHAR(128) NOT NULL,
+    code_challenge_method VARCHAR(10) NOT NULL,
+    nonce VARCHAR(250) NOT NULL DEFAULT '',
+    expires_at DATETIME NOT NULL,
+    -- Code can be exchanged only once
+    used_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (code_hash),
+    CONSTRAINT fk_oauth_authorization_codes_client_id FOREIGN KEY (client_id) REFERENCES oauth_clients (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_oauth_authorization_codes_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
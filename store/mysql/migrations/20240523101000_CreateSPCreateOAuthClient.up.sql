@@ -0,0 +1,32 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreateOAuthClient
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreateOAuthClient;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreateOAuthClient (
+    IN inID VARCHAR(100),
+    IN inName VARCHAR(250),
+    IN inSecretHash VARCHAR(500),
+    IN inRedirectURIs TEXT,
+    IN inGrantTypes VARCHAR(250),
+    IN inScopes VARCHAR(500),
+    IN inCreatedBy CHAR(36)
+)
+BEGIN
+
+    INSERT INTO oauth_clients (id, name, secret_hash, redirect_uris, grant_types, scopes, created_by)
+    VALUES (inID, inName, inSecretHash, inRedirectURIs, inGrantTypes, inScopes, inCreatedBy);
+
+    SELECT c.id,
+        c.name,
+        c.secret_hash,
+        c.redirect_uris,
+        c.grant_types,
+        c.scopes,
+        c.created_at
+    FROM oauth_clients c
+    WHERE c.id = inID;
+
+END;
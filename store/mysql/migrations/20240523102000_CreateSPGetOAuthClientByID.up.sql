@@ -0,0 +1,23 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetOAuthClientByID
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetOAuthClientByID;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetOAuthClientByID (
+    IN inID VARCHAR(100)
+)
+BEGIN
+
+    SELECT c.id,
+        c.name,
+        c.secret_hash,
+        c.redirect_uris,
+        c.grant_types,
+        c.scopes,
+        c.created_at
+    FROM oauth_clients c
+    WHERE c.id = inID;
+
+END;
@@ -0,0 +1,16 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteOAuthClient
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteOAuthClient;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteOAuthClient (
+    IN inID VARCHAR(100)
+)
+BEGIN
+
+    DELETE FROM oauth_clients
+    WHERE id = inID;
+
+END;
@@ -0,0 +1,26 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreateAuthorizationCode
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreateAuthorizationCode;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreateAuthorizationCode (
+    IN inCodeHash CHAR(64),
+    IN inClientID VARCHAR(100),
+    IN inUserID CHAR(36),
+    IN inRedirectURI TEXT,
+    IN inScope VARCHAR(500),
+    IN inCodeChallenge VARCHAR(128),
+    IN inCodeChallengeMethod VARCHAR(10),
+    IN inNonce VARCHAR(250),
+    IN inExpiresAt DATETIME
+)
+BEGIN
+
+    INSERT INTO oauth_authorization_codes (code_hash, client_id, user_id, redirect_uri, scope,
+        code_challenge, code_challenge_method, nonce, expires_at)
+    VALUES (inCodeHash, inClientID, inUserID, inRedirectURI, inScope,
+        inCodeChallenge, inCodeChallengeMethod, inNonce, inExpiresAt);
+
+END;
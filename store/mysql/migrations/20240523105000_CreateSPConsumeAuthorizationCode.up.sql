@@ -0,0 +1,37 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE ConsumeAuthorizationCode
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS ConsumeAuthorizationCode;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE ConsumeAuthorizationCode (
+    IN inCodeHash CHAR(64)
+)
+BEGIN
+
+    DECLARE consumed INT;
+
+    -- Mark code as used, only the first exchange succeeds
+    UPDATE oauth_authorization_codes
+    SET used_at = NOW()
+    WHERE code_hash = inCodeHash
+        AND used_at IS NULL;
+
+    SET consumed = ROW_COUNT();
+
+    SELECT c.code_hash,
+        c.client_id,
+        c.user_id,
+        c.redirect_uri,
+        c.scope,
+        c.code_challenge,
+        c.code_challenge_method,
+        c.nonce,
+        c.expires_at,
+        (consumed = 0) AS used,
+        (c.expires_at < NOW()) AS expired
+    FROM oauth_authorization_codes c
+    WHERE c.code_hash = inCodeHash;
+
+END;
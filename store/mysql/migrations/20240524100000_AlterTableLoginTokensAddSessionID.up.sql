@@ -0,0 +1,7 @@
+-- *****************************************************************************************
+-- TABLE login_tokens
+-- *****************************************************************************************
+ALTER TABLE login_tokens
+    -- ID of the Redis session refresh token was issued with, NULL for other token types
+    ADD COLUMN session_id VARCHAR(100) NULL AFTER token_type,
+    ADD INDEX idx_login_tokens_user_id_session_id (user_id, session_id);
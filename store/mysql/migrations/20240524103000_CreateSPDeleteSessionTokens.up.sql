@@ -0,0 +1,17 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteSessionTokens
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteSessionTokens;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteSessionTokens (
+    IN inUserID CHAR(36),
+    IN inSessionID VARCHAR(100)
+)
+BEGIN
+
+    DELETE FROM login_tokens
+    WHERE user_id = inUserID AND session_id = inSessionID;
+
+END;
@@ -0,0 +1,21 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteUserTokens
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteUserTokens;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteUserTokens (
+    IN inUserID CHAR(36),
+    -- Tokens issued along with this session are kept, empty value deletes all tokens
+    IN inExceptSessionID VARCHAR(100)
+)
+BEGIN
+
+    DELETE FROM login_tokens
+    WHERE user_id = inUserID
+        AND (NULLIF(inExceptSessionID, '') IS NULL
+            OR session_id IS NULL
+            OR session_id <> inExceptSessionID);
+
+END;
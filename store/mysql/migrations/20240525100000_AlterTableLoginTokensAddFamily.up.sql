@@ -0,0 +1,9 @@
+-- *****************************************************************************************
+-- TABLE login_tokens
+-- *****************************************************************************************
+ALTER TABLE login_tokens
+    -- Refresh tokens rotated from the same login share the family, NULL for other token types
+    ADD COLUMN family_id VARCHAR(100) NULL AFTER session_id,
+    -- Rotated refresh tokens are kept until they expire, so their reuse can be detected
+    ADD COLUMN used_at DATETIME NULL AFTER expires_at,
+    ADD INDEX idx_login_tokens_user_id_family_id (user_id, family_id);
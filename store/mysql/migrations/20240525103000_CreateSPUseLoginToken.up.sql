@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UseLoginToken
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UseLoginToken;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UseLoginToken (
+    IN inTokenID BIGINT
+)
+BEGIN
+
+    UPDATE login_tokens
+    SET used_at = NOW()
+    WHERE id = inTokenID
+        AND used_at IS NULL;
+
+END;
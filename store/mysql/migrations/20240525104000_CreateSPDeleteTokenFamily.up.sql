@@ -0,0 +1,17 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteTokenFamily
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteTokenFamily;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteTokenFamily (
+    IN inUserID CHAR(36),
+    IN inFamilyID VARCHAR(100)
+)
+BEGIN
+
+    DELETE FROM login_tokens
+    WHERE user_id = inUserID AND family_id = inFamilyID;
+
+END;
@@ -0,0 +1,32 @@
+-- *****************************************************************************************
+-- TABLE roles
+-- *****************************************************************************************
+ALTER TABLE roles
+    ADD UNIQUE INDEX idx_roles_name (name);
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+-- *****************************************************************************************
+-- TABLE permissions
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS permissions (
+    id SERIAL,
+    -- Permission name in resource:action format, e.g. users:read
+    name VARCHAR(150) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_permissions_name (name)
+);
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+-- *****************************************************************************************
+-- TABLE role_permissions
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id BIGINT UNSIGNED NOT NULL,
+    permission_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role_id FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_role_permissions_permission_id FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,18 @@
+INSERT INTO permissions (name, description)
+VALUES
+  ('users:read', 'List users and their sessions'),
+  ('users:create', 'Create accounts and invite users'),
+  ('users:update', 'Update profile of any user'),
+  ('users:activate', 'Activate or deactivate users'),
+  ('users:sessions', 'Revoke sessions of any user'),
+  ('roles:read', 'List roles and permissions'),
+  ('roles:manage', 'Create, update and delete roles and assign them to users'),
+  ('clients:manage', 'Register and delete OAuth clients'),
+  ('tasks:manage', 'View and manage tasks of all users');
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+-- Admin role is granted all permissions
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, p.id
+FROM permissions p;
@@ -0,0 +1,20 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetAllRoles
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetAllRoles;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetAllRoles ()
+BEGIN
+
+    SELECT r.id,
+        r.name,
+        COALESCE(GROUP_CONCAT(p.name ORDER BY p.name SEPARATOR ' '), '') AS permissions
+    FROM roles r
+    LEFT JOIN role_permissions rp ON rp.role_id = r.id
+    LEFT JOIN permissions p ON p.id = rp.permission_id
+    GROUP BY r.id, r.name
+    ORDER BY r.id;
+
+END;
@@ -0,0 +1,22 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetRoleByID
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetRoleByID;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetRoleByID (
+    IN inID BIGINT
+)
+BEGIN
+
+    SELECT r.id,
+        r.name,
+        COALESCE(GROUP_CONCAT(p.name ORDER BY p.name SEPARATOR ' '), '') AS permissions
+    FROM roles r
+    LEFT JOIN role_permissions rp ON rp.role_id = r.id
+    LEFT JOIN permissions p ON p.id = rp.permission_id
+    WHERE r.id = inID
+    GROUP BY r.id, r.name;
+
+END;
@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreateRole
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreateRole;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreateRole (
+    IN inName VARCHAR(150)
+)
+BEGIN
+
+    INSERT INTO roles (name)
+    VALUES (inName);
+
+    SELECT LAST_INSERT_ID();
+
+END;
@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UpdateRole
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UpdateRole;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UpdateRole (
+    IN inID BIGINT,
+    IN inName VARCHAR(150)
+)
+BEGIN
+
+    UPDATE roles
+    SET name = inName
+    WHERE id = inID;
+
+END;
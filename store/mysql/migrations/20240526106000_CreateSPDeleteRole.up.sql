@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteRole
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteRole;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteRole (
+    IN inID BIGINT
+)
+BEGIN
+
+    -- Role which is still assigned to users is not deleted
+    DELETE FROM roles
+    WHERE id = inID
+        AND NOT EXISTS (SELECT 1 FROM user_roles ur WHERE ur.role_id = inID);
+
+END;
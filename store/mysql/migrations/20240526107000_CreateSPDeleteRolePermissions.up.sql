@@ -0,0 +1,16 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteRolePermissions
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteRolePermissions;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteRolePermissions (
+    IN inRoleID BIGINT
+)
+BEGIN
+
+    DELETE FROM role_permissions
+    WHERE role_id = inRoleID;
+
+END;
@@ -0,0 +1,19 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE AddRolePermission
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS AddRolePermission;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE AddRolePermission (
+    IN inRoleID BIGINT,
+    IN inPermissionName VARCHAR(150)
+)
+BEGIN
+
+    INSERT INTO role_permissions (role_id, permission_id)
+    SELECT inRoleID, p.id
+    FROM permissions p
+    WHERE p.name = inPermissionName;
+
+END;
@@ -0,0 +1,17 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetAllPermissions
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetAllPermissions;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetAllPermissions ()
+BEGIN
+
+    SELECT p.id,
+        p.name,
+        p.description
+    FROM permissions p
+    ORDER BY p.name;
+
+END;
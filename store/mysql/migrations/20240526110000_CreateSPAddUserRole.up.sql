@@ -0,0 +1,21 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE AddUserRole
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS AddUserRole;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE AddUserRole (
+    IN inUserID CHAR(36),
+    IN inRoleID BIGINT
+)
+BEGIN
+
+    INSERT INTO user_roles (user_id, role_id)
+    SELECT inUserID, inRoleID
+    FROM DUAL
+    WHERE NOT EXISTS (
+        SELECT 1 FROM user_roles ur WHERE ur.user_id = inUserID AND ur.role_id = inRoleID
+    );
+
+END;
@@ -0,0 +1,17 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE RemoveUserRole
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS RemoveUserRole;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE RemoveUserRole (
+    IN inUserID CHAR(36),
+    IN inRoleID BIGINT
+)
+BEGIN
+
+    DELETE FROM user_roles
+    WHERE user_id = inUserID AND role_id = inRoleID;
+
+END;
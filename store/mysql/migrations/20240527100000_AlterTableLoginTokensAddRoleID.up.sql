@@ -0,0 +1,7 @@
+-- *****************************************************************************************
+-- TABLE login_tokens
+-- *****************************************************************************************
+ALTER TABLE login_tokens
+    -- Role user selected for the session refresh token was issued with, NULL for other token types
+    ADD COLUMN role_id BIGINT UNSIGNED NULL AFTER family_id,
+    ADD CONSTRAINT fk_login_tokens_role_id FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE SET NULL ON UPDATE CASCADE;
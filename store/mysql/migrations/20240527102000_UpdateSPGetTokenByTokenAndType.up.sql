@@ -0,0 +1,28 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetTokenByTokenAndType
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetTokenByTokenAndType;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetTokenByTokenAndType (
+	IN inToken TEXT,
+	IN inTokenType VARCHAR(100)
+) 
+BEGIN
+	SET @Now = UNIX_TIMESTAMP(NOW());
+
+	SELECT (lt.expires_at < @Now) AS expired,
+			lt.id,
+			lt.user_id,
+			lt.token,
+			lt.token_type,
+			COALESCE(lt.session_id, '') AS session_id,
+			COALESCE(lt.family_id, '') AS family_id,
+			COALESCE(lt.role_id, 0) AS role_id,
+			(lt.used_at IS NOT NULL) AS used
+	FROM login_tokens lt
+	JOIN users u ON u.id = lt.user_id
+	WHERE lt.token = inToken AND lt.token_type = inTokenType;
+
+END;
@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- TABLE email_verification_tokens
+-- *****************************************************************************************
+-- This table contains tokens sent in email verification links. Email is the address being
+-- verified, which differs from the current user email when user changes their email.
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    -- SHA-256 hash of the token, plain token is only sent in the email
+    token_hash CHAR(64) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    email VARCHAR(250) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    -- Token can be used only once
+    used_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (token_hash),
+    CONSTRAINT fk_email_verification_tokens_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,41 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE VerifyEmail
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS VerifyEmail;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE VerifyEmail (
+    IN inTokenHash CHAR(64)
+)
+BEGIN
+
+    DECLARE vUserID CHAR(36) DEFAULT NULL;
+    DECLARE vEmail VARCHAR(250) DEFAULT NULL;
+
+    SELECT t.user_id, t.email INTO vUserID, vEmail
+    FROM email_verification_tokens t
+    WHERE t.token_hash = inTokenHash
+        AND t.used_at IS NULL
+        AND t.expires_at > NOW();
+
+    IF vUserID IS NOT NULL THEN
+        -- Email is changed first, so the token stays unused if the address was taken in the meantime
+        UPDATE users
+        SET email = vEmail,
+            email_verified = 1
+        WHERE id = vUserID;
+
+        UPDATE email_verification_tokens
+        SET used_at = NOW()
+        WHERE token_hash = inTokenHash;
+    END IF;
+
+    SELECT u.id,
+        u.name,
+        u.email,
+        u.email_verified
+    FROM users u
+    WHERE u.id = vUserID;
+
+END;
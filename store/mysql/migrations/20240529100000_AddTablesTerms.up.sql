@@ -0,0 +1,37 @@
+-- *****************************************************************************************
+-- TABLE terms_documents
+-- *****************************************************************************************
+-- This table contains versions of terms of service. Current version is the most recently
+-- published one, versions with publish date in the future are scheduled.
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS terms_documents (
+    id SERIAL,
+    version VARCHAR(50) NOT NULL,
+    content TEXT NOT NULL,
+    published_at DATETIME NOT NULL,
+    -- ID of the admin who created the version
+    created_by CHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE INDEX `uq_idx_terms_documents_version` (`version`),
+    INDEX `idx_terms_documents_published_at` (`published_at`),
+    CONSTRAINT fk_terms_documents_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+-- *****************************************************************************************
+-- TABLE terms_acceptances
+-- *****************************************************************************************
+-- This table contains record of every terms version user accepted.
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS terms_acceptances (
+    user_id CHAR(36) NOT NULL,
+    terms_id BIGINT UNSIGNED NOT NULL,
+    accepted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- IP address the terms were accepted from, required for tracking purposes
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    PRIMARY KEY (user_id, terms_id),
+    CONSTRAINT fk_terms_acceptances_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_terms_acceptances_terms_id FOREIGN KEY (terms_id) REFERENCES terms_documents (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,10 @@
+INSERT INTO permissions (name, description)
+VALUES ('terms:manage', 'Publish new versions of terms of service');
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+-- Admin role is granted all permissions
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, p.id
+FROM permissions p
+WHERE p.name = 'terms:manage';
@@ -0,0 +1,21 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetCurrentTerms
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetCurrentTerms;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetCurrentTerms ()
+BEGIN
+
+    SELECT t.id,
+        t.version,
+        t.content,
+        t.published_at,
+        t.created_at
+    FROM terms_documents t
+    WHERE t.published_at <= NOW()
+    ORDER BY t.published_at DESC, t.id DESC
+    LIMIT 1;
+
+END;
@@ -0,0 +1,27 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreateTerms
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreateTerms;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreateTerms (
+    IN inVersion VARCHAR(50),
+    IN inContent TEXT,
+    IN inPublishedAt DATETIME,
+    IN inCreatedBy CHAR(36)
+)
+BEGIN
+
+    INSERT INTO terms_documents (version, content, published_at, created_by)
+    VALUES (inVersion, inContent, COALESCE(inPublishedAt, NOW()), inCreatedBy);
+
+    SELECT t.id,
+        t.version,
+        t.content,
+        t.published_at,
+        t.created_at
+    FROM terms_documents t
+    WHERE t.id = LAST_INSERT_ID();
+
+END;
@@ -0,0 +1,26 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE AcceptTerms
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS AcceptTerms;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE AcceptTerms (
+    IN inUserID CHAR(36),
+    IN inTermsID BIGINT UNSIGNED,
+    IN inIPAddress VARCHAR(45)
+)
+BEGIN
+
+    -- First acceptance of the version is kept
+    INSERT INTO terms_acceptances (user_id, terms_id, ip_address)
+    SELECT inUserID, inTermsID, inIPAddress
+    FROM DUAL
+    WHERE NOT EXISTS (
+        SELECT 1
+        FROM terms_acceptances ta
+        WHERE ta.user_id = inUserID
+            AND ta.terms_id = inTermsID
+    );
+
+END;
@@ -0,0 +1,23 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetUserTermsAcceptance
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetUserTermsAcceptance;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetUserTermsAcceptance (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    -- Acceptance of the current version, accepted_at is NULL if user did not accept it
+    SELECT t.id,
+        t.version,
+        ta.accepted_at
+    FROM terms_documents t
+    LEFT JOIN terms_acceptances ta ON ta.terms_id = t.id AND ta.user_id = inUserID
+    WHERE t.published_at <= NOW()
+    ORDER BY t.published_at DESC, t.id DESC
+    LIMIT 1;
+
+END;
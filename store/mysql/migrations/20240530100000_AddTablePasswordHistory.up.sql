@@ -0,0 +1,15 @@
+-- *****************************************************************************************
+-- TABLE password_history
+-- *****************************************************************************************
+-- This table contains hashes of the most recent passwords of the user, so they can not be
+-- reused. Only the configured number of the latest passwords is kept.
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS password_history (
+    id SERIAL,
+    user_id CHAR(36) NOT NULL,
+    password VARCHAR(500) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX `idx_password_history_user_id` (`user_id`),
+    CONSTRAINT fk_password_history_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,32 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE AddPasswordHistory
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS AddPasswordHistory;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE AddPasswordHistory (
+    IN inUserID CHAR(36),
+    IN inPassword VARCHAR(500),
+    IN inHistorySize INT
+)
+BEGIN
+
+    INSERT INTO password_history (user_id, password)
+    VALUES (inUserID, inPassword);
+
+    -- Keep only the latest passwords, derived table is needed to select from the same table
+    DELETE FROM password_history
+    WHERE user_id = inUserID
+        AND id NOT IN (
+            SELECT id
+            FROM (
+                SELECT ph.id
+                FROM password_history ph
+                WHERE ph.user_id = inUserID
+                ORDER BY ph.id DESC
+                LIMIT inHistorySize
+            ) AS recent
+        );
+
+END;
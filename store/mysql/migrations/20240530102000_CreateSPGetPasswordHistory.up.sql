@@ -0,0 +1,29 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetPasswordHistory
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetPasswordHistory;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetPasswordHistory (
+    IN inUserID CHAR(36),
+    IN inHistorySize INT
+)
+BEGIN
+
+    -- Current password is included for users whose password was set before history was kept
+    SELECT u.password
+    FROM users u
+    WHERE u.id = inUserID
+        AND u.password <> ''
+    UNION ALL
+    SELECT recent.password
+    FROM (
+        SELECT ph.password
+        FROM password_history ph
+        WHERE ph.user_id = inUserID
+        ORDER BY ph.id DESC
+        LIMIT inHistorySize
+    ) AS recent;
+
+END;
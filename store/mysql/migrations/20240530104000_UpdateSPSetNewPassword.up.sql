@@ -0,0 +1,22 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE SetNewPassword
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS SetNewPassword;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE SetNewPassword (
+    IN inUserID	CHAR(36),
+    IN inNewPassword VARCHAR(500),
+    IN inHistorySize INT
+)
+BEGIN
+
+    CALL AddPasswordHistory(inUserID, inNewPassword, inHistorySize);
+
+    -- Kept as the last statement, number of affected rows tells if user exists
+    UPDATE users
+    SET password = inNewPassword
+    WHERE id = inUserID;
+
+END;
@@ -0,0 +1,20 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UpdatePasswordHash
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UpdatePasswordHash;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UpdatePasswordHash (
+    IN inUserID	CHAR(36),
+    IN inCurrentPassword VARCHAR(500),
+    IN inNewPassword VARCHAR(500)
+)
+BEGIN
+
+    -- Password is left as is if it was changed in the meantime
+    UPDATE users
+    SET password = inNewPassword
+    WHERE id = inUserID AND password = inCurrentPassword;
+
+END;
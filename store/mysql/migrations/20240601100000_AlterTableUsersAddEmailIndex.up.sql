@@ -0,0 +1,13 @@
+-- *****************************************************************************************
+-- TABLE users
+-- *****************************************************************************************
+-- Email and phone are encrypted by the application, so columns are widened to fit the
+-- ciphertext. Email is looked up and kept unique by its blind index instead.
+-- *****************************************************************************************
+ALTER TABLE users
+    MODIFY COLUMN email VARCHAR(600) NOT NULL,
+    MODIFY COLUMN phone VARCHAR(300) NULL,
+    -- Keyed hash of the normalized email, NULL until existing rows are encrypted on startup
+    ADD COLUMN email_index CHAR(64) NULL AFTER email,
+    DROP INDEX `uq_idx_email`,
+    ADD UNIQUE INDEX `uq_idx_email_index` (`email_index`);
@@ -0,0 +1,11 @@
+-- *****************************************************************************************
+-- TABLE email_verification_tokens
+-- *****************************************************************************************
+-- Pending links were issued for plain text addresses, users can request a new link
+DELETE FROM email_verification_tokens;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+ALTER TABLE email_verification_tokens
+    MODIFY COLUMN email VARCHAR(600) NOT NULL,
+    ADD COLUMN email_index CHAR(64) NOT NULL AFTER email;
@@ -0,0 +1,25 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreateEmailVerificationToken
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreateEmailVerificationToken;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreateEmailVerificationToken (
+    IN inTokenHash CHAR(64),
+    IN inUserID CHAR(36),
+    IN inEmail VARCHAR(600),
+    IN inEmailIndex CHAR(64),
+    IN inExpiresAt DATETIME
+)
+BEGIN
+
+    -- Only the most recent verification link of the user is valid
+    DELETE FROM email_verification_tokens
+    WHERE user_id = inUserID
+        AND used_at IS NULL;
+
+    INSERT INTO email_verification_tokens (token_hash, user_id, email, email_index, expires_at)
+    VALUES (inTokenHash, inUserID, inEmail, inEmailIndex, inExpiresAt);
+
+END;
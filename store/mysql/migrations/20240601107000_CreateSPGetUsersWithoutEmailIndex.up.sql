@@ -0,0 +1,17 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetUsersWithoutEmailIndex
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetUsersWithoutEmailIndex;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetUsersWithoutEmailIndex ()
+BEGIN
+
+    SELECT u.id,
+        u.email,
+        COALESCE(u.phone, '')
+    FROM users u
+    WHERE u.email_index IS NULL;
+
+END;
@@ -0,0 +1,22 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE SetUserEncryptedFields
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS SetUserEncryptedFields;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE SetUserEncryptedFields (
+    IN inUserID CHAR(36),
+    IN inEmail VARCHAR(600),
+    IN inEmailIndex CHAR(64),
+    IN inPhone VARCHAR(300)
+)
+BEGIN
+
+    UPDATE users
+    SET email = inEmail,
+        email_index = inEmailIndex,
+        phone = inPhone
+    WHERE id = inUserID;
+
+END;
@@ -0,0 +1,6 @@
+-- *****************************************************************************************
+-- TABLE users
+-- *****************************************************************************************
+ALTER TABLE users
+    -- Deleted users can not log in and are hidden from the list of users, until they are restored
+    ADD COLUMN deleted_at DATETIME NULL AFTER last_time_logged;
@@ -0,0 +1,28 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UpdateUserDetails
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UpdateUserDetails;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UpdateUserDetails (
+    IN inUserID CHAR(36),
+    IN inName VARCHAR(150),
+    IN inEmail VARCHAR(600),
+    IN inEmailIndex CHAR(64),
+    IN inPhone VARCHAR(300),
+    IN inLanguage VARCHAR(2)
+)
+BEGIN
+
+    -- Address set by admin has to be verified again
+    UPDATE users
+    SET name = inName,
+        email_verified = IF(email_index = inEmailIndex, email_verified, 0),
+        email = inEmail,
+        email_index = inEmailIndex,
+        phone = inPhone,
+        language = inLanguage
+    WHERE id = inUserID;
+
+END;
@@ -0,0 +1,16 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteUserRoles
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteUserRoles;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteUserRoles (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    DELETE FROM user_roles
+    WHERE user_id = inUserID;
+
+END;
@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UnlockUser
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS UnlockUser;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UnlockUser (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    UPDATE users
+    SET failed_login_count = 0,
+        login_blocked_until = NULL
+    WHERE id = inUserID;
+
+END;
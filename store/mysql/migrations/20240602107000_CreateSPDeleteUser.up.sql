@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteUser
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeleteUser;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteUser (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    UPDATE users
+    SET deleted_at = NOW()
+    WHERE id = inUserID
+        AND deleted_at IS NULL;
+
+END;
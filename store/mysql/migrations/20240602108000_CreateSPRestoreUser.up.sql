@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE RestoreUser
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS RestoreUser;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE RestoreUser (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    UPDATE users
+    SET deleted_at = NULL
+    WHERE id = inUserID
+        AND deleted_at IS NOT NULL;
+
+END;
@@ -0,0 +1,19 @@
+-- *****************************************************************************************
+-- TABLE user_activation_log
+-- *****************************************************************************************
+-- This table contains every change of the user active status made by admin, along with the
+-- reason admin stated for it.
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS user_activation_log (
+    id SERIAL,
+    user_id CHAR(36) NOT NULL,
+    active BOOLEAN NOT NULL,
+    reason VARCHAR(500) NULL,
+    -- Admin who changed the status, NULL if admin was removed in the meantime
+    changed_by CHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX `idx_user_activation_log_user_id` (`user_id`),
+    CONSTRAINT fk_user_activation_log_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_user_activation_log_changed_by FOREIGN KEY (changed_by) REFERENCES users (id) ON DELETE SET NULL ON UPDATE CASCADE
+);
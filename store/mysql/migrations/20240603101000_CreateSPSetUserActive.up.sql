@@ -0,0 +1,33 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE SetUserActive
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS SetUserActive;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE SetUserActive (
+    IN inUserID CHAR(36),
+    IN inActive BOOLEAN,
+    IN inReason VARCHAR(500),
+    IN inChangedBy CHAR(36)
+)
+BEGIN
+
+    DECLARE vChanged INT DEFAULT 0;
+
+    -- Setting the status user already has is a no-op, so retried requests do not flip it back
+    UPDATE users
+    SET active = inActive
+    WHERE id = inUserID
+        AND active <> inActive;
+
+    SET vChanged = ROW_COUNT();
+
+    IF vChanged > 0 THEN
+        INSERT INTO user_activation_log (user_id, active, reason, changed_by)
+        VALUES (inUserID, inActive, NULLIF(inReason, ''), inChangedBy);
+    END IF;
+
+    SELECT vChanged > 0;
+
+END;
@@ -0,0 +1,5 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE ActivateUser
+-- =========================================================================================
+-- Replaced by SetUserActive, which sets the requested status instead of toggling it
+DROP PROCEDURE IF EXISTS ActivateUser;
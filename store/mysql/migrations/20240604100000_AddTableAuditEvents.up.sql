@@ -0,0 +1,25 @@
+-- *****************************************************************************************
+-- TABLE audit_events
+-- *****************************************************************************************
+-- This table contains security relevant events, like logins, password changes and actions
+-- admins performed on the users. Rows are only ever inserted, users are not referenced with
+-- foreign keys so events outlive the users they describe.
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS audit_events (
+    id SERIAL,
+    action VARCHAR(100) NOT NULL,
+    -- User who performed the action, NULL when not known, like for failed login
+    actor_id CHAR(36) NULL,
+    -- User the action was performed on
+    target_id CHAR(36) NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(500) NOT NULL DEFAULT '',
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    details JSON NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX `idx_audit_events_actor_id` (`actor_id`),
+    INDEX `idx_audit_events_target_id` (`target_id`),
+    INDEX `idx_audit_events_action` (`action`),
+    INDEX `idx_audit_events_created_at` (`created_at`)
+);
@@ -0,0 +1,25 @@
+-- *****************************************************************************************
+-- TRIGGERS audit_events_no_update, audit_events_no_delete
+-- =========================================================================================
+-- Audit log is append-only, existing events can not be changed or removed.
+DROP TRIGGER IF EXISTS audit_events_no_update;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE TRIGGER audit_events_no_update BEFORE UPDATE ON audit_events
+FOR EACH ROW
+BEGIN
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append-only';
+END;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+DROP TRIGGER IF EXISTS audit_events_no_delete;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE TRIGGER audit_events_no_delete BEFORE DELETE ON audit_events
+FOR EACH ROW
+BEGIN
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append-only';
+END;
@@ -0,0 +1,22 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreateAuditEvent
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreateAuditEvent;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreateAuditEvent (
+    IN inAction VARCHAR(100),
+    IN inActorID CHAR(36),
+    IN inTargetID CHAR(36),
+    IN inIPAddress VARCHAR(45),
+    IN inUserAgent VARCHAR(500),
+    IN inRequestID VARCHAR(100),
+    IN inDetails JSON
+)
+BEGIN
+
+    INSERT INTO audit_events (action, actor_id, target_id, ip_address, user_agent, request_id, details)
+    VALUES (inAction, inActorID, inTargetID, inIPAddress, LEFT(inUserAgent, 500), inRequestID, inDetails);
+
+END;
@@ -0,0 +1,21 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE SetLoginFailures
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS SetLoginFailures;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE SetLoginFailures (
+    IN inUserID CHAR(36),
+    IN inFailedLoginCount INT,
+    IN inBlockedUntil DATETIME
+)
+BEGIN
+
+    -- Failed logins are counted in memory, user only keeps the summary of the latest lockout
+    UPDATE users
+    SET failed_login_count = inFailedLoginCount,
+        login_blocked_until = inBlockedUntil
+    WHERE id = inUserID;
+
+END;
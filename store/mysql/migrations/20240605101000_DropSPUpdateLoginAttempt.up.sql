@@ -0,0 +1,5 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UpdateLoginAttempt
+-- =========================================================================================
+-- Replaced by SetLoginFailures, failed logins are counted in memory
+DROP PROCEDURE IF EXISTS UpdateLoginAttempt;
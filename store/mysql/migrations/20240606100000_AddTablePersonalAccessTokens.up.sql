@@ -0,0 +1,24 @@
+-- *****************************************************************************************
+-- TABLE personal_access_tokens
+-- *****************************************************************************************
+-- This table contains tokens users create for scripts and CI jobs, so they do not have to
+-- log in with the password. Token acts with the role user had selected when creating it.
+-- *****************************************************************************************
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id SERIAL,
+    user_id CHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    -- SHA-256 hash of the token, plain token is only shown once it is created
+    token_hash CHAR(64) NOT NULL,
+    -- Token stops working once the role is removed from the user
+    role_id BIGINT UNSIGNED NOT NULL,
+    -- Space separated permissions of the role granted to the token
+    scopes VARCHAR(1000) NOT NULL DEFAULT '',
+    expires_at DATETIME NOT NULL,
+    last_used_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE INDEX `idx_personal_access_tokens_token_hash` (`token_hash`),
+    CONSTRAINT fk_personal_access_tokens_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_personal_access_tokens_role_id FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
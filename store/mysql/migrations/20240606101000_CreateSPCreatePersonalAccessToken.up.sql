@@ -0,0 +1,34 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreatePersonalAccessToken
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreatePersonalAccessToken;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreatePersonalAccessToken (
+    IN inUserID CHAR(36),
+    IN inName VARCHAR(100),
+    IN inTokenHash CHAR(64),
+    IN inRoleID BIGINT UNSIGNED,
+    IN inScopes VARCHAR(1000),
+    IN inExpiresAt DATETIME
+)
+BEGIN
+
+    INSERT INTO personal_access_tokens (user_id, name, token_hash, role_id, scopes, expires_at)
+    VALUES (inUserID, inName, inTokenHash, inRoleID, inScopes, inExpiresAt);
+
+    SELECT t.id,
+        t.user_id,
+        t.name,
+        t.role_id,
+        r.name,
+        t.scopes,
+        t.expires_at,
+        t.last_used_at,
+        t.created_at
+    FROM personal_access_tokens t
+    INNER JOIN roles r ON r.id = t.role_id
+    WHERE t.id = LAST_INSERT_ID();
+
+END;
@@ -0,0 +1,27 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetPersonalAccessTokens
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetPersonalAccessTokens;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetPersonalAccessTokens (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    SELECT t.id,
+        t.user_id,
+        t.name,
+        t.role_id,
+        r.name,
+        t.scopes,
+        t.expires_at,
+        t.last_used_at,
+        t.created_at
+    FROM personal_access_tokens t
+    INNER JOIN roles r ON r.id = t.role_id
+    WHERE t.user_id = inUserID
+    ORDER BY t.id DESC;
+
+END;
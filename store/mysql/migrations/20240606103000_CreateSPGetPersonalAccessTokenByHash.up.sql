@@ -0,0 +1,34 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetPersonalAccessTokenByHash
+-- =========================================================================================
+-- Token is returned only if it is not expired, its user is active and not deleted, and the
+-- role of the token is still assigned to the user
+DROP PROCEDURE IF EXISTS GetPersonalAccessTokenByHash;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetPersonalAccessTokenByHash (
+    IN inTokenHash CHAR(64)
+)
+BEGIN
+
+    SELECT t.id,
+        t.user_id,
+        t.name,
+        t.role_id,
+        r.name,
+        t.scopes,
+        t.expires_at,
+        t.last_used_at,
+        t.created_at
+    FROM personal_access_tokens t
+    INNER JOIN users u ON u.id = t.user_id
+    INNER JOIN user_roles ur ON ur.user_id = t.user_id AND ur.role_id = t.role_id
+    INNER JOIN roles r ON r.id = t.role_id
+    WHERE t.token_hash = inTokenHash
+        AND t.expires_at > NOW()
+        AND u.active = 1
+        AND u.deleted_at IS NULL
+    LIMIT 1;
+
+END;
@@ -0,0 +1,18 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE TouchPersonalAccessToken
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS TouchPersonalAccessToken;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE TouchPersonalAccessToken (
+    IN inID BIGINT UNSIGNED,
+    IN inUsedAt DATETIME
+)
+BEGIN
+
+    UPDATE personal_access_tokens
+    SET last_used_at = inUsedAt
+    WHERE id = inID;
+
+END;
@@ -0,0 +1,17 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeletePersonalAccessToken
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeletePersonalAccessToken;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeletePersonalAccessToken (
+    IN inUserID CHAR(36),
+    IN inID BIGINT UNSIGNED
+)
+BEGIN
+
+    DELETE FROM personal_access_tokens
+    WHERE id = inID AND user_id = inUserID;
+
+END;
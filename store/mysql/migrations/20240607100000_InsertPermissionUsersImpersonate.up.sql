@@ -0,0 +1,10 @@
+INSERT INTO permissions (name, description)
+VALUES ('users:impersonate', 'Act as another user');
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+-- Admin role is granted all permissions
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, p.id
+FROM permissions p
+WHERE p.name = 'users:impersonate';
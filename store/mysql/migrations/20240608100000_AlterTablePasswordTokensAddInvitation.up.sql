@@ -0,0 +1,6 @@
+-- *****************************************************************************************
+-- TABLE password_tokens
+-- *****************************************************************************************
+ALTER TABLE password_tokens
+    -- Invitation tokens activate the user once password is set, reset tokens do not
+    ADD COLUMN invitation BOOLEAN NOT NULL DEFAULT 0 AFTER expires_at;
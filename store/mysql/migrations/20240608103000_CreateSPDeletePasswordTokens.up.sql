@@ -0,0 +1,16 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeletePasswordTokens
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS DeletePasswordTokens;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeletePasswordTokens (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    DELETE FROM password_tokens
+    WHERE user_id = inUserID;
+
+END;
@@ -0,0 +1,53 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE SetPassword
+-- =========================================================================================
+-- User is only activated when password is set over the invitation link, so the user deactivated
+-- by admin can not activate himself by resetting the password
+DROP PROCEDURE IF EXISTS SetPassword;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE SetPassword (
+    IN inUserID CHAR(36),
+    IN inPassword VARCHAR(500),
+    IN inToken VARCHAR(100),
+    IN inHistorySize INT
+)
+BEGIN
+
+    DECLARE vInvitation BOOLEAN DEFAULT 0;
+
+    SELECT invitation INTO vInvitation
+    FROM password_tokens
+    WHERE token = inToken
+        AND user_id = inUserID;
+
+    UPDATE users 
+    SET password = inPassword,
+        active = IF(vInvitation, 1, active),
+        email_verified = 1,
+        terms_accepted = 1
+    WHERE id = inUserID;
+
+    CALL AddPasswordHistory(inUserID, inPassword, inHistorySize);
+
+    DELETE FROM password_tokens
+    WHERE token = inToken;
+
+    SELECT u.id, 
+        u.name, 
+        u.email, 
+        u.phone, 
+        u.language, 
+        u.active, 
+        u.phone, 
+        u.password,
+        r.name,
+        r.id,
+        u.created_at
+    FROM users u
+    JOIN user_roles ur ON ur.user_id = u.id
+    JOIN roles r ON r.id = ur.role_id
+    WHERE u.id = inUserID;
+
+END;
@@ -0,0 +1,22 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE DeleteUser
+-- =========================================================================================
+-- Password links sent before are deleted, so deleted user can not set a new password and log in
+DROP PROCEDURE IF EXISTS DeleteUser;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE DeleteUser (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    UPDATE users
+    SET deleted_at = NOW()
+    WHERE id = inUserID
+        AND deleted_at IS NULL;
+
+    DELETE FROM password_tokens
+    WHERE user_id = inUserID;
+
+END;
@@ -0,0 +1,6 @@
+-- *****************************************************************************************
+-- TABLE login_tokens
+-- *****************************************************************************************
+ALTER TABLE login_tokens
+    -- Number of invalid second factor codes sent with the temporary token
+    ADD COLUMN failed_attempts INT NOT NULL DEFAULT 0 AFTER used_at;
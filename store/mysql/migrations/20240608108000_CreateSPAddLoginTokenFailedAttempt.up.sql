@@ -0,0 +1,21 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE AddLoginTokenFailedAttempt
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS AddLoginTokenFailedAttempt;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE AddLoginTokenFailedAttempt (
+    IN inTokenID BIGINT
+)
+BEGIN
+
+    UPDATE login_tokens
+    SET failed_attempts = failed_attempts + 1
+    WHERE id = inTokenID;
+
+    SELECT failed_attempts
+    FROM login_tokens
+    WHERE id = inTokenID;
+
+END;
@@ -0,0 +1,16 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetUnencryptedMFASecrets
+-- =========================================================================================
+-- Secrets encrypted by the application start with "enc:" prefix
+DROP PROCEDURE IF EXISTS GetUnencryptedMFASecrets;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetUnencryptedMFASecrets ()
+BEGIN
+
+    SELECT user_id, secret
+    FROM user_mfa
+    WHERE secret NOT LIKE 'enc:%';
+
+END;
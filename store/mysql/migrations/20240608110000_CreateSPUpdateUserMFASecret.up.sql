@@ -0,0 +1,19 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE UpdateUserMFASecret
+-- =========================================================================================
+-- Replaces stored value of the secret without resetting the enrollment, used to encrypt it
+DROP PROCEDURE IF EXISTS UpdateUserMFASecret;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE UpdateUserMFASecret (
+    IN inUserID CHAR(36),
+    IN inSecret VARCHAR(500)
+)
+BEGIN
+
+    UPDATE user_mfa
+    SET secret = inSecret
+    WHERE user_id = inUserID;
+
+END;
@@ -0,0 +1,17 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetRoleUserIDs
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS GetRoleUserIDs;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetRoleUserIDs (
+    IN inRoleID BIGINT
+)
+BEGIN
+
+    SELECT user_id
+    FROM user_roles
+    WHERE role_id = inRoleID;
+
+END;
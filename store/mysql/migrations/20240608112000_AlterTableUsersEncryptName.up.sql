@@ -0,0 +1,8 @@
+-- *****************************************************************************************
+-- TABLE users
+-- *****************************************************************************************
+-- Name is encrypted by the application as well, so the column is widened to fit the
+-- ciphertext. Existing names are encrypted on startup.
+-- *****************************************************************************************
+ALTER TABLE users
+    MODIFY COLUMN name VARCHAR(1000) NOT NULL;
@@ -0,0 +1,43 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE CreateUser
+-- =========================================================================================
+DROP PROCEDURE IF EXISTS CreateUser;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE CreateUser (
+    IN inName VARCHAR(1000),
+    IN inEmail VARCHAR(600),
+    IN inEmailIndex CHAR(64),
+    IN inPassword VARCHAR(500),
+    IN inActive BOOLEAN,
+    IN inRoleName VARCHAR(150)
+)
+BEGIN
+
+    SET @userID = UUID();
+
+    INSERT INTO users (id, name, email, email_index, phone, password, active)
+    VALUES (@userID, inName, inEmail, inEmailIndex, '', inPassword, inActive);
+
+    -- Assign requested role to the newly created user
+    INSERT INTO user_roles (user_id, role_id)
+    SELECT @userID, r.id
+    FROM roles r
+    WHERE r.name = inRoleName;
+
+    SELECT u.id,
+        u.name,
+        u.email,
+        u.phone,
+        u.language,
+        u.active,
+        r.name,
+        r.id,
+        u.created_at
+    FROM users u
+    JOIN user_roles ur ON ur.user_id = u.id
+    JOIN roles r ON r.id = ur.role_id
+    WHERE u.id = @userID;
+
+END;
@@ -0,0 +1,30 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetUsersToEncrypt
+-- =========================================================================================
+-- Replaces GetUsersWithoutEmailIndex. Returns users whose personal data is not encrypted
+-- with the current key, which is the case for data stored before encryption was enabled
+-- or before the key was rotated. Blind index of their email is computed again as well.
+DROP PROCEDURE IF EXISTS GetUsersWithoutEmailIndex;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+DROP PROCEDURE IF EXISTS GetUsersToEncrypt;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetUsersToEncrypt (
+    IN inKeyPrefix VARCHAR(50)
+)
+BEGIN
+
+    SELECT u.id,
+        u.name,
+        u.email,
+        COALESCE(u.phone, '')
+    FROM users u
+    WHERE u.email_index IS NULL
+        OR u.email NOT LIKE CONCAT(inKeyPrefix, '%')
+        OR (u.name <> '' AND u.name NOT LIKE CONCAT(inKeyPrefix, '%'))
+        OR (COALESCE(u.phone, '') <> '' AND u.phone NOT LIKE CONCAT(inKeyPrefix, '%'));
+
+END;
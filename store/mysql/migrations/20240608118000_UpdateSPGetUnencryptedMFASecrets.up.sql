@@ -0,0 +1,19 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE GetUnencryptedMFASecrets
+-- =========================================================================================
+-- Returns secrets which are not encrypted with the current key, either stored in plain
+-- text or encrypted before the key was rotated
+DROP PROCEDURE IF EXISTS GetUnencryptedMFASecrets;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE GetUnencryptedMFASecrets (
+    IN inKeyPrefix VARCHAR(50)
+)
+BEGIN
+
+    SELECT user_id, secret
+    FROM user_mfa
+    WHERE secret NOT LIKE CONCAT(inKeyPrefix, '%');
+
+END;
@@ -0,0 +1,20 @@
+-- *****************************************************************************************
+-- STORED PROCEDURE HasUserActivationLog
+-- =========================================================================================
+-- Checks if admin ever changed the active status of the user
+DROP PROCEDURE IF EXISTS HasUserActivationLog;
+
+--MYSQL_CUSTOM_STATEMENT_DELIMITER
+
+CREATE PROCEDURE HasUserActivationLog (
+    IN inUserID CHAR(36)
+)
+BEGIN
+
+    SELECT EXISTS (
+        SELECT 1
+        FROM user_activation_log
+        WHERE user_id = inUserID
+    );
+
+END;
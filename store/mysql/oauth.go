@@ -0,0 +1,185 @@
+package mysqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/go-sql-driver/mysql"
+	"github.com/twinj/uuid"
+)
+
+// CreateOAuthClient registers a new OAuth client.
+func (r *Repository) CreateOAuthClient(client *store.OAuthClient, createdBy uuid.UUID) (*store.OAuthClient, error) {
+	query, err := r.db.Prepare("CALL CreateOAuthClient(?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreateOAuthClient(%v, %v).", client.ID, client.Name)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	secretHash := sql.NullString{String: client.SecretHash, Valid: client.SecretHash != ""}
+
+	createdClient, err := scanOAuthClient(query.QueryRow(client.ID,
+		client.Name,
+		secretHash,
+		strings.Join(client.RedirectURIs, " "),
+		strings.Join(client.GrantTypes, " "),
+		strings.Join(client.Scopes, " "),
+		createdBy))
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == ErrDuplicateEntry {
+			return nil, errors.New(store.OAuthClientDuplicated)
+		}
+
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL CreateOAuthClient(%v, %v).", client.ID, client.Name)
+		return nil, err
+	}
+
+	return createdClient, nil
+}
+
+// GetOAuthClientByID will retrieve OAuth client filtered by client ID.
+func (r *Repository) GetOAuthClientByID(id string) (*store.OAuthClient, error) {
+	query, err := r.db.Prepare("CALL GetOAuthClientByID(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetOAuthClientByID(%v).", id)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	client, err := scanOAuthClient(query.QueryRow(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.OAuthClientNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL GetOAuthClientByID(%v).", id)
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// DeleteOAuthClient deletes OAuth client and its authorization codes.
+func (r *Repository) DeleteOAuthClient(id string) error {
+	query, err := r.db.Prepare("CALL DeleteOAuthClient(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteOAuthClient(%v)", id)
+		return err
+	}
+
+	defer query.Close()
+
+	res, err := query.Exec(id)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteOAuthClient(%v)", id)
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed after statement is executed: CALL DeleteOAuthClient(%v)", id)
+		return err
+	}
+
+	if ra == 0 {
+		return errors.New(store.OAuthClientNotFound)
+	}
+
+	return nil
+}
+
+// CreateAuthorizationCode stores authorization code issued to the client.
+func (r *Repository) CreateAuthorizationCode(code *store.AuthorizationCode) error {
+	query, err := r.db.Prepare("CALL CreateAuthorizationCode(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreateAuthorizationCode(%v, %v).", code.ClientID, code.UserID)
+		return err
+	}
+
+	defer query.Close()
+
+	_, err = query.Exec(code.CodeHash,
+		code.ClientID,
+		code.UserID,
+		code.RedirectURI,
+		code.Scope,
+		code.CodeChallenge,
+		code.CodeChallengeMethod,
+		code.Nonce,
+		code.ExpiresAt)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL CreateAuthorizationCode(%v, %v).", code.ClientID, code.UserID)
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeAuthorizationCode marks authorization code as used and returns it. Used flag is set
+// if code was already exchanged before.
+func (r *Repository) ConsumeAuthorizationCode(codeHash string) (*store.AuthorizationCode, error) {
+	query, err := r.db.Prepare("CALL ConsumeAuthorizationCode(?)")
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to prepare statement: CALL ConsumeAuthorizationCode.")
+		return nil, err
+	}
+
+	defer query.Close()
+
+	code := new(store.AuthorizationCode)
+
+	err = query.QueryRow(codeHash).Scan(&code.CodeHash,
+		&code.ClientID,
+		&code.UserID,
+		&code.RedirectURI,
+		&code.Scope,
+		&code.CodeChallenge,
+		&code.CodeChallengeMethod,
+		&code.Nonce,
+		&code.ExpiresAt,
+		&code.Used,
+		&code.Expired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.AuthorizationCodeNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("There was an error executing query: CALL ConsumeAuthorizationCode.")
+		return nil, err
+	}
+
+	return code, nil
+}
+
+func scanOAuthClient(row rowScanner) (*store.OAuthClient, error) {
+	client := new(store.OAuthClient)
+
+	var secretHash sql.NullString
+
+	var redirectURIs, grantTypes, scopes string
+
+	err := row.Scan(&client.ID,
+		&client.Name,
+		&secretHash,
+		&redirectURIs,
+		&grantTypes,
+		&scopes,
+		&client.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	client.SecretHash = secretHash.String
+	client.RedirectURIs = strings.Fields(redirectURIs)
+	client.GrantTypes = strings.Fields(grantTypes)
+	client.Scopes = strings.Fields(scopes)
+
+	return client, nil
+}
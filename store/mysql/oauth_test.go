@@ -0,0 +1,88 @@
+package mysqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *RepositorySuite) TestGetOAuthClientByID() {
+	currentTime := time.Now()
+
+	columns := []string{"ID", "Name", "SecretHash", "RedirectURIs", "GrantTypes", "Scopes", "CreatedAt"}
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		queryParam  string
+		expected    *store.OAuthClient
+		expectErr   bool
+		errorMsg    interface{}
+	}{
+		{
+			name: "Success Case - Confidential client",
+			queryResult: sqlmock.NewRows(columns).
+				AddRow("client1", "Reporting", "$2a$04$hash", "", "client_credentials", "tasks:read", currentTime),
+			queryParam: "client1",
+			expected: &store.OAuthClient{
+				ID:           "client1",
+				Name:         "Reporting",
+				SecretHash:   "$2a$04$hash",
+				RedirectURIs: []string{},
+				GrantTypes:   []string{"client_credentials"},
+				Scopes:       []string{"tasks:read"},
+				CreatedAt:    currentTime,
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name: "Success Case - Public client",
+			queryResult: sqlmock.NewRows(columns).
+				AddRow("client2", "SPA", nil, "https://app.example.com/callback http://localhost:3000/callback",
+					"authorization_code", "openid profile email", currentTime),
+			queryParam: "client2",
+			expected: &store.OAuthClient{
+				ID:           "client2",
+				Name:         "SPA",
+				RedirectURIs: []string{"https://app.example.com/callback", "http://localhost:3000/callback"},
+				GrantTypes:   []string{"authorization_code"},
+				Scopes:       []string{"openid", "profile", "email"},
+				CreatedAt:    currentTime,
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name:        "Error Case - Client not found",
+			queryResult: sqlmock.NewRows([]string{}),
+			queryParam:  "client3",
+			expectErr:   true,
+			errorMsg:    store.OAuthClientNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetOAuthClientByID\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(tt.queryParam).
+				WillReturnRows(tt.queryResult)
+
+			client, err := s.repo.GetOAuthClientByID(tt.queryParam)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				require.Equal(t, tt.errorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, client)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
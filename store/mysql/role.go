@@ -0,0 +1,339 @@
+package mysqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/go-sql-driver/mysql"
+	"github.com/twinj/uuid"
+)
+
+// GetAllRoles will retrieve all roles along with their permissions.
+func (r *Repository) GetAllRoles() ([]*store.Role, error) {
+	query, err := r.db.Prepare("CALL GetAllRoles()")
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to prepare statement: CALL GetAllRoles().")
+		return nil, err
+	}
+
+	defer query.Close()
+
+	rows, err := query.Query()
+	if err != nil {
+		logger.Error().Err(err).Msg("There was an error executing query: CALL GetAllRoles().")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	roles := make([]*store.Role, 0)
+
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// GetRoleByID will retrieve the role filtered by ID along with its permissions.
+func (r *Repository) GetRoleByID(id int64) (*store.Role, error) {
+	query, err := r.db.Prepare("CALL GetRoleByID(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetRoleByID(%v).", id)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	role, err := scanRole(query.QueryRow(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.RoleNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL GetRoleByID(%v).", id)
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// CreateRole will create a new role and grant it the permissions.
+func (r *Repository) CreateRole(role *store.Role) (*store.Role, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to begin transaction for role %v.", role.Name)
+		return nil, err
+	}
+
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is no-op
+
+	query, err := tx.Prepare("CALL CreateRole(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreateRole(%v).", role.Name)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	var roleID int64
+
+	err = query.QueryRow(role.Name).Scan(&roleID)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == ErrDuplicateEntry {
+			return nil, errors.New(store.RoleDuplicated)
+		}
+
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL CreateRole(%v).", role.Name)
+		return nil, err
+	}
+
+	if err := replaceRolePermissions(tx, roleID, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetRoleByID(roleID)
+}
+
+// UpdateRole updates role name and replaces the permissions granted to the role.
+func (r *Repository) UpdateRole(role *store.Role) (*store.Role, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to begin transaction for role %v.", role.ID)
+		return nil, err
+	}
+
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is no-op
+
+	query, err := tx.Prepare("CALL UpdateRole(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UpdateRole(%v, %v).", role.ID, role.Name)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(role.ID, role.Name); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == ErrDuplicateEntry {
+			return nil, errors.New(store.RoleDuplicated)
+		}
+
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL UpdateRole(%v, %v).", role.ID, role.Name)
+		return nil, err
+	}
+
+	if err := replaceRolePermissions(tx, role.ID, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetRoleByID(role.ID)
+}
+
+// DeleteRole deletes role by id. Role which is assigned to users is not deleted.
+func (r *Repository) DeleteRole(id int64) error {
+	query, err := r.db.Prepare("CALL DeleteRole(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteRole(%v)", id)
+		return err
+	}
+
+	defer query.Close()
+
+	res, err := query.Exec(id)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteRole(%v)", id)
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed after statement is executed: CALL DeleteRole(%v)", id)
+		return err
+	}
+
+	if ra == 0 {
+		return errors.New(store.RoleInUse)
+	}
+
+	return nil
+}
+
+// GetAllPermissions will retrieve all permissions which can be granted to the roles.
+func (r *Repository) GetAllPermissions() ([]*store.Permission, error) {
+	query, err := r.db.Prepare("CALL GetAllPermissions()")
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to prepare statement: CALL GetAllPermissions().")
+		return nil, err
+	}
+
+	defer query.Close()
+
+	rows, err := query.Query()
+	if err != nil {
+		logger.Error().Err(err).Msg("There was an error executing query: CALL GetAllPermissions().")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	permissions := make([]*store.Permission, 0)
+
+	for rows.Next() {
+		permission := new(store.Permission)
+
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description); err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// AddUserRole assigns the role to the user. Nothing is changed if user already has the role.
+func (r *Repository) AddUserRole(userID uuid.UUID, roleID int64) error {
+	query, err := r.db.Prepare("CALL AddUserRole(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AddUserRole(%v, %v)", userID, roleID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID, roleID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL AddUserRole(%v, %v)", userID, roleID)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveUserRole removes the role from the user.
+func (r *Repository) RemoveUserRole(userID uuid.UUID, roleID int64) error {
+	query, err := r.db.Prepare("CALL RemoveUserRole(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL RemoveUserRole(%v, %v)", userID, roleID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID, roleID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL RemoveUserRole(%v, %v)", userID, roleID)
+		return err
+	}
+
+	return nil
+}
+
+// GetRoleUserIDs retrieves IDs of users the role is assigned to.
+func (r *Repository) GetRoleUserIDs(roleID int64) ([]uuid.UUID, error) {
+	query, err := r.db.Prepare("CALL GetRoleUserIDs(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetRoleUserIDs(%v)", roleID)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	rows, err := query.Query(roleID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL GetRoleUserIDs(%v)", roleID)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	userIDs := make([]uuid.UUID, 0)
+
+	for rows.Next() {
+		var userID uuid.UUID
+
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// replaceRolePermissions deletes permissions granted to the role and grants the new ones.
+func replaceRolePermissions(tx *sql.Tx, roleID int64, permissions []string) error {
+	deleteQuery, err := tx.Prepare("CALL DeleteRolePermissions(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteRolePermissions(%v).", roleID)
+		return err
+	}
+
+	defer deleteQuery.Close()
+
+	if _, err = deleteQuery.Exec(roleID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteRolePermissions(%v).", roleID)
+		return err
+	}
+
+	addQuery, err := tx.Prepare("CALL AddRolePermission(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AddRolePermission(%v).", roleID)
+		return err
+	}
+
+	defer addQuery.Close()
+
+	for _, permission := range permissions {
+		if _, err = addQuery.Exec(roleID, permission); err != nil {
+			logger.Error().Err(err).Msgf("failed to execute statement: CALL AddRolePermission(%v, %v).", roleID, permission)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanRole(row rowScanner) (*store.Role, error) {
+	role := new(store.Role)
+
+	var permissions string
+
+	if err := row.Scan(&role.ID, &role.Name, &permissions); err != nil {
+		return nil, err
+	}
+
+	role.Value = strings.ToUpper(role.Name)
+	role.Permissions = strings.Fields(permissions)
+
+	return role, nil
+}
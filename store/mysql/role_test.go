@@ -0,0 +1,149 @@
+package mysqlstore
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/stretchr/testify/require"
+	"github.com/twinj/uuid"
+)
+
+func (s *RepositorySuite) TestGetRoleByID() {
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		expected    *store.Role
+		errorMsg    string
+	}{
+		{
+			name: "Success Case",
+			queryResult: sqlmock.NewRows([]string{
+				"id", "name", "permissions",
+			}).AddRow(
+				1, "Admin", "roles:read users:read",
+			),
+			expected: &store.Role{
+				ID:          1,
+				Name:        "Admin",
+				Value:       "ADMIN",
+				Permissions: []string{"roles:read", "users:read"},
+			},
+		},
+		{
+			name: "Success Case - Role without permissions",
+			queryResult: sqlmock.NewRows([]string{
+				"id", "name", "permissions",
+			}).AddRow(
+				1, "User", "",
+			),
+			expected: &store.Role{
+				ID:          1,
+				Name:        "User",
+				Value:       "USER",
+				Permissions: []string{},
+			},
+		},
+		{
+			name: "Error Case - Role not found",
+			queryResult: sqlmock.NewRows([]string{
+				"id", "name", "permissions",
+			}),
+			errorMsg: store.RoleNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetRoleByID\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(int64(1)).
+				WillReturnRows(tt.queryResult)
+
+			role, err := s.repo.GetRoleByID(1)
+
+			if tt.errorMsg != "" {
+				require.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, role)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestDeleteRole() {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		errorMsg     string
+	}{
+		{
+			name:         "Success Case",
+			rowsAffected: 1,
+		},
+		{
+			name:         "Error Case - Role is assigned to users",
+			rowsAffected: 0,
+			errorMsg:     store.RoleInUse,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL DeleteRole\\(\\?\\)$").
+				ExpectExec().
+				WithArgs(int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			err := s.repo.DeleteRole(3)
+
+			if tt.errorMsg != "" {
+				require.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestGetRoleUserIDs() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		expected    []uuid.UUID
+	}{
+		{
+			name:        "Success Case",
+			queryResult: sqlmock.NewRows([]string{"user_id"}).AddRow(userID.String()),
+			expected:    []uuid.UUID{userID},
+		},
+		{
+			name:        "Success Case - Role not assigned",
+			queryResult: sqlmock.NewRows([]string{"user_id"}),
+			expected:    []uuid.UUID{},
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetRoleUserIDs\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(int64(3)).
+				WillReturnRows(tt.queryResult)
+
+			userIDs, err := s.repo.GetRoleUserIDs(3)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, userIDs)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package mysqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/twinj/uuid"
+)
+
+// CreateTask will create a new task.
+func (r *Repository) CreateTask(task *store.Task) (*store.Task, error) {
+	query, err := r.db.Prepare("CALL CreateTask(?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreateTask(%v, %v, %v, %v).",
+			task.Title, task.CreatedBy, task.AssignedTo, task.DueDate)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	createdTask, err := scanTask(query.QueryRow(task.Title, task.Description, task.CreatedBy, task.AssignedTo, task.DueDate))
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL CreateTask(%v, %v, %v, %v).",
+			task.Title, task.CreatedBy, task.AssignedTo, task.DueDate)
+		return nil, err
+	}
+
+	return createdTask, nil
+}
+
+// GetTaskByID will retrieve the task filtered by ID.
+func (r *Repository) GetTaskByID(id int64) (*store.Task, error) {
+	query, err := r.db.Prepare("CALL GetTaskByID(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetTaskByID(%v).", id)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	task, err := scanTask(query.QueryRow(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.TaskNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL GetTaskByID(%v).", id)
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// UpdateTask updates task details.
+func (r *Repository) UpdateTask(task *store.Task) (*store.Task, error) {
+	query, err := r.db.Prepare("CALL UpdateTask(?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UpdateTask(%v, %v, %v).",
+			task.ID, task.Title, task.DueDate)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	updatedTask, err := scanTask(query.QueryRow(task.ID, task.Title, task.Description, task.DueDate))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.TaskNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL UpdateTask(%v, %v, %v).",
+			task.ID, task.Title, task.DueDate)
+		return nil, err
+	}
+
+	return updatedTask, nil
+}
+
+// AssignTask assigns task to the user. Task is unassigned if user is not provided.
+func (r *Repository) AssignTask(id int64, assignedTo *uuid.UUID) (*store.Task, error) {
+	query, err := r.db.Prepare("CALL AssignTask(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AssignTask(%v, %v).", id, assignedTo)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	task, err := scanTask(query.QueryRow(id, assignedTo))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.TaskNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL AssignTask(%v, %v).", id, assignedTo)
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// UpdateTaskStatus updates status of the task.
+func (r *Repository) UpdateTaskStatus(id int64, status string) (*store.Task, error) {
+	query, err := r.db.Prepare("CALL UpdateTaskStatus(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UpdateTaskStatus(%v, %v).", id, status)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	task, err := scanTask(query.QueryRow(id, status))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.TaskNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL UpdateTaskStatus(%v, %v).", id, status)
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// DeleteTask deletes task by id.
+func (r *Repository) DeleteTask(id int64) error {
+	query, err := r.db.Prepare("CALL DeleteTask(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteTask(%v)", id)
+		return err
+	}
+
+	defer query.Close()
+
+	res, err := query.Exec(id)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteTask(%v)", id)
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed after statement is executed: CALL DeleteTask(%v)", id)
+		return err
+	}
+
+	if ra == 0 {
+		return errors.New(store.TaskNotFound)
+	}
+
+	return nil
+}
+
+// GetTasks will retrieve list of tasks based on filter and cursor pagination parameters.
+func (r *Repository) GetTasks(filter *store.TaskFilter) ([]*store.Task, error) {
+	limit := r.PaginatorCursor().GetLimit()
+	// Get order key and direction values, with defaults specified
+	key, direction := r.PaginatorCursor().Order("t.id", "desc", store.TasksKeyToColumnMap())
+
+	var err error
+	// Build WHERE clause. Each segment of the clause is AND-ed together.
+	// Values are appended to args so we can avoid SQL injection.
+	where, args := []string{"1 = 1"}, []interface{}{}
+
+	// Build paginator where clause based on page direction
+	where, args, err = r.PaginatorCursor().BuildWhereClause(where, args, key, direction)
+	if err != nil {
+		return nil, fmt.Errorf("build where clause: %w", err)
+	}
+
+	if v := filter.Status; v != nil {
+		where, args = append(where, "t.status = ?"), append(args, *v)
+	}
+	if v := filter.AssignedTo; v != nil {
+		where, args = append(where, "t.assigned_to = ?"), append(args, *v)
+	}
+	if v := filter.CreatedBy; v != nil {
+		where, args = append(where, "t.created_by = ?"), append(args, *v)
+	}
+	if v := filter.VisibleTo; v != nil {
+		where, args = append(where, "(t.created_by = ? OR t.assigned_to = ?)"), append(args, *v, *v)
+	}
+	if v := filter.Search; v != nil {
+		pattern := "%" + *v + "%"
+		where, args = append(where, "t.title LIKE ?"), append(args, pattern)
+	}
+
+	// Determine default sorting.
+	sortBy := fmt.Sprintf("%s %s",
+		key, r.PaginatorCursor().GetOrderByCursorDirection(direction))
+
+	query := `SELECT t.id,
+		t.title,
+		t.description,
+		t.status,
+		t.created_by,
+		t.assigned_to,
+		t.due_date,
+		t.created_at,
+		t.updated_at
+	FROM
+		tasks t
+	WHERE `
+
+	query += strings.Join(where, " AND ")
+	query += ` ORDER BY ` + sortBy + `
+	` + r.PaginatorCursor().FormatLimit(limit+1)
+
+	// For previous page, the main query will be subquery,
+	// because we need to reverse the result set order again to
+	// get the correct order
+	if r.PaginatorCursor().GetCursorDirection() == "previous" {
+		sortByReverse := fmt.Sprintf("%s %s", key, direction)
+
+		query = `SELECT t.id,
+					t.title,
+					t.description,
+					t.status,
+					t.created_by,
+					t.assigned_to,
+					t.due_date,
+					t.created_at,
+					t.updated_at
+				FROM(
+					` + query + `
+				) AS t
+				ORDER BY ` + sortByReverse
+	}
+
+	tasks := make([]*store.Task, 0)
+
+	// Prepare the query
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	// Execute the query
+	rows, err := stmt.Query(args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tasks, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// get result length before slicing
+	resultLength := len(tasks)
+
+	if len(tasks) > 0 {
+		if r.PaginatorCursor().GetCursorDirection() == "next" {
+			if len(tasks) > limit {
+				tasks = tasks[:limit]
+			}
+		} else {
+			if len(tasks) > limit {
+				tasks = tasks[1:]
+			}
+		}
+
+		// Paginate
+		r.PaginatorCursor().Paginate(tasks[0].ID, tasks[len(tasks)-1].ID, resultLength)
+	}
+
+	return tasks, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (*store.Task, error) {
+	task := new(store.Task)
+
+	err := row.Scan(&task.ID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.CreatedBy,
+		&task.AssignedTo,
+		&task.DueDate,
+		&task.CreatedAt,
+		&task.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
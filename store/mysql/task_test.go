@@ -0,0 +1,101 @@
+package mysqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/stretchr/testify/require"
+	"github.com/twinj/uuid"
+)
+
+func (s *RepositorySuite) TestGetTaskByID() {
+	currentTime := time.Now()
+	creatorID := uuid.NewV4()
+	assigneeID := uuid.NewV4()
+
+	columns := []string{
+		"ID", "Title", "Description", "Status", "CreatedBy",
+		"AssignedTo", "DueDate", "CreatedAt", "UpdatedAt",
+	}
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		queryParam  int64
+		expected    *store.Task
+		expectErr   bool
+		errorMsg    interface{}
+	}{
+		{
+			name: "Success Case",
+			queryResult: sqlmock.NewRows(columns).
+				AddRow(
+					1, "test task", "description", "TODO", creatorID.String(),
+					assigneeID.String(), currentTime, currentTime, currentTime,
+				),
+			queryParam: 1,
+			expected: &store.Task{
+				ID:          1,
+				Title:       "test task",
+				Description: "description",
+				Status:      "TODO",
+				CreatedBy:   creatorID,
+				AssignedTo:  &assigneeID,
+				DueDate:     &currentTime,
+				CreatedAt:   currentTime,
+				UpdatedAt:   currentTime,
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name: "Success Case - Task is not assigned",
+			queryResult: sqlmock.NewRows(columns).
+				AddRow(
+					2, "test task", "", "IN_PROGRESS", creatorID.String(),
+					nil, nil, currentTime, currentTime,
+				),
+			queryParam: 2,
+			expected: &store.Task{
+				ID:        2,
+				Title:     "test task",
+				Status:    "IN_PROGRESS",
+				CreatedBy: creatorID,
+				CreatedAt: currentTime,
+				UpdatedAt: currentTime,
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name:        "Error Case - Task not found",
+			queryResult: sqlmock.NewRows([]string{}),
+			queryParam:  3,
+			expectErr:   true,
+			errorMsg:    store.TaskNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetTaskByID\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(tt.queryParam).
+				WillReturnRows(tt.queryResult)
+
+			task, err := s.repo.GetTaskByID(tt.queryParam)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				require.Equal(t, tt.errorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, task)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package mysqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/go-sql-driver/mysql"
+	"github.com/twinj/uuid"
+)
+
+// GetCurrentTerms will retrieve the most recently published version of terms of service.
+func (r *Repository) GetCurrentTerms() (*store.Terms, error) {
+	query, err := r.db.Prepare("CALL GetCurrentTerms()")
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to prepare statement: CALL GetCurrentTerms().")
+		return nil, err
+	}
+
+	defer query.Close()
+
+	terms, err := scanTerms(query.QueryRow())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.TermsNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("There was an error executing query: CALL GetCurrentTerms().")
+		return nil, err
+	}
+
+	return terms, nil
+}
+
+// CreateTerms creates a new version of terms of service. Version is published immediately
+// if publish date is not set.
+func (r *Repository) CreateTerms(terms *store.Terms, createdBy uuid.UUID) (*store.Terms, error) {
+	query, err := r.db.Prepare("CALL CreateTerms(?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreateTerms(%v).", terms.Version)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	publishedAt := sql.NullTime{Time: terms.PublishedAt, Valid: !terms.PublishedAt.IsZero()}
+
+	createdTerms, err := scanTerms(query.QueryRow(terms.Version, terms.Content, publishedAt, createdBy))
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == ErrDuplicateEntry {
+			return nil, errors.New(store.TermsDuplicated)
+		}
+
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL CreateTerms(%v).", terms.Version)
+		return nil, err
+	}
+
+	return createdTerms, nil
+}
+
+// AcceptTerms records that the user accepted the terms version. Accepting the same version again
+// keeps the time of the first acceptance.
+func (r *Repository) AcceptTerms(userID uuid.UUID, termsID int64, ipAddress string) error {
+	query, err := r.db.Prepare("CALL AcceptTerms(?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AcceptTerms(%v, %v)", userID, termsID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID, termsID, ipAddress); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL AcceptTerms(%v, %v)", userID, termsID)
+		return err
+	}
+
+	return nil
+}
+
+// GetUserTermsAcceptance will retrieve the current version of terms of service along with the time
+// user accepted it.
+func (r *Repository) GetUserTermsAcceptance(userID uuid.UUID) (*store.TermsAcceptance, error) {
+	query, err := r.db.Prepare("CALL GetUserTermsAcceptance(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetUserTermsAcceptance(%v).", userID)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	acceptance := new(store.TermsAcceptance)
+
+	var acceptedAt sql.NullTime
+
+	err = query.QueryRow(userID).Scan(&acceptance.TermsID, &acceptance.Version, &acceptedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.TermsNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL GetUserTermsAcceptance(%v).", userID)
+		return nil, err
+	}
+
+	if acceptedAt.Valid {
+		acceptance.AcceptedAt = &acceptedAt.Time
+	}
+
+	return acceptance, nil
+}
+
+func scanTerms(row rowScanner) (*store.Terms, error) {
+	terms := new(store.Terms)
+
+	err := row.Scan(&terms.ID,
+		&terms.Version,
+		&terms.Content,
+		&terms.PublishedAt,
+		&terms.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return terms, nil
+}
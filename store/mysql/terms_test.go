@@ -0,0 +1,77 @@
+package mysqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/stretchr/testify/require"
+	"github.com/twinj/uuid"
+)
+
+func (s *RepositorySuite) TestGetUserTermsAcceptance() {
+	userID := uuid.NewV4()
+	acceptedAt := time.Now().UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		expected    *store.TermsAcceptance
+		errorMsg    string
+	}{
+		{
+			name: "Success Case - Current version accepted",
+			queryResult: sqlmock.NewRows([]string{
+				"id", "version", "accepted_at",
+			}).AddRow(
+				2, "2.0", acceptedAt,
+			),
+			expected: &store.TermsAcceptance{
+				TermsID:    2,
+				Version:    "2.0",
+				AcceptedAt: &acceptedAt,
+			},
+		},
+		{
+			name: "Success Case - Current version not accepted",
+			queryResult: sqlmock.NewRows([]string{
+				"id", "version", "accepted_at",
+			}).AddRow(
+				2, "2.0", nil,
+			),
+			expected: &store.TermsAcceptance{
+				TermsID: 2,
+				Version: "2.0",
+			},
+		},
+		{
+			name: "Error Case - No terms published",
+			queryResult: sqlmock.NewRows([]string{
+				"id", "version", "accepted_at",
+			}),
+			errorMsg: store.TermsNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetUserTermsAcceptance\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(userID).
+				WillReturnRows(tt.queryResult)
+
+			acceptance, err := s.repo.GetUserTermsAcceptance(userID)
+
+			if tt.errorMsg != "" {
+				require.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, acceptance)
+				require.Equal(t, tt.expected.AcceptedAt != nil, acceptance.Accepted())
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/adinovcina/golang-setup/store"
 	"github.com/adinovcina/golang-setup/tools/logger"
@@ -22,7 +24,8 @@ func (r *Repository) GetPasswordTokenByToken(token string) (*store.PasswordToken
 
 	passwordToken := new(store.PasswordToken)
 
-	err = query.QueryRow(token).Scan(&passwordToken.UserID, &passwordToken.Token, &passwordToken.ExpiresAt)
+	err = query.QueryRow(token).Scan(&passwordToken.UserID, &passwordToken.Token, &passwordToken.ExpiresAt,
+		&passwordToken.Invitation)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, errors.New("TOKEN_NOT_FOUND")
 	}
@@ -36,8 +39,10 @@ func (r *Repository) GetPasswordTokenByToken(token string) (*store.PasswordToken
 }
 
 // AddPasswordResetToken add new reset password for the user.
-func (r *Repository) AddPasswordResetToken(userID uuid.UUID, token string, expiresAt int64) (*store.PasswordToken, error) {
-	query, err := r.db.Prepare("CALL AddPasswordToken(?, ?, ?)")
+func (r *Repository) AddPasswordResetToken(userID uuid.UUID, token string, expiresAt int64,
+	invitation bool,
+) (*store.PasswordToken, error) {
+	query, err := r.db.Prepare("CALL AddPasswordToken(?, ?, ?, ?)")
 	if err != nil {
 		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AddPasswordToken(%v, %v, %v)",
 			userID, token, expiresAt)
@@ -48,10 +53,11 @@ func (r *Repository) AddPasswordResetToken(userID uuid.UUID, token string, expir
 
 	passwordToken := new(store.PasswordToken)
 
-	err = query.QueryRow(userID, token, expiresAt).
+	err = query.QueryRow(userID, token, expiresAt, invitation).
 		Scan(&passwordToken.UserID,
 			&passwordToken.Token,
-			&passwordToken.ExpiresAt)
+			&passwordToken.ExpiresAt,
+			&passwordToken.Invitation)
 	if err != nil {
 		logger.Error().Err(err).Msgf("There was an error executing query: CALL AddPasswordToken(%v, %v, %v)",
 			userID, token, expiresAt)
@@ -61,6 +67,24 @@ func (r *Repository) AddPasswordResetToken(userID uuid.UUID, token string, expir
 	return passwordToken, nil
 }
 
+// DeletePasswordTokens deletes all password tokens of the user, so links sent before can not be used.
+func (r *Repository) DeletePasswordTokens(userID uuid.UUID) error {
+	query, err := r.db.Prepare("CALL DeletePasswordTokens(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeletePasswordTokens(%v)", userID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID); err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL DeletePasswordTokens(%v)", userID)
+		return err
+	}
+
+	return nil
+}
+
 // GetTokenByTokenAndType will retrieve the token by token value and type.
 func (r *Repository) GetTokenByTokenAndType(token, tokenType string) (*store.LoginToken, error) {
 	query, err := r.db.Prepare("CALL GetTokenByTokenAndType(?,?)")
@@ -79,7 +103,11 @@ func (r *Repository) GetTokenByTokenAndType(token, tokenType string) (*store.Log
 			&model.ID,
 			&model.UserID,
 			&model.Token,
-			&model.TokenType)
+			&model.TokenType,
+			&model.SessionID,
+			&model.FamilyID,
+			&model.RoleID,
+			&model.Used)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, errors.New(store.TokenNotFound)
 	}
@@ -124,3 +152,264 @@ func (r *Repository) DeleteTokenByID(id int64) error {
 
 	return nil
 }
+
+// UseLoginToken marks the token as used. False is returned if the token was already used,
+// which means it is replayed.
+func (r *Repository) UseLoginToken(id int64) (bool, error) {
+	query, err := r.db.Prepare("CALL UseLoginToken(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UseLoginToken(%v)", id)
+		return false, err
+	}
+
+	defer query.Close()
+
+	res, err := query.Exec(id)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL UseLoginToken(%v)", id)
+		return false, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed after statement is executed: CALL UseLoginToken(%v)", id)
+		return false, err
+	}
+
+	return ra > 0, nil
+}
+
+// AddLoginTokenFailedAttempt counts invalid second factor code sent with the temporary token. Returns
+// number of failed attempts made with the token so far.
+func (r *Repository) AddLoginTokenFailedAttempt(id int64) (int64, error) {
+	query, err := r.db.Prepare("CALL AddLoginTokenFailedAttempt(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AddLoginTokenFailedAttempt(%v)", id)
+		return 0, err
+	}
+
+	defer query.Close()
+
+	var attempts int64
+
+	err = query.QueryRow(id).Scan(&attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, errors.New(store.TokenNotFound)
+	} else if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL AddLoginTokenFailedAttempt(%v)", id)
+		return 0, err
+	}
+
+	return attempts, nil
+}
+
+// DeleteTokenFamily deletes all refresh tokens rotated from the same login.
+func (r *Repository) DeleteTokenFamily(userID uuid.UUID, familyID string) error {
+	query, err := r.db.Prepare("CALL DeleteTokenFamily(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteTokenFamily(%v, %v)", userID, familyID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID, familyID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteTokenFamily(%v, %v)", userID, familyID)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSessionTokens deletes all tokens issued along with the user session.
+func (r *Repository) DeleteSessionTokens(userID uuid.UUID, sessionID string) error {
+	query, err := r.db.Prepare("CALL DeleteSessionTokens(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteSessionTokens(%v, %v)", userID, sessionID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID, sessionID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteSessionTokens(%v, %v)", userID, sessionID)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteUserTokens deletes all login tokens of the user. Tokens issued along with the
+// session provided in exceptSessionID are kept.
+func (r *Repository) DeleteUserTokens(userID uuid.UUID, exceptSessionID string) error {
+	query, err := r.db.Prepare("CALL DeleteUserTokens(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteUserTokens(%v, %v)", userID, exceptSessionID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID, exceptSessionID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteUserTokens(%v, %v)", userID, exceptSessionID)
+		return err
+	}
+
+	return nil
+}
+
+// CreatePersonalAccessToken stores hashed personal access token of the user.
+func (r *Repository) CreatePersonalAccessToken(token *store.PersonalAccessToken) (*store.PersonalAccessToken, error) {
+	query, err := r.db.Prepare("CALL CreatePersonalAccessToken(?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreatePersonalAccessToken(%v, %v).",
+			token.UserID, token.Name)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	createdToken, err := scanPersonalAccessToken(query.QueryRow(token.UserID,
+		token.Name,
+		token.TokenHash,
+		token.RoleID,
+		strings.Join(token.Scopes, " "),
+		token.ExpiresAt))
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL CreatePersonalAccessToken(%v, %v).",
+			token.UserID, token.Name)
+		return nil, err
+	}
+
+	return createdToken, nil
+}
+
+// GetPersonalAccessTokens will retrieve all personal access tokens of the user, including the expired ones.
+func (r *Repository) GetPersonalAccessTokens(userID uuid.UUID) ([]*store.PersonalAccessToken, error) {
+	query, err := r.db.Prepare("CALL GetPersonalAccessTokens(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL GetPersonalAccessTokens(%v).", userID)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	rows, err := query.Query(userID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL GetPersonalAccessTokens(%v).", userID)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tokens := make([]*store.PersonalAccessToken, 0)
+
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetPersonalAccessTokenByHash will retrieve personal access token by its hash. Token is found only if it
+// can be used, expired tokens and tokens of inactive or deleted users are not returned.
+func (r *Repository) GetPersonalAccessTokenByHash(tokenHash string) (*store.PersonalAccessToken, error) {
+	query, err := r.db.Prepare("CALL GetPersonalAccessTokenByHash(?)")
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to prepare statement: CALL GetPersonalAccessTokenByHash.")
+		return nil, err
+	}
+
+	defer query.Close()
+
+	token, err := scanPersonalAccessToken(query.QueryRow(tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.PersonalAccessTokenNotFound)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("There was an error executing query: CALL GetPersonalAccessTokenByHash.")
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// TouchPersonalAccessToken sets the time personal access token was last used.
+func (r *Repository) TouchPersonalAccessToken(id int64, usedAt time.Time) error {
+	query, err := r.db.Prepare("CALL TouchPersonalAccessToken(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL TouchPersonalAccessToken(%v)", id)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(id, usedAt); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL TouchPersonalAccessToken(%v)", id)
+		return err
+	}
+
+	return nil
+}
+
+// DeletePersonalAccessToken deletes personal access token of the user.
+func (r *Repository) DeletePersonalAccessToken(userID uuid.UUID, id int64) error {
+	query, err := r.db.Prepare("CALL DeletePersonalAccessToken(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeletePersonalAccessToken(%v, %v)", userID, id)
+		return err
+	}
+
+	defer query.Close()
+
+	res, err := query.Exec(userID, id)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeletePersonalAccessToken(%v, %v)", userID, id)
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed after statement is executed: CALL DeletePersonalAccessToken(%v, %v)", userID, id)
+		return err
+	}
+
+	if ra == 0 {
+		return errors.New(store.PersonalAccessTokenNotFound)
+	}
+
+	return nil
+}
+
+func scanPersonalAccessToken(row rowScanner) (*store.PersonalAccessToken, error) {
+	token := new(store.PersonalAccessToken)
+
+	var scopes string
+
+	err := row.Scan(&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.RoleID,
+		&token.Role,
+		&scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes = strings.Fields(scopes)
+
+	return token, nil
+}
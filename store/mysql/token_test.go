@@ -0,0 +1,299 @@
+package mysqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/stretchr/testify/require"
+	"github.com/twinj/uuid"
+)
+
+func (s *RepositorySuite) TestGetTokenByTokenAndType() {
+	userID := uuid.NewV4()
+	tokenType := store.GetTokenTypes().RefreshToken
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		expected    *store.LoginToken
+		errorMsg    string
+	}{
+		{
+			name: "Success Case - Rotated refresh token",
+			queryResult: sqlmock.NewRows([]string{
+				"expired", "id", "user_id", "token", "token_type", "session_id", "family_id", "role_id", "used",
+			}).AddRow(
+				false, 1, userID, "token", tokenType, "session", "family", 2, true,
+			),
+			expected: &store.LoginToken{
+				ID:        1,
+				UserID:    userID,
+				Token:     "token",
+				TokenType: tokenType,
+				SessionID: "session",
+				FamilyID:  "family",
+				RoleID:    2,
+				Used:      true,
+			},
+		},
+		{
+			name: "Error Case - Token not found",
+			queryResult: sqlmock.NewRows([]string{
+				"expired", "id", "user_id", "token", "token_type", "session_id", "family_id", "role_id", "used",
+			}),
+			errorMsg: store.TokenNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetTokenByTokenAndType\\(\\?,\\?\\)$").
+				ExpectQuery().
+				WithArgs("token", tokenType).
+				WillReturnRows(tt.queryResult)
+
+			token, err := s.repo.GetTokenByTokenAndType("token", tokenType)
+
+			if tt.errorMsg != "" {
+				require.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, token)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestUseLoginToken() {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{
+			name:         "Success Case - Token rotated",
+			rowsAffected: 1,
+			expected:     true,
+		},
+		{
+			name:         "Success Case - Token already used",
+			rowsAffected: 0,
+			expected:     false,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL UseLoginToken\\(\\?\\)$").
+				ExpectExec().
+				WithArgs(int64(1)).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			used, err := s.repo.UseLoginToken(1)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, used)
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestAddLoginTokenFailedAttempt() {
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		expected    int64
+		errorMsg    string
+	}{
+		{
+			name:        "Success Case",
+			queryResult: sqlmock.NewRows([]string{"failed_attempts"}).AddRow(3),
+			expected:    3,
+		},
+		{
+			name:        "Error Case - Token not found",
+			queryResult: sqlmock.NewRows([]string{"failed_attempts"}),
+			errorMsg:    store.TokenNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL AddLoginTokenFailedAttempt\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(int64(1)).
+				WillReturnRows(tt.queryResult)
+
+			attempts, err := s.repo.AddLoginTokenFailedAttempt(1)
+
+			if tt.errorMsg != "" {
+				require.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, attempts)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestGetPersonalAccessTokenByHash() {
+	userID := uuid.NewV4()
+	expiresAt := time.Now().Add(time.Hour).UTC()
+	createdAt := time.Now().UTC()
+	columns := []string{
+		"id", "user_id", "name", "role_id", "role", "scopes", "expires_at", "last_used_at", "created_at",
+	}
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		expected    *store.PersonalAccessToken
+		errorMsg    string
+	}{
+		{
+			name: "Success Case - Token never used",
+			queryResult: sqlmock.NewRows(columns).AddRow(
+				1, userID, "CI", 2, "Admin", "users:read roles:read", expiresAt, nil, createdAt,
+			),
+			expected: &store.PersonalAccessToken{
+				ID:        1,
+				UserID:    userID,
+				Name:      "CI",
+				RoleID:    2,
+				Role:      "Admin",
+				Scopes:    []string{"users:read", "roles:read"},
+				ExpiresAt: expiresAt,
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name:        "Error Case - Token not found",
+			queryResult: sqlmock.NewRows(columns),
+			errorMsg:    store.PersonalAccessTokenNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetPersonalAccessTokenByHash\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs("hash").
+				WillReturnRows(tt.queryResult)
+
+			token, err := s.repo.GetPersonalAccessTokenByHash("hash")
+
+			if tt.errorMsg != "" {
+				require.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, token)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestDeletePersonalAccessToken() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		errorMsg     string
+	}{
+		{
+			name:         "Success Case",
+			rowsAffected: 1,
+		},
+		{
+			name:         "Error Case - Token of another user",
+			rowsAffected: 0,
+			errorMsg:     store.PersonalAccessTokenNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL DeletePersonalAccessToken\\(\\?, \\?\\)$").
+				ExpectExec().
+				WithArgs(userID, int64(1)).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			err := s.repo.DeletePersonalAccessToken(userID, 1)
+
+			if tt.errorMsg != "" {
+				require.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestGetPasswordTokenByToken() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		expected    *store.PasswordToken
+		errorMsg    string
+	}{
+		{
+			name: "Success Case - Invitation token",
+			queryResult: sqlmock.NewRows([]string{"user_id", "token", "expires_at", "invitation"}).
+				AddRow(userID, "token", 1700000000, true),
+			expected: &store.PasswordToken{
+				UserID:     userID,
+				Token:      "token",
+				ExpiresAt:  1700000000,
+				Invitation: true,
+			},
+		},
+		{
+			name: "Success Case - Reset token",
+			queryResult: sqlmock.NewRows([]string{"user_id", "token", "expires_at", "invitation"}).
+				AddRow(userID, "token", 1700000000, false),
+			expected: &store.PasswordToken{
+				UserID:    userID,
+				Token:     "token",
+				ExpiresAt: 1700000000,
+			},
+		},
+		{
+			name:        "Error Case - Token not found",
+			queryResult: sqlmock.NewRows([]string{"user_id", "token", "expires_at", "invitation"}),
+			errorMsg:    "TOKEN_NOT_FOUND",
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.mock.ExpectPrepare("^CALL GetPasswordTokenByToken\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs("token").
+				WillReturnRows(tt.queryResult)
+
+			token, err := s.repo.GetPasswordTokenByToken("token")
+
+			if tt.errorMsg != "" {
+				require.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, token)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
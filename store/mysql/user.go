@@ -8,6 +8,7 @@ import (
 
 	"github.com/adinovcina/golang-setup/store"
 	"github.com/adinovcina/golang-setup/tools/logger"
+	"github.com/go-sql-driver/mysql"
 	"github.com/twinj/uuid"
 )
 
@@ -25,7 +26,8 @@ func (r *Repository) GetUserByID(id uuid.UUID) (*store.User, error) {
 
 	err = query.QueryRow(id).
 		Scan(&user.ID, &user.Name, &user.Email, &user.Phone, &user.Language, &user.Active,
-			&user.Role, &user.CreatedAt)
+			&user.Role, &user.RoleID, &user.CreatedAt, &user.Password, &user.EmailVerified,
+			&user.FailedLoginCount, &user.LoginBlockedUntil, &user.DeletedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, errors.New(store.UserNotFound)
@@ -36,6 +38,10 @@ func (r *Repository) GetUserByID(id uuid.UUID) (*store.User, error) {
 		return nil, err
 	}
 
+	if err := r.decryptUser(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -59,6 +65,11 @@ func (r *Repository) GetUsers(filter *store.UserFilter) ([]*store.User, error) {
 	if v := filter.Active; v != nil {
 		where, args = append(where, "u.active = ?"), append(args, *v)
 	}
+	if v := filter.Deleted; v != nil && *v {
+		where = append(where, "u.deleted_at IS NOT NULL")
+	} else {
+		where = append(where, "u.deleted_at IS NULL")
+	}
 	if v := filter.Search; v != nil {
 		// Instead of using CONCAT in SQL, construct the pattern string directly
 		pattern := "%" + *v + "%"
@@ -146,6 +157,10 @@ func (r *Repository) GetUsers(filter *store.UserFilter) ([]*store.User, error) {
 			return nil, err
 		}
 
+		if err := r.decryptUser(user); err != nil {
+			return nil, err
+		}
+
 		users = append(users, user)
 	}
 
@@ -174,7 +189,7 @@ func (r *Repository) GetUsers(filter *store.UserFilter) ([]*store.User, error) {
 	return users, nil
 }
 
-// GetUserByEmail will retrieve the user filtered by email
+// GetUserByEmail will retrieve the user filtered by email. Email is looked up by its blind index.
 func (r *Repository) GetUserByEmail(email string) (*store.User, error) {
 	query, err := r.db.Prepare("CALL GetUserByEmail(?)")
 	if err != nil {
@@ -186,9 +201,10 @@ func (r *Repository) GetUserByEmail(email string) (*store.User, error) {
 
 	user := new(store.User)
 
-	err = query.QueryRow(email).
+	err = query.QueryRow(r.encryptor.BlindIndex(email)).
 		Scan(&user.ID, &user.Name, &user.Email,
-			&user.Password, &user.Active, &user.FailedLoginCount, &user.LoginBlockedUntil)
+			&user.Password, &user.Active, &user.FailedLoginCount, &user.LoginBlockedUntil,
+			&user.EmailVerified, &user.CreatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, errors.New(store.UserNotFound)
@@ -199,6 +215,10 @@ func (r *Repository) GetUserByEmail(email string) (*store.User, error) {
 		return nil, err
 	}
 
+	if err := r.decryptUser(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -229,5 +249,231 @@ func (r *Repository) GetUserByToken(token, tokenType string) (*store.User, error
 		return nil, err
 	}
 
+	if err := r.decryptUser(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
+
+// CreateUser will create a new user with the role assigned by the role name.
+func (r *Repository) CreateUser(user *store.User) (*store.User, error) {
+	query, err := r.db.Prepare("CALL CreateUser(?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreateUser(%v, %v, %v, %v).",
+			user.Name, user.Email, user.Active, user.Role)
+		return nil, err
+	}
+
+	defer query.Close()
+
+	encryptedName, err := r.encryptor.Encrypt(user.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedEmail, err := r.encryptor.Encrypt(user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	createdUser := new(store.User)
+
+	err = query.QueryRow(encryptedName, encryptedEmail, r.encryptor.BlindIndex(user.Email), user.Password,
+		user.Active, user.Role).
+		Scan(&createdUser.ID, &createdUser.Name, &createdUser.Email, &createdUser.Phone, &createdUser.Language,
+			&createdUser.Active, &createdUser.Role, &createdUser.RoleID, &createdUser.CreatedAt)
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == ErrDuplicateEntry {
+		return nil, errors.New(store.UserDuplicated)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msgf("There was an error executing query: CALL CreateUser(%v, %v, %v, %v).",
+			user.Name, user.Email, user.Active, user.Role)
+		return nil, err
+	}
+
+	if err := r.decryptUser(createdUser); err != nil {
+		return nil, err
+	}
+
+	return createdUser, nil
+}
+
+// CreateEmailVerificationToken persists the token sent in email verification link.
+// Previously issued tokens of the user which are not used are invalidated.
+func (r *Repository) CreateEmailVerificationToken(token *store.EmailVerificationToken) error {
+	query, err := r.db.Prepare("CALL CreateEmailVerificationToken(?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL CreateEmailVerificationToken(%v).", token.UserID)
+		return err
+	}
+
+	defer query.Close()
+
+	encryptedEmail, err := r.encryptor.Encrypt(token.Email)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.Exec(token.TokenHash, token.UserID, encryptedEmail, r.encryptor.BlindIndex(token.Email), token.ExpiresAt)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL CreateEmailVerificationToken(%v).", token.UserID)
+		return err
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the email address from the token as verified and sets it as user email.
+// Token can be used only once and only before it expires.
+func (r *Repository) VerifyEmail(tokenHash string) (*store.User, error) {
+	query, err := r.db.Prepare("CALL VerifyEmail(?)")
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to prepare statement: CALL VerifyEmail.")
+		return nil, err
+	}
+
+	defer query.Close()
+
+	user := new(store.User)
+
+	err = query.QueryRow(tokenHash).Scan(&user.ID, &user.Name, &user.Email, &user.EmailVerified)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(store.EmailVerificationTokenNotFound)
+	}
+
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == ErrDuplicateEntry {
+			return nil, errors.New(store.UserDuplicated)
+		}
+
+		logger.Error().Err(err).Msg("There was an error executing query: CALL VerifyEmail.")
+		return nil, err
+	}
+
+	if err := r.decryptUser(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUserDetails updates user details edited by admin. Email has to be verified again if it is changed.
+func (r *Repository) UpdateUserDetails(user *store.User) error {
+	query, err := r.db.Prepare("CALL UpdateUserDetails(?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL UpdateUserDetails(%v).", user.ID)
+		return err
+	}
+
+	defer query.Close()
+
+	encryptedName, err := r.encryptor.Encrypt(user.Name)
+	if err != nil {
+		return err
+	}
+
+	encryptedEmail, err := r.encryptor.Encrypt(user.Email)
+	if err != nil {
+		return err
+	}
+
+	encryptedPhone, err := r.encryptor.Encrypt(user.Phone)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.Exec(user.ID, encryptedName, encryptedEmail, r.encryptor.BlindIndex(user.Email), encryptedPhone,
+		user.Language)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == ErrDuplicateEntry {
+			return errors.New(store.UserDuplicated)
+		}
+
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL UpdateUserDetails(%v).", user.ID)
+		return err
+	}
+
+	return nil
+}
+
+// SetUserRoles replaces roles assigned to the user.
+func (r *Repository) SetUserRoles(userID uuid.UUID, roleIDs []int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to begin transaction for roles of user %v.", userID)
+		return err
+	}
+
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is no-op
+
+	deleteQuery, err := tx.Prepare("CALL DeleteUserRoles(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL DeleteUserRoles(%v).", userID)
+		return err
+	}
+
+	defer deleteQuery.Close()
+
+	if _, err = deleteQuery.Exec(userID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL DeleteUserRoles(%v).", userID)
+		return err
+	}
+
+	addQuery, err := tx.Prepare("CALL AddUserRole(?, ?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL AddUserRole(%v).", userID)
+		return err
+	}
+
+	defer addQuery.Close()
+
+	for _, roleID := range roleIDs {
+		if _, err = addQuery.Exec(userID, roleID); err != nil {
+			logger.Error().Err(err).Msgf("failed to execute statement: CALL AddUserRole(%v, %v).", userID, roleID)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UnlockUser clears failed login attempts of the user, so user suspended after too many
+// failed logins can log in again.
+func (r *Repository) UnlockUser(userID uuid.UUID) error {
+	return r.execUserProcedure("UnlockUser", userID)
+}
+
+// DeleteUser marks the user as deleted and deletes his password tokens. Deleted user can not log in and
+// is not listed, but can be restored.
+func (r *Repository) DeleteUser(userID uuid.UUID) error {
+	return r.execUserProcedure("DeleteUser", userID)
+}
+
+// RestoreUser restores the deleted user.
+func (r *Repository) RestoreUser(userID uuid.UUID) error {
+	return r.execUserProcedure("RestoreUser", userID)
+}
+
+// execUserProcedure executes the stored procedure which takes user id as the only parameter.
+func (r *Repository) execUserProcedure(name string, userID uuid.UUID) error {
+	query, err := r.db.Prepare("CALL " + name + "(?)")
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to prepare statement: CALL %s(%v).", name, userID)
+		return err
+	}
+
+	defer query.Close()
+
+	if _, err = query.Exec(userID); err != nil {
+		logger.Error().Err(err).Msgf("failed to execute statement: CALL %s(%v).", name, userID)
+		return err
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/adinovcina/golang-setup/store"
+	"github.com/go-sql-driver/mysql"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 	"github.com/twinj/uuid"
@@ -13,6 +14,7 @@ import (
 
 func (s *RepositorySuite) TestGetUserByEmail() {
 	userID := uuid.NewV4()
+	createdAt := time.Now().UTC().Truncate(time.Second)
 
 	tests := []struct {
 		name        string
@@ -25,11 +27,11 @@ func (s *RepositorySuite) TestGetUserByEmail() {
 			name: "Success Case",
 			queryResult: sqlmock.NewRows([]string{
 				"ID", "Name", "Email", "Password",
-				"Active", "FailedLoginCount", "LoginBlockedUntil",
+				"Active", "FailedLoginCount", "LoginBlockedUntil", "EmailVerified", "CreatedAt",
 			}).
 				AddRow(
-					userID, "test user", "test@gmail.com", "$2a$10$HnQIEV5YpB8BxXjr6p5UuuVo901a/W/fHo3GDHbslZw1RZvYsPtWG",
-					true, 0, nil,
+					userID, "test user", s.encrypt("test@gmail.com"), "$2a$10$HnQIEV5YpB8BxXjr6p5UuuVo901a/W/fHo3GDHbslZw1RZvYsPtWG",
+					true, 0, nil, true, createdAt,
 				),
 			expected: &store.User{
 				ID:                userID,
@@ -39,6 +41,8 @@ func (s *RepositorySuite) TestGetUserByEmail() {
 				Active:            true,
 				FailedLoginCount:  0,
 				LoginBlockedUntil: nil,
+				EmailVerified:     true,
+				CreatedAt:         createdAt,
 			},
 			expectErr: false,
 			errorMsg:  nil,
@@ -57,7 +61,7 @@ func (s *RepositorySuite) TestGetUserByEmail() {
 		s.T().Run(tt.name, func(t *testing.T) {
 			s.mock.ExpectPrepare("^CALL GetUserByEmail\\(\\?\\)$").
 				ExpectQuery().
-				WithArgs(tt.expected.Email).
+				WithArgs(s.repo.encryptor.BlindIndex(tt.expected.Email)).
 				WillReturnRows(tt.queryResult)
 
 			user, err := s.repo.GetUserByEmail(tt.expected.Email)
@@ -93,21 +97,28 @@ func (s *RepositorySuite) TestGetUserByID() {
 			name: "Success Case",
 			queryResult: sqlmock.NewRows([]string{
 				"ID", "Name", "Email", "Phone",
-				"Language", "Active", "Role", "CreatedAt",
+				"Language", "Active", "Role", "RoleID", "CreatedAt", "Password",
+				"EmailVerified", "FailedLoginCount", "LoginBlockedUntil", "DeletedAt",
 			}).
 				AddRow(
-					userID, "test user", "test@gmail.com", "+12312313",
-					"en", true, "user", currentTime,
+					userID, "test user", s.encrypt("test@gmail.com"), s.encrypt("+12312313"),
+					"en", true, "user", 2, currentTime, "$2a$10$HnQIEV5YpB8BxXjr6p5UuuVo901a/W/fHo3GDHbslZw1RZvYsPtWG",
+					true, 3, nil, &currentTime,
 				),
 			expected: &store.User{
-				ID:        userID,
-				Name:      "test user",
-				Email:     "test@gmail.com",
-				Phone:     "+12312313",
-				Language:  "en",
-				Role:      "user",
-				CreatedAt: currentTime,
-				Active:    true,
+				ID:               userID,
+				Name:             "test user",
+				Email:            "test@gmail.com",
+				Phone:            "+12312313",
+				Language:         "en",
+				Role:             "user",
+				RoleID:           2,
+				CreatedAt:        currentTime,
+				Active:           true,
+				Password:         "$2a$10$HnQIEV5YpB8BxXjr6p5UuuVo901a/W/fHo3GDHbslZw1RZvYsPtWG",
+				EmailVerified:    true,
+				FailedLoginCount: 3,
+				DeletedAt:        &currentTime,
 			},
 			expectErr:      false,
 			errorMsg:       nil,
@@ -149,3 +160,238 @@ func (s *RepositorySuite) TestGetUserByID() {
 		})
 	}
 }
+
+func (s *RepositorySuite) TestCreateUser() {
+	currentTime := time.Now()
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		queryErr    error
+		user        *store.User
+		expected    *store.User
+		expectErr   bool
+		errorMsg    interface{}
+	}{
+		{
+			name: "Success Case",
+			queryResult: sqlmock.NewRows([]string{
+				"ID", "Name", "Email", "Phone", "Language",
+				"Active", "Role", "RoleID", "CreatedAt",
+			}).
+				AddRow(
+					userID, s.encrypt("test user"), s.encrypt("test@gmail.com"), "", "en",
+					false, "User", 2, currentTime,
+				),
+			user: &store.User{
+				Name:  "test user",
+				Email: "test@gmail.com",
+				Role:  "User",
+			},
+			expected: &store.User{
+				ID:        userID,
+				Name:      "test user",
+				Email:     "test@gmail.com",
+				Language:  "en",
+				Role:      "User",
+				RoleID:    2,
+				CreatedAt: currentTime,
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name:     "Error Case - Email already exists",
+			queryErr: &mysql.MySQLError{Number: ErrDuplicateEntry, Message: "Duplicate entry"},
+			user: &store.User{
+				Name:  "test user",
+				Email: "admin@gmail.com",
+				Role:  "User",
+			},
+			expectErr: true,
+			errorMsg:  store.UserDuplicated,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			expectedQuery := s.mock.ExpectPrepare("^CALL CreateUser\\(\\?, \\?, \\?, \\?, \\?, \\?\\)$").
+				ExpectQuery().
+				WithArgs(EncryptedValue{s.repo.encryptor, tt.user.Name}, EncryptedValue{s.repo.encryptor, tt.user.Email},
+					s.repo.encryptor.BlindIndex(tt.user.Email), tt.user.Password, tt.user.Active, tt.user.Role)
+
+			if tt.queryErr != nil {
+				expectedQuery.WillReturnError(tt.queryErr)
+			} else {
+				expectedQuery.WillReturnRows(tt.queryResult)
+			}
+
+			user, err := s.repo.CreateUser(tt.user)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				require.Equal(t, tt.errorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, user)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestVerifyEmail() {
+	userID := uuid.NewV4()
+	tokenHash := "0f2b8c1a9d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a"
+
+	tests := []struct {
+		name        string
+		queryResult *sqlmock.Rows
+		queryErr    error
+		expected    *store.User
+		expectErr   bool
+		errorMsg    interface{}
+	}{
+		{
+			name: "Success Case",
+			queryResult: sqlmock.NewRows([]string{"ID", "Name", "Email", "EmailVerified"}).
+				AddRow(userID, "test user", "new@gmail.com", true),
+			expected: &store.User{
+				ID:            userID,
+				Name:          "test user",
+				Email:         "new@gmail.com",
+				EmailVerified: true,
+			},
+			expectErr: false,
+			errorMsg:  nil,
+		},
+		{
+			name:        "Error Case - Token not found, used or expired",
+			queryResult: sqlmock.NewRows([]string{"ID", "Name", "Email", "EmailVerified"}),
+			expectErr:   true,
+			errorMsg:    store.EmailVerificationTokenNotFound,
+		},
+		{
+			name:      "Error Case - Email taken by another user",
+			queryErr:  &mysql.MySQLError{Number: ErrDuplicateEntry, Message: "Duplicate entry"},
+			expectErr: true,
+			errorMsg:  store.UserDuplicated,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			expectedQuery := s.mock.ExpectPrepare("^CALL VerifyEmail\\(\\?\\)$").
+				ExpectQuery().
+				WithArgs(tokenHash)
+
+			if tt.queryErr != nil {
+				expectedQuery.WillReturnError(tt.queryErr)
+			} else {
+				expectedQuery.WillReturnRows(tt.queryResult)
+			}
+
+			user, err := s.repo.VerifyEmail(tokenHash)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				require.Equal(t, tt.errorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, user)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestUpdateUserDetails() {
+	userID := uuid.NewV4()
+
+	tests := []struct {
+		name      string
+		user      *store.User
+		queryErr  error
+		expectErr bool
+		errorMsg  interface{}
+	}{
+		{
+			name: "Success Case",
+			user: &store.User{
+				ID:       userID,
+				Name:     "test user",
+				Email:    "test@gmail.com",
+				Phone:    "+12312313",
+				Language: "en",
+			},
+			expectErr: false,
+		},
+		{
+			name: "Error Case - Email already exists",
+			user: &store.User{
+				ID:       userID,
+				Name:     "test user",
+				Email:    "admin@gmail.com",
+				Language: "en",
+			},
+			queryErr:  &mysql.MySQLError{Number: ErrDuplicateEntry, Message: "Duplicate entry"},
+			expectErr: true,
+			errorMsg:  store.UserDuplicated,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			var phone interface{} = ""
+			if tt.user.Phone != "" {
+				phone = EncryptedValue{s.repo.encryptor, tt.user.Phone}
+			}
+
+			expectedExec := s.mock.ExpectPrepare("^CALL UpdateUserDetails\\(\\?, \\?, \\?, \\?, \\?, \\?\\)$").
+				ExpectExec().
+				WithArgs(tt.user.ID, EncryptedValue{s.repo.encryptor, tt.user.Name}, EncryptedValue{s.repo.encryptor, tt.user.Email},
+					s.repo.encryptor.BlindIndex(tt.user.Email), phone, tt.user.Language)
+
+			if tt.queryErr != nil {
+				expectedExec.WillReturnError(tt.queryErr)
+			} else {
+				expectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			err := s.repo.UpdateUserDetails(tt.user)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				require.Equal(t, tt.errorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			err = s.mock.ExpectationsWereMet()
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *RepositorySuite) TestSetUserRoles() {
+	userID := uuid.NewV4()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectPrepare("^CALL DeleteUserRoles\\(\\?\\)$").
+		ExpectExec().
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	addQuery := s.mock.ExpectPrepare("^CALL AddUserRole\\(\\?, \\?\\)$")
+	addQuery.ExpectExec().WithArgs(userID, int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	addQuery.ExpectExec().WithArgs(userID, int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.SetUserRoles(userID, []int64{1, 2})
+	s.Require().NoError(err)
+
+	err = s.mock.ExpectationsWereMet()
+	s.Require().NoError(err)
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"time"
+
+	"github.com/adinovcina/golang-setup/tools/utils"
+	"github.com/twinj/uuid"
+)
+
+const (
+	OAuthClientNotFound       = "oauth client not found"
+	OAuthClientDuplicated     = "duplicate oauth client"
+	AuthorizationCodeNotFound = "authorization code not found"
+)
+
+type OAuthRepository interface {
+	CreateOAuthClient(client *OAuthClient, createdBy uuid.UUID) (*OAuthClient, error)
+	GetOAuthClientByID(id string) (*OAuthClient, error)
+	DeleteOAuthClient(id string) error
+	CreateAuthorizationCode(code *AuthorizationCode) error
+	ConsumeAuthorizationCode(codeHash string) (*AuthorizationCode, error)
+}
+
+// GetGrantTypes get supported OAuth2 grant types.
+func GetGrantTypes() GrantTypes {
+	return GrantTypes{
+		AuthorizationCode: "authorization_code",
+		ClientCredentials: "client_credentials",
+	}
+}
+
+// GrantTypes struct used to describe OAuth2 grant types.
+type GrantTypes struct {
+	AuthorizationCode string
+	ClientCredentials string
+}
+
+// OAuthClient is third-party application registered to log users in through us.
+type OAuthClient struct {
+	CreatedAt    time.Time `json:"createdAt"`
+	ID           string    `json:"clientID"`
+	Name         string    `json:"name"`
+	SecretHash   string    `json:"-"`
+	RedirectURIs []string  `json:"redirectURIs"`
+	GrantTypes   []string  `json:"grantTypes"`
+	Scopes       []string  `json:"scopes"`
+}
+
+// IsConfidential checks if client has a secret. Public clients must use PKCE.
+func (c *OAuthClient) IsConfidential() bool {
+	return c.SecretHash != ""
+}
+
+// AllowsGrantType checks if client is allowed to use the grant type.
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	return utils.Contains(c.GrantTypes, grantType)
+}
+
+// AllowsRedirectURI checks if redirect URI exactly matches one of the registered URIs.
+func (c *OAuthClient) AllowsRedirectURI(redirectURI string) bool {
+	return utils.Contains(c.RedirectURIs, redirectURI)
+}
+
+// AllowsScopes checks if all requested scopes are registered for the client.
+func (c *OAuthClient) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !utils.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AuthorizationCode is issued to the client after user authorizes it and exchanged for tokens.
+type AuthorizationCode struct {
+	ExpiresAt           time.Time
+	CodeHash            string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+	UserID              uuid.UUID
+	Used                bool
+	Expired             bool
+}
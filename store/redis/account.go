@@ -2,17 +2,67 @@ package redisstore
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/adinovcina/golang-setup/store"
+	"github.com/adinovcina/golang-setup/tools/lockout"
 	"github.com/adinovcina/golang-setup/tools/utils"
+	r "github.com/redis/go-redis/v9"
 	"github.com/twinj/uuid"
 )
 
-// SetSession - sets user session. Expects userID, sessionID and Client name.
-func (s *RedisStore) SetSession(ctx context.Context, uid uuid.UUID, sid, v string, redisTokenTTL time.Duration) error {
-	// Since refresh token data is stored in claim as well, we will keep it in Redis 10 times longer than
-	// actual Token expiration time
-	return s.redis.Set(ctx, utils.FormatSessionKey(uid, sid), v, redisTokenTTL).Err()
+// sessionTouchInterval limits how often last seen time of the session is updated.
+const sessionTouchInterval = time.Minute
+
+// SetSession - sets user session and adds it to the index of user sessions. Expects userID, session
+// metadata and session value. Creation time of already indexed session is kept, so refreshed session
+// is still reported as created at login time.
+func (s *RedisStore) SetSession(ctx context.Context, uid uuid.UUID, session *store.Session, v string,
+	redisTokenTTL time.Duration,
+) error {
+	indexKey := utils.FormatSessionIndexKey(uid)
+
+	existing, err := s.getIndexedSession(ctx, uid, session.ID)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		session.CreatedAt = existing.CreatedAt
+	}
+
+	meta, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.redis.TxPipelined(ctx, func(pipe r.Pipeliner) error {
+		// Since refresh token data is stored in claim as well, we will keep it in Redis 10 times longer than
+		// actual Token expiration time
+		pipe.Set(ctx, utils.FormatSessionKey(uid, session.ID), v, redisTokenTTL)
+		pipe.HSet(ctx, indexKey, session.ID, meta)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Index has to live at least as long as the session it contains
+	ttl, err := s.redis.TTL(ctx, indexKey).Result()
+	if err != nil {
+		return err
+	}
+
+	if ttl < redisTokenTTL {
+		return s.redis.Expire(ctx, indexKey, redisTokenTTL).Err()
+	}
+
+	return nil
 }
 
 // GetSession - gets user session. Expects userID and sessionID.
@@ -25,12 +75,297 @@ func (s *RedisStore) GetSession(ctx context.Context, uid uuid.UUID, sid string)
 	return value, nil
 }
 
-// DelSession - del user session. Expects userID and sessionID.
+// GetSessions - gets metadata of all active user sessions, most recently used first. Expired sessions
+// are removed from the index.
+func (s *RedisStore) GetSessions(ctx context.Context, uid uuid.UUID) ([]*store.Session, error) {
+	indexKey := utils.FormatSessionIndexKey(uid)
+
+	indexed, err := s.redis.HGetAll(ctx, indexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*store.Session, 0, len(indexed))
+	expired := make([]string, 0)
+
+	for sid, meta := range indexed {
+		exists, err := s.redis.Exists(ctx, utils.FormatSessionKey(uid, sid)).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		if exists == 0 {
+			expired = append(expired, sid)
+			continue
+		}
+
+		session := new(store.Session)
+		if err := json.Unmarshal([]byte(meta), session); err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if len(expired) > 0 {
+		if err := s.redis.HDel(ctx, indexKey, expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+// TouchSession - updates last seen time of indexed user session. Expects userID and sessionID.
+func (s *RedisStore) TouchSession(ctx context.Context, uid uuid.UUID, sid string, seenAt time.Time) error {
+	session, err := s.getIndexedSession(ctx, uid, sid)
+	if err != nil || session == nil {
+		return err
+	}
+
+	// Avoid writing to Redis on every request
+	if seenAt.Sub(session.LastSeenAt) < sessionTouchInterval {
+		return nil
+	}
+
+	session.LastSeenAt = seenAt
+
+	meta, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	return s.redis.HSet(ctx, utils.FormatSessionIndexKey(uid), sid, meta).Err()
+}
+
+// DelSession - del user session and removes it from the index. Expects userID and sessionID.
 func (s *RedisStore) DelSession(ctx context.Context, uid uuid.UUID, sid string) error {
-	return s.redis.Del(ctx, utils.FormatSessionKey(uid, sid)).Err()
+	_, err := s.redis.TxPipelined(ctx, func(pipe r.Pipeliner) error {
+		pipe.Del(ctx, utils.FormatSessionKey(uid, sid))
+		pipe.HDel(ctx, utils.FormatSessionIndexKey(uid), sid)
+
+		return nil
+	})
+
+	return err
+}
+
+// DelSessions - del all user sessions except the one provided in exceptSID. Returns IDs of deleted sessions.
+func (s *RedisStore) DelSessions(ctx context.Context, uid uuid.UUID, exceptSID string) ([]string, error) {
+	indexKey := utils.FormatSessionIndexKey(uid)
+
+	sids, err := s.redis.HKeys(ctx, indexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]string, 0, len(sids))
+
+	for _, sid := range sids {
+		if sid != exceptSID {
+			deleted = append(deleted, sid)
+		}
+	}
+
+	if len(deleted) == 0 {
+		return deleted, nil
+	}
+
+	_, err = s.redis.TxPipelined(ctx, func(pipe r.Pipeliner) error {
+		for _, sid := range deleted {
+			pipe.Del(ctx, utils.FormatSessionKey(uid, sid))
+		}
+
+		pipe.HDel(ctx, indexKey, deleted...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
 }
 
 // DelSessionWithKey - del user session. Expects session key.
 func (s *RedisStore) DelSessionWithKey(ctx context.Context, key string) error {
 	return s.redis.Del(ctx, key).Err()
 }
+
+// Throttle - allows the action identified by the key at most once per interval. Returns false if
+// the action was already allowed within the interval.
+func (s *RedisStore) Throttle(ctx context.Context, key string, interval time.Duration) (bool, error) {
+	return s.redis.SetNX(ctx, key, time.Now().Unix(), interval).Result()
+}
+
+// rateLimitScript keeps timestamps of allowed requests in a sorted set, requests older than the window
+// are removed first. Request is allowed and recorded while fewer than limit requests are in the set,
+// otherwise time until the oldest request leaves the window is returned.
+var rateLimitScript = r.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+if redis.call('ZCARD', KEYS[1]) < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, 0}
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// RateLimit - allows the action identified by the key at most limit times within the sliding window.
+// Returns false along with the time after which the action is allowed again once the limit is reached.
+func (s *RedisStore) RateLimit(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	now := time.Now().UnixMilli()
+
+	result, err := rateLimitScript.Run(ctx, s.redis, []string{key},
+		now, window.Milliseconds(), limit, fmt.Sprintf("%d:%s", now, utils.GenerateUniqueID())).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// loginFailureCounter is stored in Redis hash with number of failed logins and time of the latest one.
+type loginFailureCounter struct {
+	Count int64 `redis:"count"`
+	// LastFailedAt is in milliseconds
+	LastFailedAt int64 `redis:"last"`
+}
+
+// loginFailureKey links the key of Redis hash to the counter it is loaded into.
+type loginFailureKey struct {
+	counter *lockout.Counter
+	key     string
+}
+
+// GetLoginFailures - gets failed logins counted for the IP address and, when user is known, for the account.
+func (s *RedisStore) GetLoginFailures(ctx context.Context, uid *uuid.UUID, ip string) (*lockout.Failures, error) {
+	failures := new(lockout.Failures)
+	keys := loginFailureKeys(failures, uid, ip)
+
+	cmds := make([]*r.SliceCmd, len(keys))
+
+	_, err := s.redis.Pipelined(ctx, func(pipe r.Pipeliner) error {
+		for i, k := range keys {
+			cmds[i] = pipe.HMGet(ctx, k.key, "count", "last")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, k := range keys {
+		var counter loginFailureCounter
+		if err := cmds[i].Scan(&counter); err != nil {
+			return nil, err
+		}
+
+		if counter.Count > 0 {
+			*k.counter = lockout.Counter{Count: counter.Count, LastFailedAt: time.UnixMilli(counter.LastFailedAt)}
+		}
+	}
+
+	return failures, nil
+}
+
+// AddLoginFailure - counts failed login for the IP address and, when user is known, for the account. Counters
+// are removed once no failure is added within the window. Returns the updated counters.
+func (s *RedisStore) AddLoginFailure(ctx context.Context, uid *uuid.UUID, ip string, window time.Duration,
+) (*lockout.Failures, error) {
+	failures := new(lockout.Failures)
+	keys := loginFailureKeys(failures, uid, ip)
+
+	now := time.Now()
+	cmds := make([]*r.IntCmd, len(keys))
+
+	_, err := s.redis.TxPipelined(ctx, func(pipe r.Pipeliner) error {
+		for i, k := range keys {
+			cmds[i] = pipe.HIncrBy(ctx, k.key, "count", 1)
+			pipe.HSet(ctx, k.key, "last", now.UnixMilli())
+			pipe.PExpire(ctx, k.key, window)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, k := range keys {
+		*k.counter = lockout.Counter{Count: cmds[i].Val(), LastFailedAt: now}
+	}
+
+	return failures, nil
+}
+
+// DelLoginFailures - deletes failed logins counted for the account and for the account from the IP address.
+// Counters of the account from all IP addresses are deleted if IP address is empty. Counter of the IP
+// address itself is kept, since it is not related to the account.
+func (s *RedisStore) DelLoginFailures(ctx context.Context, uid uuid.UUID, ip string) error {
+	keys := []string{utils.FormatAccountLoginFailuresKey(uid)}
+
+	if ip != "" {
+		keys = append(keys, utils.FormatAccountIPLoginFailuresKey(uid, ip))
+	} else {
+		iter := s.redis.Scan(ctx, 0, utils.FormatAccountIPLoginFailuresKey(uid, "*"), 0).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+
+		if err := iter.Err(); err != nil {
+			return err
+		}
+	}
+
+	return s.redis.Del(ctx, keys...).Err()
+}
+
+// loginFailureKeys returns keys of counters which apply to the login. Account counters are skipped
+// when user is not known.
+func loginFailureKeys(failures *lockout.Failures, uid *uuid.UUID, ip string) []loginFailureKey {
+	keys := []loginFailureKey{
+		{counter: &failures.IP, key: utils.FormatIPLoginFailuresKey(ip)},
+	}
+
+	if uid != nil {
+		keys = append(keys,
+			loginFailureKey{counter: &failures.Account, key: utils.FormatAccountLoginFailuresKey(*uid)},
+			loginFailureKey{counter: &failures.AccountIP, key: utils.FormatAccountIPLoginFailuresKey(*uid, ip)},
+		)
+	}
+
+	return keys
+}
+
+// getIndexedSession returns metadata of the session from the index, or nil if session is not indexed.
+func (s *RedisStore) getIndexedSession(ctx context.Context, uid uuid.UUID, sid string) (*store.Session, error) {
+	meta, err := s.redis.HGet(ctx, utils.FormatSessionIndexKey(uid), sid).Result()
+	if errors.Is(err, r.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	session := new(store.Session)
+	if err := json.Unmarshal([]byte(meta), session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
@@ -0,0 +1,65 @@
+package store
+
+import "github.com/twinj/uuid"
+
+const (
+	RoleNotFound   = "role not found"
+	RoleDuplicated = "duplicate role"
+	RoleInUse      = "role is assigned to users"
+)
+
+type RoleRepository interface {
+	GetAllRoles() ([]*Role, error)
+	GetRoleByID(id int64) (*Role, error)
+	CreateRole(role *Role) (*Role, error)
+	UpdateRole(role *Role) (*Role, error)
+	DeleteRole(id int64) error
+	GetAllPermissions() ([]*Permission, error)
+	AddUserRole(userID uuid.UUID, roleID int64) error
+	RemoveUserRole(userID uuid.UUID, roleID int64) error
+	GetRoleUserIDs(roleID int64) ([]uuid.UUID, error)
+}
+
+// GetPermissions get permissions checked by the application. Permissions are stored in
+// permissions table and granted to the roles through role_permissions table.
+func GetPermissions() Permissions {
+	return Permissions{
+		UsersRead:        "users:read",
+		UsersCreate:      "users:create",
+		UsersUpdate:      "users:update",
+		UsersActivate:    "users:activate",
+		UsersSessions:    "users:sessions",
+		UsersDelete:      "users:delete",
+		UsersImpersonate: "users:impersonate",
+		RolesRead:        "roles:read",
+		RolesManage:      "roles:manage",
+		ClientsManage:    "clients:manage",
+		TasksManage:      "tasks:manage",
+		TermsManage:      "terms:manage",
+		AuditRead:        "audit:read",
+	}
+}
+
+// Permissions struct used to describe permissions.
+type Permissions struct {
+	UsersRead        string
+	UsersCreate      string
+	UsersUpdate      string
+	UsersActivate    string
+	UsersSessions    string
+	UsersDelete      string
+	UsersImpersonate string
+	RolesRead        string
+	RolesManage      string
+	ClientsManage    string
+	TasksManage      string
+	TermsManage      string
+	AuditRead        string
+}
+
+// Permission model.
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ID          int64  `json:"id"`
+}
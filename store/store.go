@@ -14,6 +14,12 @@ type Repository interface {
 	AccountRepository
 	UserRepository
 	TokenRepository
+	TaskRepository
+	MFARepository
+	OAuthRepository
+	RoleRepository
+	TermsRepository
+	AuditRepository
 }
 
 type InMemRepository interface {
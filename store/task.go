@@ -0,0 +1,100 @@
+package store
+
+import (
+	"time"
+
+	"github.com/adinovcina/golang-setup/tools/utils"
+	"github.com/twinj/uuid"
+)
+
+const (
+	TaskNotFound = "task not found"
+)
+
+type TaskRepository interface {
+	CreateTask(task *Task) (*Task, error)
+	GetTaskByID(id int64) (*Task, error)
+	GetTasks(filter *TaskFilter) ([]*Task, error)
+	UpdateTask(task *Task) (*Task, error)
+	AssignTask(id int64, assignedTo *uuid.UUID) (*Task, error)
+	UpdateTaskStatus(id int64, status string) (*Task, error)
+	DeleteTask(id int64) error
+}
+
+// GetTaskStatuses get available task statuses.
+func GetTaskStatuses() TaskStatuses {
+	return TaskStatuses{
+		Todo:       "TODO",
+		InProgress: "IN_PROGRESS",
+		Done:       "DONE",
+		Cancelled:  "CANCELLED",
+	}
+}
+
+// TaskStatuses struct used to describe task statuses.
+type TaskStatuses struct {
+	Todo       string
+	InProgress string
+	Done       string
+	Cancelled  string
+}
+
+// taskStatusTransitions contains list of statuses task can be moved to from the current status.
+func taskStatusTransitions() map[string][]string {
+	statuses := GetTaskStatuses()
+
+	return map[string][]string{
+		statuses.Todo:       {statuses.InProgress, statuses.Cancelled},
+		statuses.InProgress: {statuses.Todo, statuses.Done, statuses.Cancelled},
+		statuses.Done:       {statuses.InProgress},
+		statuses.Cancelled:  {statuses.Todo},
+	}
+}
+
+// IsValidTaskStatus checks if status is one of the supported task statuses.
+func IsValidTaskStatus(status string) bool {
+	_, ok := taskStatusTransitions()[status]
+
+	return ok
+}
+
+// Task model.
+type Task struct {
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+	AssignedTo  *uuid.UUID `json:"assignedTo,omitempty"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	ID          int64      `json:"id"`
+	CreatedBy   uuid.UUID  `json:"createdBy"`
+}
+
+// CanTransitionTo checks if task can be moved from its current status to the given one.
+func (t *Task) CanTransitionTo(status string) bool {
+	return utils.Contains(taskStatusTransitions()[t.Status], status)
+}
+
+// IsVisibleTo checks if user created the task or the task is assigned to him.
+func (t *Task) IsVisibleTo(userID uuid.UUID) bool {
+	return t.CreatedBy == userID || (t.AssignedTo != nil && *t.AssignedTo == userID)
+}
+
+type TaskFilter struct {
+	Status     *string
+	Search     *string
+	AssignedTo *uuid.UUID
+	CreatedBy  *uuid.UUID
+	// VisibleTo limits tasks to the ones user created or is assigned to
+	VisibleTo *uuid.UUID
+}
+
+// TasksKeyToColumnMap - a map of sorting keys matching their DB values.
+func TasksKeyToColumnMap() map[string]string {
+	tasksToColumnMap := map[string]string{
+		"id": "t.id",
+	}
+
+	return tasksToColumnMap
+}
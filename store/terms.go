@@ -0,0 +1,40 @@
+package store
+
+import (
+	"time"
+
+	"github.com/twinj/uuid"
+)
+
+const (
+	TermsNotFound   = "terms not found"
+	TermsDuplicated = "duplicate terms version"
+)
+
+type TermsRepository interface {
+	GetCurrentTerms() (*Terms, error)
+	CreateTerms(terms *Terms, createdBy uuid.UUID) (*Terms, error)
+	AcceptTerms(userID uuid.UUID, termsID int64, ipAddress string) error
+	GetUserTermsAcceptance(userID uuid.UUID) (*TermsAcceptance, error)
+}
+
+// Terms is a version of terms of service. Version with the latest publish date in the past is the current one.
+type Terms struct {
+	PublishedAt time.Time `json:"publishedAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Version     string    `json:"version"`
+	Content     string    `json:"content"`
+	ID          int64     `json:"id"`
+}
+
+// TermsAcceptance tells if user accepted the current version of terms of service.
+type TermsAcceptance struct {
+	AcceptedAt *time.Time `json:"acceptedAt"`
+	Version    string     `json:"version"`
+	TermsID    int64      `json:"termsID"`
+}
+
+// Accepted checks if user accepted the terms version.
+func (ta *TermsAcceptance) Accepted() bool {
+	return ta.AcceptedAt != nil
+}
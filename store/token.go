@@ -1,16 +1,32 @@
 package store
 
-import "github.com/twinj/uuid"
+import (
+	"time"
+
+	"github.com/twinj/uuid"
+)
 
 const (
-	TokenNotFound = "token not found"
+	TokenNotFound               = "token not found"
+	PersonalAccessTokenNotFound = "personal access token not found"
 )
 
 type TokenRepository interface {
-	AddPasswordResetToken(userID uuid.UUID, token string, expiresAt int64) (*PasswordToken, error)
+	AddPasswordResetToken(userID uuid.UUID, token string, expiresAt int64, invitation bool) (*PasswordToken, error)
 	GetPasswordTokenByToken(token string) (*PasswordToken, error)
+	DeletePasswordTokens(userID uuid.UUID) error
 	GetTokenByTokenAndType(token, tokenType string) (*LoginToken, error)
 	DeleteTokenByID(id int64) error
+	UseLoginToken(id int64) (bool, error)
+	AddLoginTokenFailedAttempt(id int64) (int64, error)
+	DeleteTokenFamily(userID uuid.UUID, familyID string) error
+	DeleteSessionTokens(userID uuid.UUID, sessionID string) error
+	DeleteUserTokens(userID uuid.UUID, exceptSessionID string) error
+	CreatePersonalAccessToken(token *PersonalAccessToken) (*PersonalAccessToken, error)
+	GetPersonalAccessTokens(userID uuid.UUID) ([]*PersonalAccessToken, error)
+	GetPersonalAccessTokenByHash(tokenHash string) (*PersonalAccessToken, error)
+	TouchPersonalAccessToken(id int64, usedAt time.Time) error
+	DeletePersonalAccessToken(userID uuid.UUID, id int64) error
 }
 
 // GetTokenTypes get available token types.
@@ -28,18 +44,39 @@ type Tokens struct {
 	RefreshToken string
 }
 
-// LoginToken represents token struct.
+// LoginToken represents token struct. SessionID, FamilyID and RoleID are set for refresh tokens, where
+// all tokens rotated from the same login share the family. Used is set once refresh token is rotated.
 type LoginToken struct {
 	Token     string    `json:"token"`
 	TokenType string    `json:"tokenType"`
+	SessionID string    `json:"sessionID"`
+	FamilyID  string    `json:"familyID"`
 	ID        int64     `json:"id"`
+	RoleID    int64     `json:"roleID"`
 	UserID    uuid.UUID `json:"userID"`
 	Expired   bool      `json:"expired"`
+	Used      bool      `json:"used"`
 }
 
-// PasswordToken contains innfo about password token.
+// PasswordToken contains innfo about password token. Invitation tokens activate the user once password is set.
 type PasswordToken struct {
-	Token     string
-	UserID    uuid.UUID
-	ExpiresAt int64
+	Token      string
+	UserID     uuid.UUID
+	ExpiresAt  int64
+	Invitation bool
+}
+
+// PersonalAccessToken is created by user for scripts and CI jobs. It acts with the role user was authorized
+// with when creating it, limited to the permissions listed in Scopes.
+type PersonalAccessToken struct {
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	Role       string     `json:"role"`
+	Scopes     []string   `json:"scopes"`
+	ID         int64      `json:"id"`
+	RoleID     int64      `json:"roleID"`
+	UserID     uuid.UUID  `json:"-"`
 }
@@ -7,23 +7,34 @@ import (
 )
 
 const (
-	UserNotFound   = "user not found"
-	UserDuplicated = "duplicate user"
+	UserNotFound                   = "user not found"
+	UserDuplicated                 = "duplicate user"
+	EmailVerificationTokenNotFound = "email verification token not found"
 )
 
 type UserRepository interface {
 	GetUserByID(id uuid.UUID) (*User, error)
 	GetUserByEmail(email string) (*User, error)
 	GetUserByToken(token, tokenType string) (*User, error)
-	ActivateUser(userID uuid.UUID) error
+	SetUserActive(userID uuid.UUID, active bool, reason string, changedBy uuid.UUID) (bool, error)
+	HasUserActivationLog(userID uuid.UUID) (bool, error)
 	GetUsers(filter *UserFilter) ([]*User, error)
 	UpdateUser(user *User) (*User, error)
+	CreateUser(user *User) (*User, error)
+	CreateEmailVerificationToken(token *EmailVerificationToken) error
+	VerifyEmail(tokenHash string) (*User, error)
+	UpdateUserDetails(user *User) error
+	SetUserRoles(userID uuid.UUID, roleIDs []int64) error
+	UnlockUser(userID uuid.UUID) error
+	DeleteUser(userID uuid.UUID) error
+	RestoreUser(userID uuid.UUID) error
 }
 
 // User model.
 type User struct {
 	CreatedAt         time.Time  `json:"createdAt,omitempty"`
 	LoginBlockedUntil *time.Time `json:"loginBlockedUntil,omitempty"`
+	DeletedAt         *time.Time `json:"deletedAt,omitempty"`
 	Name              string     `json:"name,omitempty"`
 	Email             string     `json:"email,omitempty"`
 	Phone             string     `json:"phone,omitempty"`
@@ -39,14 +50,20 @@ type User struct {
 	Expired           bool       `json:"expired,omitempty"`
 }
 
-func (u *User) VerifyIfUserIsSuspended(maxLoginFailures int) bool {
-	return u.LoginBlockedUntil != nil && u.LoginBlockedUntil.After(time.Now()) &&
-		u.FailedLoginCount >= maxLoginFailures
+// EmailVerificationToken is sent to the email address which needs to be verified. Email is set to
+// the new address when user changes their email, and is applied to the user once it is verified.
+type EmailVerificationToken struct {
+	ExpiresAt time.Time
+	TokenHash string
+	Email     string
+	UserID    uuid.UUID
 }
 
 type UserFilter struct {
 	Active *bool
 	Search *string
+	// Deleted lists deleted users instead of the ones which are not deleted
+	Deleted *bool
 }
 
 // UsersKeyToColumnMap - a map of sorting keys matching their DB values.
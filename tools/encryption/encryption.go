@@ -11,7 +11,8 @@ func IsValid(hashedPassword, password string) error {
 	return err
 }
 
-// Encrypt - Generate hashed password out of clear text password.
+// Encrypt - Generate hashed password out of clear text password. Minimal cost is only suitable for
+// random secrets such as recovery codes, user passwords are hashed with the configured Hasher.
 func Encrypt(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
 	if err != nil {
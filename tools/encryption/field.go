@@ -0,0 +1,259 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// encryptedPrefix marks encrypted values, values without it are stored in plain text
+	encryptedPrefix = "enc:"
+	// keyIDPrefix is followed by id of the key the value was encrypted with
+	keyIDPrefix = encryptedPrefix + "v2:"
+	// legacyPrefix marks values encrypted before the key id was stored with the value
+	legacyPrefix  = encryptedPrefix + "v1:"
+	keyIDLength   = 4
+	dataKeyLength = 32
+	// MinFieldKeyLength is the minimal length of the secret encryption keys are derived from
+	MinFieldKeyLength = 32
+)
+
+var (
+	ErrFieldKeyTooShort  = errors.New("field encryption key is too short")
+	ErrInvalidCiphertext = errors.New("encrypted value is not in a supported format")
+	ErrUnknownFieldKey   = errors.New("value is encrypted with unknown key")
+)
+
+// FieldEncryptor encrypts values of single database columns with AES-256-GCM using envelope encryption.
+// Every value is encrypted with its own random data key, which is stored along the value encrypted
+// with the key derived from the secret. Id of the key is stored with the value as well, so the secret
+// can be rotated while values encrypted with previous secrets can still be decrypted. Blind index
+// allows lookups of encrypted values by equality and is always computed with the current secret.
+type FieldEncryptor struct {
+	keyID string
+	// keyEncryptionKeys contains keys derived from the current and previous secrets by their id
+	keyEncryptionKeys map[string][]byte
+	// keyIDs are ordered from the current key, values without key id are decrypted with the first that fits
+	keyIDs        []string
+	blindIndexKey []byte
+}
+
+// NewFieldEncryptor derives the key encryption key and the blind index key from the secret. Previous
+// secrets are only used to decrypt values encrypted before the secret was rotated.
+func NewFieldEncryptor(secret string, previousSecrets ...string) (*FieldEncryptor, error) {
+	encryptor := &FieldEncryptor{
+		keyEncryptionKeys: make(map[string][]byte),
+	}
+
+	for _, keySecret := range append([]string{secret}, previousSecrets...) {
+		if len(keySecret) < MinFieldKeyLength {
+			return nil, ErrFieldKeyTooShort
+		}
+
+		keyEncryptionKey, err := deriveKey(keySecret, "field-encryption")
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := deriveKey(keySecret, "key-id")
+		if err != nil {
+			return nil, err
+		}
+
+		keyID := hex.EncodeToString(id[:keyIDLength])
+		if _, ok := encryptor.keyEncryptionKeys[keyID]; ok {
+			continue
+		}
+
+		encryptor.keyEncryptionKeys[keyID] = keyEncryptionKey
+		encryptor.keyIDs = append(encryptor.keyIDs, keyID)
+	}
+
+	blindIndexKey, err := deriveKey(secret, "blind-index")
+	if err != nil {
+		return nil, err
+	}
+
+	encryptor.keyID = encryptor.keyIDs[0]
+	encryptor.blindIndexKey = blindIndexKey
+
+	return encryptor, nil
+}
+
+// Encrypt encrypts the value with the current key. Empty value is kept empty, so optional columns stay optional.
+func (e *FieldEncryptor) Encrypt(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, dataKeyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := seal(e.keyEncryptionKeys[e.keyID], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(dataKey, []byte(value))
+	if err != nil {
+		return "", err
+	}
+
+	return e.KeyPrefix() + base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts the value with the key it was encrypted with. Values stored before encryption was
+// enabled are returned as they are.
+func (e *FieldEncryptor) Decrypt(value string) (string, error) {
+	if encoded, ok := strings.CutPrefix(value, legacyPrefix); ok {
+		return e.decryptLegacy(encoded)
+	}
+
+	encoded, ok := strings.CutPrefix(value, keyIDPrefix)
+	if !ok {
+		if IsEncrypted(value) {
+			return "", ErrInvalidCiphertext
+		}
+
+		return value, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(encoded, ":")
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+
+	keyEncryptionKey, ok := e.keyEncryptionKeys[keyID]
+	if !ok {
+		return "", ErrUnknownFieldKey
+	}
+
+	return decryptEnvelope(keyEncryptionKey, encoded)
+}
+
+// decryptLegacy decrypts the value stored without key id with the first key it was encrypted with.
+func (e *FieldEncryptor) decryptLegacy(encoded string) (string, error) {
+	var err error
+
+	for _, keyID := range e.keyIDs {
+		var plaintext string
+
+		if plaintext, err = decryptEnvelope(e.keyEncryptionKeys[keyID], encoded); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return "", err
+}
+
+// KeyPrefix returns the prefix of values encrypted with the current key. Values without it are
+// encrypted again when the secret is rotated.
+func (e *FieldEncryptor) KeyPrefix() string {
+	return keyIDPrefix + e.keyID + ":"
+}
+
+// IsEncrypted checks if the value was encrypted by the FieldEncryptor.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// BlindIndex returns keyed hash of the value, which is stored next to the encrypted value and used
+// to look it up. Value is normalized, so lookups are case insensitive.
+func (e *FieldEncryptor) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, e.blindIndexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// decryptEnvelope decrypts the data key with the key encryption key and the value with the data key.
+func decryptEnvelope(keyEncryptionKey []byte, encoded string) (string, error) {
+	encodedKey, encodedCiphertext, ok := strings.Cut(encoded, ":")
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(encodedCiphertext)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	dataKey, err := open(keyEncryptionKey, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func deriveKey(secret, info string) ([]byte, error) {
+	key := make([]byte, dataKeyLength)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), nil, []byte(info)), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// seal encrypts the plaintext with AES-GCM and prepends the random nonce.
+func seal(key, plaintext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the ciphertext generated by seal.
+func open(key, ciphertext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
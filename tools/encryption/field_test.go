@@ -0,0 +1,140 @@
+package encryption
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testFieldKey = "0123456789abcdef0123456789abcdef"
+
+func TestFieldEncryptor(t *testing.T) {
+	t.Parallel()
+
+	encryptor, err := NewFieldEncryptor(testFieldKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"phone", "+12341245"},
+		{"email", "admin@gmail.com"},
+		{"unicode", "Ćevapi Šnicla"},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			encrypted, err := encryptor.Encrypt(tt.value)
+			require.NoError(t, err)
+			require.True(t, IsEncrypted(encrypted))
+			require.NotContains(t, encrypted, tt.value)
+
+			// Every value is encrypted with its own data key
+			other, err := encryptor.Encrypt(tt.value)
+			require.NoError(t, err)
+			require.NotEqual(t, encrypted, other)
+
+			decrypted, err := encryptor.Decrypt(encrypted)
+			require.NoError(t, err)
+			require.Equal(t, tt.value, decrypted)
+		})
+	}
+}
+
+func TestFieldEncryptorDecrypt(t *testing.T) {
+	t.Parallel()
+
+	encryptor, err := NewFieldEncryptor(testFieldKey)
+	require.NoError(t, err)
+
+	otherEncryptor, err := NewFieldEncryptor(strings.Repeat("x", MinFieldKeyLength))
+	require.NoError(t, err)
+
+	encrypted, err := otherEncryptor.Encrypt("+12341245")
+	require.NoError(t, err)
+
+	// Encrypted with a different key
+	_, err = encryptor.Decrypt(encrypted)
+	require.Error(t, err)
+
+	_, err = encryptor.Decrypt(encryptedPrefix + "invalid")
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	// Plain text values stored before encryption was enabled
+	decrypted, err := encryptor.Decrypt("+12341245")
+	require.NoError(t, err)
+	require.Equal(t, "+12341245", decrypted)
+
+	encrypted, err = encryptor.Encrypt("")
+	require.NoError(t, err)
+	require.Empty(t, encrypted)
+
+	_, err = NewFieldEncryptor("short")
+	require.ErrorIs(t, err, ErrFieldKeyTooShort)
+}
+
+func TestBlindIndex(t *testing.T) {
+	t.Parallel()
+
+	encryptor, err := NewFieldEncryptor(testFieldKey)
+	require.NoError(t, err)
+
+	otherEncryptor, err := NewFieldEncryptor(strings.Repeat("x", MinFieldKeyLength))
+	require.NoError(t, err)
+
+	index := encryptor.BlindIndex("admin@gmail.com")
+
+	require.Len(t, index, 64)
+	require.Equal(t, index, encryptor.BlindIndex(" Admin@Gmail.com "))
+	require.NotEqual(t, index, encryptor.BlindIndex("user@gmail.com"))
+	require.NotEqual(t, index, otherEncryptor.BlindIndex("admin@gmail.com"))
+}
+
+func TestFieldEncryptorKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	previousKey := strings.Repeat("x", MinFieldKeyLength)
+
+	previousEncryptor, err := NewFieldEncryptor(previousKey)
+	require.NoError(t, err)
+
+	encryptor, err := NewFieldEncryptor(testFieldKey, previousKey)
+	require.NoError(t, err)
+
+	require.NotEqual(t, previousEncryptor.KeyPrefix(), encryptor.KeyPrefix())
+
+	encrypted, err := previousEncryptor.Encrypt("+12341245")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encrypted, previousEncryptor.KeyPrefix()))
+
+	// Values encrypted with the previous key are decrypted with the key matching their id
+	decrypted, err := encryptor.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "+12341245", decrypted)
+
+	// New values are encrypted with the current key only
+	encrypted, err = encryptor.Encrypt("+12341245")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encrypted, encryptor.KeyPrefix()))
+
+	_, err = previousEncryptor.Decrypt(encrypted)
+	require.ErrorIs(t, err, ErrUnknownFieldKey)
+
+	// Values stored before key id was added to the value are decrypted with any of the keys
+	legacy, err := previousEncryptor.Encrypt("admin@gmail.com")
+	require.NoError(t, err)
+
+	legacy = legacyPrefix + strings.TrimPrefix(legacy, previousEncryptor.KeyPrefix())
+
+	decrypted, err = encryptor.Decrypt(legacy)
+	require.NoError(t, err)
+	require.Equal(t, "admin@gmail.com", decrypted)
+
+	_, err = NewFieldEncryptor(testFieldKey, "short")
+	require.ErrorIs(t, err, ErrFieldKeyTooShort)
+}
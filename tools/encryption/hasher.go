@@ -0,0 +1,96 @@
+package encryption
+
+import (
+	"errors"
+	"strings"
+)
+
+// Supported password hashing algorithms.
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown password hashing algorithm")
+	ErrMismatch         = errors.New("hashed password does not match the password")
+	ErrInvalidHash      = errors.New("hashed password is not in a supported format")
+)
+
+// Hasher hashes passwords and verifies them against stored hashes.
+type Hasher interface {
+	// Hash generates hashed password out of clear text password.
+	Hash(password string) (string, error)
+	// Verify validates if the password matches the hashed password.
+	Verify(hashedPassword, password string) error
+	// NeedsRehash checks if the hashed password was generated with a different algorithm or parameters.
+	NeedsRehash(hashedPassword string) bool
+}
+
+// HasherOptions configure the password hasher.
+type HasherOptions struct {
+	// Algorithm used for new hashes, hashes of all supported algorithms are verified
+	Algorithm  string
+	BcryptCost int
+	Argon2id   Argon2idParams
+}
+
+// NewHasher creates the hasher which hashes passwords with the configured algorithm and verifies hashes
+// of any supported algorithm, so stored hashes can be upgraded when users log in.
+func NewHasher(options HasherOptions) (Hasher, error) {
+	bcryptHasher := NewBcryptHasher(options.BcryptCost)
+	argon2idHasher := NewArgon2idHasher(options.Argon2id)
+
+	hasher := &multiHasher{bcrypt: bcryptHasher, argon2id: argon2idHasher}
+
+	switch options.Algorithm {
+	case AlgorithmBcrypt:
+		hasher.preferred = bcryptHasher
+	case AlgorithmArgon2id:
+		hasher.preferred = argon2idHasher
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+
+	return hasher, nil
+}
+
+type multiHasher struct {
+	preferred Hasher
+	bcrypt    *BcryptHasher
+	argon2id  *Argon2idHasher
+}
+
+func (h *multiHasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+func (h *multiHasher) Verify(hashedPassword, password string) error {
+	hasher := h.hasherFor(hashedPassword)
+	if hasher == nil {
+		return ErrInvalidHash
+	}
+
+	return hasher.Verify(hashedPassword, password)
+}
+
+func (h *multiHasher) NeedsRehash(hashedPassword string) bool {
+	hasher := h.hasherFor(hashedPassword)
+	if hasher != h.preferred {
+		return true
+	}
+
+	return hasher.NeedsRehash(hashedPassword)
+}
+
+// hasherFor returns the hasher of the algorithm hashed password was generated with.
+func (h *multiHasher) hasherFor(hashedPassword string) Hasher {
+	switch {
+	case strings.HasPrefix(hashedPassword, argon2idPrefix):
+		return h.argon2id
+	case isBcryptHash(hashedPassword):
+		return h.bcrypt
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package encryption
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Low cost parameters keep the tests fast
+var testArgon2idParams = Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestHasher(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		algorithm string
+		prefix    string
+	}{
+		{"bcrypt", AlgorithmBcrypt, "$2a$"},
+		{"argon2id", AlgorithmArgon2id, "$argon2id$v=19$m=1024,t=1,p=1$"},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hasher, err := NewHasher(HasherOptions{
+				Algorithm:  tt.algorithm,
+				BcryptCost: bcrypt.MinCost,
+				Argon2id:   testArgon2idParams,
+			})
+			require.NoError(t, err)
+
+			hashedPassword, err := hasher.Hash("MyPassword123")
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(hashedPassword, tt.prefix), hashedPassword)
+
+			require.NoError(t, hasher.Verify(hashedPassword, "MyPassword123"))
+			require.ErrorIs(t, hasher.Verify(hashedPassword, "MyPassword124"), ErrMismatch)
+			require.False(t, hasher.NeedsRehash(hashedPassword))
+		})
+	}
+}
+
+func TestHasherNeedsRehash(t *testing.T) {
+	t.Parallel()
+
+	bcryptHash, err := NewBcryptHasher(bcrypt.MinCost).Hash("MyPassword123")
+	require.NoError(t, err)
+
+	argon2idHash, err := NewArgon2idHasher(testArgon2idParams).Hash("MyPassword123")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		options        HasherOptions
+		hashedPassword string
+		expected       bool
+	}{
+		{
+			name:           "bcrypt hash with outdated cost",
+			options:        HasherOptions{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1},
+			hashedPassword: bcryptHash,
+			expected:       true,
+		},
+		{
+			name:           "bcrypt hash when argon2id is configured",
+			options:        HasherOptions{Algorithm: AlgorithmArgon2id, BcryptCost: bcrypt.MinCost, Argon2id: testArgon2idParams},
+			hashedPassword: bcryptHash,
+			expected:       true,
+		},
+		{
+			name:           "argon2id hash with outdated parameters",
+			options:        HasherOptions{Algorithm: AlgorithmArgon2id, Argon2id: Argon2idParams{Memory: 2048, Iterations: 1, Parallelism: 1}},
+			hashedPassword: argon2idHash,
+			expected:       true,
+		},
+		{
+			name:           "argon2id hash with current parameters",
+			options:        HasherOptions{Algorithm: AlgorithmArgon2id, Argon2id: testArgon2idParams},
+			hashedPassword: argon2idHash,
+			expected:       false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hasher, err := NewHasher(tt.options)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expected, hasher.NeedsRehash(tt.hashedPassword))
+			// Hashes of all supported algorithms are verified regardless of the configured one
+			require.NoError(t, hasher.Verify(tt.hashedPassword, "MyPassword123"))
+		})
+	}
+}
+
+func TestHasherInvalidHash(t *testing.T) {
+	t.Parallel()
+
+	hasher, err := NewHasher(HasherOptions{Algorithm: AlgorithmArgon2id, Argon2id: testArgon2idParams})
+	require.NoError(t, err)
+
+	for _, hashedPassword := range []string{
+		"",
+		"plain",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+	} {
+		require.ErrorIs(t, hasher.Verify(hashedPassword, "MyPassword123"), ErrInvalidHash, hashedPassword)
+	}
+
+	_, err = NewHasher(HasherOptions{Algorithm: "md5"})
+	require.ErrorIs(t, err, ErrUnknownAlgorithm)
+}
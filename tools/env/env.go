@@ -110,3 +110,17 @@ func GetDateTime(e EnvironmentVariable, fallback time.Duration) time.Duration {
 
 	return dateDuration
 }
+
+func GetDurationOr(e EnvironmentVariable, fallback time.Duration) time.Duration {
+	stringValue := Get(e)
+	if stringValue == "" {
+		return fallback
+	}
+
+	dateDuration, err := time.ParseDuration(stringValue)
+	if err != nil {
+		logger.Fatal().Msgf("variable `%s` cannot be parsed to DURATION", e.String())
+	}
+
+	return dateDuration
+}
@@ -11,9 +11,66 @@ const (
 	MFARefreshTokenExpiration   EnvironmentVariable = "MFA_REFRESH_TOKEN_EXPIRATION"
 	MFAAccessTokenExpiration    EnvironmentVariable = "MFA_ACCESS_TOKEN_EXPIRATION"
 
+	// MFA ENV VARIABLES.
+	MFAIssuer             EnvironmentVariable = "MFA_ISSUER"
+	MFARecoveryCodesCount EnvironmentVariable = "MFA_RECOVERY_CODES_COUNT"
+	MFAMaxAttempts        EnvironmentVariable = "MFA_MAX_ATTEMPTS"
+
+	// JWT ENV VARIABLES.
+	JWTSigningAlgorithm    EnvironmentVariable = "JWT_SIGNING_ALGORITHM"
+	JWTKeysDirectory       EnvironmentVariable = "JWT_KEYS_DIRECTORY"
+	JWTKeyRotationInterval EnvironmentVariable = "JWT_KEY_ROTATION_INTERVAL"
+
+	// OAUTH ENV VARIABLES.
+	OAuthIssuer                      EnvironmentVariable = "OAUTH_ISSUER"
+	OAuthAuthorizationPageURL        EnvironmentVariable = "OAUTH_AUTHORIZATION_PAGE_URL"
+	OAuthAuthorizationCodeExpiration EnvironmentVariable = "OAUTH_AUTHORIZATION_CODE_EXPIRATION"
+	OAuthAccessTokenExpiration       EnvironmentVariable = "OAUTH_ACCESS_TOKEN_EXPIRATION"
+
 	// ACCOUNT ENV VARIABLES.
-	MaxLoginFailures EnvironmentVariable = "MAX_LOGIN_FAILURES"
-	BanDurationTime  EnvironmentVariable = "BAN_DURATION_TIME"
+	InvitationExpiration EnvironmentVariable = "INVITATION_TOKEN_EXPIRATION"
+	RegistrationEnabled  EnvironmentVariable = "REGISTRATION_ENABLED"
+	StrictResponses      EnvironmentVariable = "STRICT_AUTH_RESPONSES"
+
+	// PERSONAL ACCESS TOKEN ENV VARIABLES.
+	PersonalAccessTokenMaxLifetime EnvironmentVariable = "PERSONAL_ACCESS_TOKEN_MAX_LIFETIME"
+
+	// LOGIN LOCKOUT ENV VARIABLES.
+	LockoutWindow             EnvironmentVariable = "LOGIN_LOCKOUT_WINDOW"
+	LockoutAccountThreshold   EnvironmentVariable = "LOGIN_LOCKOUT_ACCOUNT_THRESHOLD"
+	LockoutIPThreshold        EnvironmentVariable = "LOGIN_LOCKOUT_IP_THRESHOLD"
+	LockoutAccountIPThreshold EnvironmentVariable = "LOGIN_LOCKOUT_ACCOUNT_IP_THRESHOLD"
+	LockoutBaseDelay          EnvironmentVariable = "LOGIN_LOCKOUT_BASE_DELAY"
+	LockoutMaxDelay           EnvironmentVariable = "LOGIN_LOCKOUT_MAX_DELAY"
+	LockoutAccountMaxDelay    EnvironmentVariable = "LOGIN_LOCKOUT_ACCOUNT_MAX_DELAY"
+
+	// EMAIL VERIFICATION ENV VARIABLES.
+	EmailVerificationPolicy         EnvironmentVariable = "EMAIL_VERIFICATION_POLICY"
+	EmailVerificationGracePeriod    EnvironmentVariable = "EMAIL_VERIFICATION_GRACE_PERIOD"
+	EmailVerificationExpiration     EnvironmentVariable = "EMAIL_VERIFICATION_TOKEN_EXPIRATION"
+	EmailVerificationResendInterval EnvironmentVariable = "EMAIL_VERIFICATION_RESEND_INTERVAL"
+
+	// RATE LIMIT ENV VARIABLES.
+	RateLimitEnabled        EnvironmentVariable = "RATE_LIMIT_ENABLED"
+	RateLimitWindow         EnvironmentVariable = "RATE_LIMIT_WINDOW"
+	RateLimitIPLimit        EnvironmentVariable = "RATE_LIMIT_IP_LIMIT"
+	RateLimitEmailLimit     EnvironmentVariable = "RATE_LIMIT_EMAIL_LIMIT"
+	RateLimitTrustedProxies EnvironmentVariable = "RATE_LIMIT_TRUSTED_PROXIES"
+
+	// PASSWORD POLICY ENV VARIABLES.
+	PasswordMinLength         EnvironmentVariable = "PASSWORD_MIN_LENGTH"
+	PasswordMaxLength         EnvironmentVariable = "PASSWORD_MAX_LENGTH"
+	PasswordRequireUpper      EnvironmentVariable = "PASSWORD_REQUIRE_UPPER"
+	PasswordRequireLower      EnvironmentVariable = "PASSWORD_REQUIRE_LOWER"
+	PasswordRequireDigit      EnvironmentVariable = "PASSWORD_REQUIRE_DIGIT"
+	PasswordRequireSymbol     EnvironmentVariable = "PASSWORD_REQUIRE_SYMBOL"
+	PasswordBreachedListPath  EnvironmentVariable = "PASSWORD_BREACHED_LIST_PATH"
+	PasswordHistorySize       EnvironmentVariable = "PASSWORD_HISTORY_SIZE"
+	PasswordHashAlgorithm     EnvironmentVariable = "PASSWORD_HASH_ALGORITHM"
+	PasswordBcryptCost        EnvironmentVariable = "PASSWORD_BCRYPT_COST"
+	PasswordArgon2Memory      EnvironmentVariable = "PASSWORD_ARGON2_MEMORY"
+	PasswordArgon2Iterations  EnvironmentVariable = "PASSWORD_ARGON2_ITERATIONS"
+	PasswordArgon2Parallelism EnvironmentVariable = "PASSWORD_ARGON2_PARALLELISM"
 
 	// DATABASE ENV VARIABLES.
 	DatabaseUsername         EnvironmentVariable = "DATABASE_USERNAME"
@@ -32,11 +89,18 @@ const (
 	RedisTokenTTL  EnvironmentVariable = "REDIS_TOKEN_TTL"
 
 	// Email ENV VARIABLES.
-	APIKeyPublic             EnvironmentVariable = "API_KEY_PUBLIC"
-	APIKeyPrivate            EnvironmentVariable = "API_KEY_PRIVATE"
-	ForgotPasswordTemplateID EnvironmentVariable = "FORGOT_PASSWORD_TEMPLATE_ID"
-	SenderEmail              EnvironmentVariable = "SENDER_EMAIL"
+	APIKeyPublic                 EnvironmentVariable = "API_KEY_PUBLIC"
+	APIKeyPrivate                EnvironmentVariable = "API_KEY_PRIVATE"
+	ForgotPasswordTemplateID     EnvironmentVariable = "FORGOT_PASSWORD_TEMPLATE_ID"
+	InvitationTemplateID         EnvironmentVariable = "INVITATION_TEMPLATE_ID"
+	VerificationTemplateID       EnvironmentVariable = "VERIFICATION_TEMPLATE_ID"
+	AccountActivatedTemplateID   EnvironmentVariable = "ACCOUNT_ACTIVATED_TEMPLATE_ID"
+	AccountDeactivatedTemplateID EnvironmentVariable = "ACCOUNT_DEACTIVATED_TEMPLATE_ID"
+	AccountNotActiveTemplateID   EnvironmentVariable = "ACCOUNT_NOT_ACTIVE_TEMPLATE_ID"
+	LoginDelayedTemplateID       EnvironmentVariable = "LOGIN_DELAYED_TEMPLATE_ID"
+	SenderEmail                  EnvironmentVariable = "SENDER_EMAIL"
 
 	// ENCRYPTION ENV VARIABLES.
-	EncryptionProviderHashKey EnvironmentVariable = "ENCRYPTION_PROVIDER_HASH_KEY"
+	EncryptionProviderHashKey          EnvironmentVariable = "ENCRYPTION_PROVIDER_HASH_KEY"
+	EncryptionProviderPreviousHashKeys EnvironmentVariable = "ENCRYPTION_PROVIDER_PREVIOUS_HASH_KEYS"
 )
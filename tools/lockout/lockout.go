@@ -0,0 +1,71 @@
+package lockout
+
+import "time"
+
+// maxShift keeps the exponential delay from overflowing before it is capped.
+const maxShift = 32
+
+// Options configure the lockout policy. Failed logins are counted per account, per IP address and per
+// account from the same IP address. Once the counter reaches its threshold, each next login has to
+// wait twice as long since the latest failure, starting with BaseDelay. Threshold of 0 disables the counter.
+type Options struct {
+	AccountThreshold   int
+	IPThreshold        int
+	AccountIPThreshold int
+	BaseDelay          time.Duration
+	MaxDelay           time.Duration
+	// AccountMaxDelay caps the delay of the account counter, which anyone can increase by guessing
+	// the password of the victim, so the victim is only slowed down instead of locked out
+	AccountMaxDelay time.Duration
+}
+
+// Counter contains number of failed logins and time of the latest one.
+type Counter struct {
+	LastFailedAt time.Time
+	Count        int64
+}
+
+// Failures contains failed logins counted for the account, the IP address and the account from the
+// IP address. Account counters are empty when login is attempted with unknown email.
+type Failures struct {
+	Account   Counter
+	IP        Counter
+	AccountIP Counter
+}
+
+// Policy delays logins after too many failures.
+type Policy struct {
+	options Options
+}
+
+// NewPolicy creates the lockout policy.
+func NewPolicy(options Options) *Policy {
+	return &Policy{options: options}
+}
+
+// Backoff returns how long the next login has to wait, 0 if login is allowed.
+func (p *Policy) Backoff(failures *Failures, now time.Time) time.Duration {
+	return max(
+		p.AccountBackoff(failures.Account, now),
+		p.backoff(failures.IP, p.options.IPThreshold, p.options.MaxDelay, now),
+		p.backoff(failures.AccountIP, p.options.AccountIPThreshold, p.options.MaxDelay, now),
+	)
+}
+
+// AccountBackoff returns how long the next login to the account has to wait regardless of the IP address.
+func (p *Policy) AccountBackoff(counter Counter, now time.Time) time.Duration {
+	return p.backoff(counter, p.options.AccountThreshold, p.options.AccountMaxDelay, now)
+}
+
+func (p *Policy) backoff(counter Counter, threshold int, maxDelay time.Duration, now time.Time) time.Duration {
+	if threshold <= 0 || counter.Count < int64(threshold) {
+		return 0
+	}
+
+	delay := p.options.BaseDelay << min(counter.Count-int64(threshold), maxShift)
+	if delay > maxDelay || delay <= 0 {
+		delay = maxDelay
+	}
+
+	return max(0, counter.LastFailedAt.Add(delay).Sub(now))
+}
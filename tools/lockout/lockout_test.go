@@ -0,0 +1,120 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := NewPolicy(Options{
+		AccountThreshold:   20,
+		IPThreshold:        50,
+		AccountIPThreshold: 5,
+		BaseDelay:          time.Second,
+		MaxDelay:           15 * time.Minute,
+		AccountMaxDelay:    time.Minute,
+	})
+
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		failures *Failures
+		expected time.Duration
+	}{
+		{
+			name:     "no failures",
+			failures: &Failures{},
+			expected: 0,
+		},
+		{
+			name: "below thresholds",
+			failures: &Failures{
+				Account:   Counter{Count: 19, LastFailedAt: now},
+				IP:        Counter{Count: 49, LastFailedAt: now},
+				AccountIP: Counter{Count: 4, LastFailedAt: now},
+			},
+			expected: 0,
+		},
+		{
+			name: "account from IP reached threshold",
+			failures: &Failures{
+				AccountIP: Counter{Count: 5, LastFailedAt: now},
+			},
+			expected: time.Second,
+		},
+		{
+			name: "delay doubles with each failure",
+			failures: &Failures{
+				AccountIP: Counter{Count: 8, LastFailedAt: now},
+			},
+			expected: 8 * time.Second,
+		},
+		{
+			name: "time since the latest failure is taken into account",
+			failures: &Failures{
+				AccountIP: Counter{Count: 8, LastFailedAt: now.Add(-3 * time.Second)},
+			},
+			expected: 5 * time.Second,
+		},
+		{
+			name: "delay has passed",
+			failures: &Failures{
+				AccountIP: Counter{Count: 8, LastFailedAt: now.Add(-time.Minute)},
+			},
+			expected: 0,
+		},
+		{
+			name: "delay is capped",
+			failures: &Failures{
+				IP: Counter{Count: 1000, LastFailedAt: now},
+			},
+			expected: 15 * time.Minute,
+		},
+		{
+			name: "account delay is capped lower",
+			failures: &Failures{
+				Account: Counter{Count: 1000, LastFailedAt: now},
+			},
+			expected: time.Minute,
+		},
+		{
+			name: "longest delay wins",
+			failures: &Failures{
+				Account:   Counter{Count: 22, LastFailedAt: now},
+				AccountIP: Counter{Count: 6, LastFailedAt: now},
+			},
+			expected: 4 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, policy.Backoff(tt.failures, now))
+		})
+	}
+}
+
+func TestBackoffDisabledCounter(t *testing.T) {
+	t.Parallel()
+
+	policy := NewPolicy(Options{
+		AccountIPThreshold: 5,
+		BaseDelay:          time.Second,
+		MaxDelay:           time.Minute,
+	})
+
+	now := time.Now()
+
+	failures := &Failures{
+		Account: Counter{Count: 1000, LastFailedAt: now},
+		IP:      Counter{Count: 1000, LastFailedAt: now},
+	}
+
+	require.Zero(t, policy.Backoff(failures, now))
+}
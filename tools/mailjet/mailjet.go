@@ -30,6 +30,65 @@ func (c *Client) SendEmailResetPassword(templateID int, name, fromEmail, toEmail
 		"mj_user_name":           name,
 	}
 
+	c.sendTemplate(templateID, fromEmail, toEmail, vars)
+}
+
+// SendEmailInvitation will send email to invited user to set his password and activate the account.
+func (c *Client) SendEmailInvitation(templateID int, name, fromEmail, toEmail, token string) {
+	// Define the variables for the template
+	vars := map[string]interface{}{
+		"mj_set_password_link": "https://example.com/set-password/" + token,
+		"mj_user_name":         name,
+	}
+
+	c.sendTemplate(templateID, fromEmail, toEmail, vars)
+}
+
+// SendEmailVerification will send email with the link to verify the email address.
+func (c *Client) SendEmailVerification(templateID int, name, fromEmail, toEmail, token string) {
+	// Define the variables for the template
+	vars := map[string]interface{}{
+		"mj_verify_email_link": "https://example.com/verify-email/" + token,
+		"mj_user_name":         name,
+	}
+
+	c.sendTemplate(templateID, fromEmail, toEmail, vars)
+}
+
+// SendEmailAccountStatusChanged will send email to user whose account was activated or deactivated by admin.
+func (c *Client) SendEmailAccountStatusChanged(templateID int, name, fromEmail, toEmail, reason string) {
+	// Define the variables for the template
+	vars := map[string]interface{}{
+		"mj_reason":    reason,
+		"mj_user_name": name,
+	}
+
+	c.sendTemplate(templateID, fromEmail, toEmail, vars)
+}
+
+// SendEmailAccountNotActive will send email to user who tried to log in or reset password of inactive account.
+func (c *Client) SendEmailAccountNotActive(templateID int, name, fromEmail, toEmail string) {
+	// Define the variables for the template
+	vars := map[string]interface{}{
+		"mj_user_name": name,
+	}
+
+	c.sendTemplate(templateID, fromEmail, toEmail, vars)
+}
+
+// SendEmailLoginDelayed will send email to user whose login is delayed because of too many failed logins.
+func (c *Client) SendEmailLoginDelayed(templateID int, name, fromEmail, toEmail, until string) {
+	// Define the variables for the template
+	vars := map[string]interface{}{
+		"mj_delayed_until": until,
+		"mj_user_name":     name,
+	}
+
+	c.sendTemplate(templateID, fromEmail, toEmail, vars)
+}
+
+// sendTemplate sends a transactional email based on the template with given variables.
+func (c *Client) sendTemplate(templateID int, fromEmail, toEmail string, vars map[string]interface{}) {
 	messagesInfo := []mailjet.InfoMessagesV31{
 		{
 			From: &mailjet.RecipientV31{
@@ -48,6 +48,114 @@ const (
 	ErrorEmailDoesNotExists = 1020
 	// ErrorUserSuspended used when user is suspended due to multiple failed login attempts.
 	ErrorUserSuspended = 1021
+	// ErrorMissingName - error when name is not sent or when it all empty spaces.
+	ErrorMissingName = 1022
+	// ErrorEmailAlreadyExists is used when account with the same email already exists.
+	ErrorEmailAlreadyExists = 1023
+	// ErrorMissingTitle - error when title is not sent or when it all empty spaces.
+	ErrorMissingTitle = 1024
+	// ErrorInvalidTaskStatus is used when task status is not one of the supported statuses.
+	ErrorInvalidTaskStatus = 1025
+	// ErrorInvalidTaskStatusTransition is used when task can not be moved from current to requested status.
+	ErrorInvalidTaskStatusTransition = 1026
+	// ErrorTaskNotFound is used when task does not exist or user has no access to it.
+	ErrorTaskNotFound = 1027
+	// ErrorInvalidURLParameters - error when url params are not in correct format.
+	ErrorInvalidURLParameters = 1028
+	// ErrorMissingMFACode is used when user with enabled MFA does not send code or recovery code.
+	ErrorMissingMFACode = 1029
+	// ErrorInvalidMFACode is used when MFA code or recovery code is not valid or was already used.
+	ErrorInvalidMFACode = 1030
+	// ErrorMFAAlreadyEnabled is used when user tries to enroll MFA while it is already enabled.
+	ErrorMFAAlreadyEnabled = 1031
+	// ErrorMFANotEnrolled is used when MFA operation requires enrollment which does not exist.
+	ErrorMFANotEnrolled = 1032
+	// ErrorMissingRedirectURI is used when OAuth client using authorization code has no redirect URI.
+	ErrorMissingRedirectURI = 1033
+	// ErrorInvalidRedirectURI is used when redirect URI is not absolute URL or is not registered for the client.
+	ErrorInvalidRedirectURI = 1034
+	// ErrorInvalidGrantType is used when grant type is not supported or not allowed for the client.
+	ErrorInvalidGrantType = 1035
+	// ErrorOAuthClientNotFound is used when OAuth client does not exist.
+	ErrorOAuthClientNotFound = 1036
+	// ErrorSessionNotFound is used when user session does not exist or has expired.
+	ErrorSessionNotFound = 1037
+	// ErrorTokenReused is used when already rotated refresh token is presented again.
+	ErrorTokenReused = 1038
+	// ErrorInvalidPermission is used when permission granted to the role does not exist.
+	ErrorInvalidPermission = 1039
+	// ErrorRoleNotFound is used when role does not exist.
+	ErrorRoleNotFound = 1040
+	// ErrorRoleAlreadyExists is used when role with the same name already exists.
+	ErrorRoleAlreadyExists = 1041
+	// ErrorRoleInUse is used when role which is assigned to users is deleted.
+	ErrorRoleInUse = 1042
+	// ErrorLastUserRole is used when the only role of the user is removed.
+	ErrorLastUserRole = 1043
+	// ErrorUnableToChangeOwnRoles is used when user tries to assign or remove their own roles.
+	ErrorUnableToChangeOwnRoles = 1044
+	// ErrorMissingRoleID is used when role id is not provided.
+	ErrorMissingRoleID = 1045
+	// ErrorRoleNotAssigned is used when user selects a role which is not assigned to them.
+	ErrorRoleNotAssigned = 1046
+	// ErrorEmailNotVerified is used when user with unverified email address is not allowed to log in.
+	ErrorEmailNotVerified = 1047
+	// ErrorEmailAlreadyVerified is used when verification is requested for already verified email address.
+	ErrorEmailAlreadyVerified = 1048
+	// ErrorTooManyRequests is used when the same action is requested again too soon.
+	ErrorTooManyRequests = 1049
+	// ErrorTermsNotAccepted is used when user did not accept the current version of terms of service.
+	ErrorTermsNotAccepted = 1050
+	// ErrorTermsNotFound is used when no version of terms of service is published.
+	ErrorTermsNotFound = 1051
+	// ErrorTermsVersionMismatch is used when user accepts a version of terms of service which is not the current one.
+	ErrorTermsVersionMismatch = 1052
+	// ErrorMissingVersion is used when terms version is not provided.
+	ErrorMissingVersion = 1053
+	// ErrorTermsVersionAlreadyExists is used when terms version with the same name already exists.
+	ErrorTermsVersionAlreadyExists = 1054
+	// ErrorMissingContent is used when terms content is not provided.
+	ErrorMissingContent = 1055
+	// ErrorPasswordTooShort is used when password is shorter than required by password policy.
+	ErrorPasswordTooShort = 1056
+	// ErrorPasswordTooLong is used when password is longer than allowed by password policy.
+	ErrorPasswordTooLong = 1057
+	// ErrorPasswordMissingUppercase is used when password does not contain an uppercase letter.
+	ErrorPasswordMissingUppercase = 1058
+	// ErrorPasswordMissingLowercase is used when password does not contain a lowercase letter.
+	ErrorPasswordMissingLowercase = 1059
+	// ErrorPasswordMissingDigit is used when password does not contain a digit.
+	ErrorPasswordMissingDigit = 1060
+	// ErrorPasswordMissingSymbol is used when password does not contain a symbol.
+	ErrorPasswordMissingSymbol = 1061
+	// ErrorPasswordEqualsEmail is used when password is the same as user email.
+	ErrorPasswordEqualsEmail = 1062
+	// ErrorPasswordBreached is used when password is found on the list of breached passwords.
+	ErrorPasswordBreached = 1063
+	// ErrorPasswordRecentlyUsed is used when password matches one of the recently used passwords.
+	ErrorPasswordRecentlyUsed = 1064
+	// ErrorInvalidLanguage is used when language is not a two letter code.
+	ErrorInvalidLanguage = 1065
+	// ErrorUserDeleted is used when user is deleted.
+	ErrorUserDeleted = 1066
+	// ErrorUserNotDeleted is used when restoring user which is not deleted.
+	ErrorUserNotDeleted = 1067
+	// ErrorUnableToDeleteOwnAccount is used when admin tries to delete their own account.
+	ErrorUnableToDeleteOwnAccount = 1068
+	// ErrorMissingActive is used when active status is not sent.
+	ErrorMissingActive = 1069
+	// ErrorReasonTooLong is used when reason is longer than 500 characters.
+	ErrorReasonTooLong = 1070
+	// ErrorInvalidExpiration is used when expiration is missing, in the past or too far in the future.
+	ErrorInvalidExpiration = 1071
+	// ErrorPersonalAccessTokenNotFound is used when personal access token is not found.
+	ErrorPersonalAccessTokenNotFound = 1072
+	// ErrorNameTooLong is used when name is longer than 100 characters.
+	ErrorNameTooLong = 1073
+	// ErrorImpersonationNotAllowed is used when admin is not allowed to impersonate the user.
+	ErrorImpersonationNotAllowed = 1074
+	// ErrorNotImpersonating is used when ending impersonation in session which is not impersonated.
+	ErrorNotImpersonating = 1075
 )
 
 // / ****************************************************
@@ -55,28 +163,82 @@ const (
 // / ****************************************************.
 func errToStatusTextMap() map[int]string { //nolint:funlen // ignore
 	statusText := map[int]string{
-		InternalServerError:            "internal server error",
-		EmptyBody:                      "empty request body",
-		IncorrectBodyFormat:            "incorrect body format",
-		InvalidResponseBody:            "invalid response body",
-		ErrorMissingEmail:              "missing parameter email",
-		ErrorMissingPassword:           "missing parameter password",
-		ErrorEmailOrPasswordNotMatch:   "email or password do not match",
-		ErrorEmailNotInCorrectFormat:   "email not in correct format",
-		ErrorIncorrectEmailOrPassword:  "incorrect email or password",
-		ErrorUserNotActive:             "user is not active",
-		ErrorTokenExpiredOrNotValid:    "token expired or not valid",
-		ErrorMissingPhone:              "missing phone paramater",
-		ErrorInvalidQueryURLParameters: "incorrect URL query params format",
-		ErrorMissingUserID:             "missing user id",
-		ErrorUnableToDeactivateAdmin:   "unable to deactivate or activate admin account",
-		ErrorActivateUser:              "unable to activate account",
-		ErrorDeleteToken:               "unable to delete token",
-		ErrorCurrentPasswordMismatch:   "current password mismatch",
-		ErrorGetUser:                   "unable to fetch user",
-		ErrorMissingToken:              "missing or invalid token",
-		ErrorEmailDoesNotExists:        "email does not exists",
-		ErrorUserSuspended:             "user suspended until",
+		InternalServerError:              "internal server error",
+		EmptyBody:                        "empty request body",
+		IncorrectBodyFormat:              "incorrect body format",
+		InvalidResponseBody:              "invalid response body",
+		ErrorMissingEmail:                "missing parameter email",
+		ErrorMissingPassword:             "missing parameter password",
+		ErrorEmailOrPasswordNotMatch:     "email or password do not match",
+		ErrorEmailNotInCorrectFormat:     "email not in correct format",
+		ErrorIncorrectEmailOrPassword:    "incorrect email or password",
+		ErrorUserNotActive:               "user is not active",
+		ErrorTokenExpiredOrNotValid:      "token expired or not valid",
+		ErrorMissingPhone:                "missing phone paramater",
+		ErrorInvalidQueryURLParameters:   "incorrect URL query params format",
+		ErrorMissingUserID:               "missing user id",
+		ErrorUnableToDeactivateAdmin:     "unable to deactivate or activate admin account",
+		ErrorActivateUser:                "unable to activate account",
+		ErrorDeleteToken:                 "unable to delete token",
+		ErrorCurrentPasswordMismatch:     "current password mismatch",
+		ErrorGetUser:                     "unable to fetch user",
+		ErrorMissingToken:                "missing or invalid token",
+		ErrorEmailDoesNotExists:          "email does not exists",
+		ErrorUserSuspended:               "user suspended until",
+		ErrorMissingName:                 "missing parameter name",
+		ErrorEmailAlreadyExists:          "account with this email already exists",
+		ErrorMissingTitle:                "missing parameter title",
+		ErrorInvalidTaskStatus:           "invalid task status",
+		ErrorInvalidTaskStatusTransition: "task can not be moved to requested status",
+		ErrorTaskNotFound:                "task not found",
+		ErrorInvalidURLParameters:        "incorrect URL params format",
+		ErrorMissingMFACode:              "missing MFA code",
+		ErrorInvalidMFACode:              "invalid MFA code",
+		ErrorMFAAlreadyEnabled:           "MFA is already enabled",
+		ErrorMFANotEnrolled:              "MFA is not enrolled",
+		ErrorMissingRedirectURI:          "missing parameter redirect URI",
+		ErrorInvalidRedirectURI:          "invalid redirect URI",
+		ErrorInvalidGrantType:            "invalid grant type",
+		ErrorOAuthClientNotFound:         "OAuth client not found",
+		ErrorSessionNotFound:             "Session not found",
+		ErrorTokenReused:                 "Refresh token has already been used",
+		ErrorInvalidPermission:           "Invalid permission",
+		ErrorRoleNotFound:                "Role not found",
+		ErrorRoleAlreadyExists:           "Role with the same name already exists",
+		ErrorRoleInUse:                   "Role is assigned to users",
+		ErrorLastUserRole:                "User must have at least one role",
+		ErrorUnableToChangeOwnRoles:      "Unable to change own roles",
+		ErrorMissingRoleID:               "Missing role id",
+		ErrorRoleNotAssigned:             "Role is not assigned to the user",
+		ErrorEmailNotVerified:            "Email address is not verified",
+		ErrorEmailAlreadyVerified:        "Email address is already verified",
+		ErrorTooManyRequests:             "Too many requests, try again later",
+		ErrorTermsNotAccepted:            "Current terms of service are not accepted",
+		ErrorTermsNotFound:               "Terms of service not found",
+		ErrorTermsVersionMismatch:        "Terms version is not the current one",
+		ErrorMissingVersion:              "Missing parameter version",
+		ErrorTermsVersionAlreadyExists:   "Terms version already exists",
+		ErrorMissingContent:              "Missing parameter content",
+		ErrorPasswordTooShort:            "Password is too short",
+		ErrorPasswordTooLong:             "Password is too long",
+		ErrorPasswordMissingUppercase:    "Password must contain an uppercase letter",
+		ErrorPasswordMissingLowercase:    "Password must contain a lowercase letter",
+		ErrorPasswordMissingDigit:        "Password must contain a digit",
+		ErrorPasswordMissingSymbol:       "Password must contain a symbol",
+		ErrorPasswordEqualsEmail:         "Password must not be the same as email",
+		ErrorPasswordBreached:            "Password has appeared in a data breach, choose a different one",
+		ErrorPasswordRecentlyUsed:        "Password was used recently, choose a different one",
+		ErrorInvalidLanguage:             "Language must be a two letter code",
+		ErrorUserDeleted:                 "User is deleted",
+		ErrorUserNotDeleted:              "User is not deleted",
+		ErrorUnableToDeleteOwnAccount:    "Unable to delete your own account",
+		ErrorMissingActive:               "missing parameter active",
+		ErrorReasonTooLong:               "Reason must not be longer than 500 characters",
+		ErrorInvalidExpiration:           "Expiration must be in the future and within the allowed lifetime",
+		ErrorPersonalAccessTokenNotFound: "Personal access token not found",
+		ErrorNameTooLong:                 "Name must not be longer than 100 characters",
+		ErrorImpersonationNotAllowed:     "Impersonation of this user is not allowed",
+		ErrorNotImpersonating:            "Session is not impersonating a user",
 	}
 
 	return statusText
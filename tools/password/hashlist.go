@@ -0,0 +1,188 @@
+package password
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // SHA-1 is the format breached password lists are published in
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	sha1HexLength = 40
+	// scanThreshold is the size of the file section below which lines are compared one by one
+	scanThreshold = 4096
+	readChunkSize = 256
+)
+
+// HashList is an offline list of breached password hashes, in the format of the Have I Been Pwned
+// password list ordered by hash ("SHA1:COUNT" per line). The list has hundreds of millions of lines,
+// so it is not loaded into memory. Hashes are looked up with binary search in the file instead.
+type HashList struct {
+	file *os.File
+	size int64
+}
+
+// LoadHashList opens the list of SHA-1 hashes sorted in ascending order. Empty lines and lines starting
+// with # are only allowed at the beginning of the file. Only the first hash is validated, since reading
+// the whole list would take too long.
+func LoadHashList(path string) (*HashList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	list := &HashList{file: file, size: info.Size()}
+
+	if err := list.validate(path); err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Contains checks if the password is on the list.
+func (l *HashList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // see import
+	target := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// Lines starting before lo have lower hash, while the line with the hash, if any, starts before hi.
+	// Both are always at the start of the line.
+	lo, hi := int64(0), l.size
+
+	for hi-lo > scanThreshold {
+		mid := lo + (hi-lo)/2
+
+		start, err := l.nextLineStart(mid)
+		if err != nil {
+			return false, err
+		}
+
+		if start >= hi {
+			hi = mid
+
+			continue
+		}
+
+		line, next, err := l.readLine(start)
+		if err != nil {
+			return false, err
+		}
+
+		switch cmp := strings.Compare(lineHash(line), target); {
+		case cmp == 0:
+			return true, nil
+		case cmp < 0:
+			lo = next
+		default:
+			hi = start
+		}
+	}
+
+	for lo < hi {
+		line, next, err := l.readLine(lo)
+		if err != nil {
+			return false, err
+		}
+
+		if lineHash(line) == target {
+			return true, nil
+		}
+
+		lo = next
+	}
+
+	return false, nil
+}
+
+// validate checks the first hash on the list.
+func (l *HashList) validate(path string) error {
+	for offset, number := int64(0), 1; offset < l.size; number++ {
+		line, next, err := l.readLine(offset)
+		if err != nil {
+			return err
+		}
+
+		offset = next
+
+		entry := strings.TrimSpace(line)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		hash := lineHash(line)
+		if len(hash) != sha1HexLength {
+			return fmt.Errorf("invalid hash on line %d of %s", number, path)
+		}
+
+		if _, err := hex.DecodeString(hash); err != nil {
+			return fmt.Errorf("invalid hash on line %d of %s: %w", number, path, err)
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// nextLineStart returns offset of the first line starting at the offset or after it.
+func (l *HashList) nextLineStart(offset int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+
+	// Line starts at the offset if the previous byte is the end of the line
+	_, next, err := l.readLine(offset - 1)
+
+	return next, err
+}
+
+// readLine reads the line starting at the offset. Returns offset of the next line, which is the size
+// of the file for the last line.
+func (l *HashList) readLine(offset int64) (string, int64, error) {
+	var line []byte
+
+	buf := make([]byte, readChunkSize)
+
+	for position := offset; position < l.size; {
+		n, err := l.file.ReadAt(buf, position)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", 0, err
+		}
+
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			line = append(line, buf[:i]...)
+
+			return string(line), position + int64(i) + 1, nil
+		}
+
+		line = append(line, buf[:n]...)
+		position += int64(n)
+
+		if n == 0 {
+			break
+		}
+	}
+
+	return string(line), l.size, nil
+}
+
+// lineHash returns uppercase hash from the line, occurrence count is not used. Letter case does not change
+// the order of hex encoded hashes, so lists in either case can be searched.
+func lineHash(line string) string {
+	hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+
+	return strings.ToUpper(hash)
+}
@@ -0,0 +1,132 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adinovcina/golang-setup/tools/logger"
+)
+
+// MaxBcryptLength is the number of bytes bcrypt takes into account, the rest of the password is ignored.
+const MaxBcryptLength = 72
+
+var (
+	ErrTooShort      = errors.New("password is too short")
+	ErrTooLong       = errors.New("password is too long")
+	ErrMissingUpper  = errors.New("password must contain an uppercase letter")
+	ErrMissingLower  = errors.New("password must contain a lowercase letter")
+	ErrMissingDigit  = errors.New("password must contain a digit")
+	ErrMissingSymbol = errors.New("password must contain a symbol")
+	ErrEqualsEmail   = errors.New("password must not be equal to email")
+	ErrBreached      = errors.New("password appeared in a data breach")
+)
+
+// Options configure the password policy.
+type Options struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// BreachedListPath is a file with SHA-1 hashes of breached passwords, check is disabled if empty
+	BreachedListPath string
+}
+
+// Policy validates passwords chosen by users.
+type Policy struct {
+	breached *HashList
+	options  Options
+}
+
+// NewPolicy creates the password policy and opens the list of breached passwords. Max length defaults to
+// the number of bytes bcrypt can hash and can not be longer, since the rest of the password would be ignored.
+func NewPolicy(options Options) (*Policy, error) {
+	if options.MaxLength > MaxBcryptLength {
+		return nil, fmt.Errorf("password max length %d is longer than %d bytes bcrypt can hash",
+			options.MaxLength, MaxBcryptLength)
+	}
+
+	if options.MaxLength <= 0 {
+		options.MaxLength = MaxBcryptLength
+	}
+
+	policy := &Policy{options: options}
+
+	if options.BreachedListPath != "" {
+		breached, err := LoadHashList(options.BreachedListPath)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.breached = breached
+	}
+
+	return policy, nil
+}
+
+// Validate checks the password against the policy and returns all violations.
+func (p *Policy) Validate(password, email string) []error {
+	violations := make([]error, 0)
+
+	if utf8.RuneCountInString(password) < p.options.MinLength {
+		violations = append(violations, ErrTooShort)
+	}
+
+	// Limit is in bytes, since that is what bcrypt hashes
+	if len(password) > p.options.MaxLength {
+		violations = append(violations, ErrTooLong)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c) || unicode.IsSpace(c):
+			hasSymbol = true
+		}
+	}
+
+	if p.options.RequireUpper && !hasUpper {
+		violations = append(violations, ErrMissingUpper)
+	}
+
+	if p.options.RequireLower && !hasLower {
+		violations = append(violations, ErrMissingLower)
+	}
+
+	if p.options.RequireDigit && !hasDigit {
+		violations = append(violations, ErrMissingDigit)
+	}
+
+	if p.options.RequireSymbol && !hasSymbol {
+		violations = append(violations, ErrMissingSymbol)
+	}
+
+	if email != "" && strings.EqualFold(strings.TrimSpace(password), strings.TrimSpace(email)) {
+		violations = append(violations, ErrEqualsEmail)
+	}
+
+	if p.breached != nil {
+		// List is only an additional check, so password is accepted if the list can not be read
+		breached, err := p.breached.Contains(password)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to look up password in the list of breached passwords")
+		}
+
+		if breached {
+			violations = append(violations, ErrBreached)
+		}
+	}
+
+	return violations
+}
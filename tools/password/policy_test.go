@@ -0,0 +1,155 @@
+package password
+
+import (
+	"crypto/sha1" //nolint:gosec // SHA-1 is the format breached password lists are published in
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	listPath := filepath.Join(t.TempDir(), "breached.txt")
+
+	// SHA-1 of "Password123"
+	err := os.WriteFile(listPath, []byte("# breached passwords\nB2E98AD6F6EB8508DD6A14CFA704BAD7F05F6FB1:42\n"), 0o600)
+	require.NoError(t, err)
+
+	_, err = NewPolicy(Options{MaxLength: MaxBcryptLength + 1})
+	require.Error(t, err)
+
+	policy, err := NewPolicy(Options{
+		MinLength:        8,
+		MaxLength:        MaxBcryptLength,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		BreachedListPath: listPath,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		email    string
+		expected []error
+	}{
+		{
+			name:     "valid password",
+			password: "Correct7Horse",
+			email:    "user@example.com",
+			expected: []error{},
+		},
+		{
+			name:     "too short",
+			password: "Ab1",
+			expected: []error{ErrTooShort},
+		},
+		{
+			name:     "longer than bcrypt limit",
+			password: "Aa1" + strings.Repeat("x", MaxBcryptLength),
+			expected: []error{ErrTooLong},
+		},
+		{
+			name:     "missing character classes",
+			password: "lowercaseonly",
+			expected: []error{ErrMissingUpper, ErrMissingDigit},
+		},
+		{
+			name:     "equal to email",
+			password: "User1@Example.com",
+			email:    "user1@example.com",
+			expected: []error{ErrEqualsEmail},
+		},
+		{
+			name:     "breached password",
+			password: "Password123",
+			expected: []error{ErrBreached},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, policy.Validate(tt.password, tt.email))
+		})
+	}
+}
+
+func TestLoadHashList(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	validPath := filepath.Join(dir, "valid.txt")
+	err := os.WriteFile(validPath, []byte("b2e98ad6f6eb8508dd6a14cfa704bad7f05f6fb1\n\n"), 0o600)
+	require.NoError(t, err)
+
+	list, err := LoadHashList(validPath)
+	require.NoError(t, err)
+
+	found, err := list.Contains("Password123")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	found, err = list.Contains("password123")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	invalidPath := filepath.Join(dir, "invalid.txt")
+	err = os.WriteFile(invalidPath, []byte("not-a-hash:1\n"), 0o600)
+	require.NoError(t, err)
+
+	_, err = LoadHashList(invalidPath)
+	require.Error(t, err)
+
+	_, err = LoadHashList(filepath.Join(dir, "missing.txt"))
+	require.Error(t, err)
+}
+
+func TestHashListContainsLargeList(t *testing.T) {
+	t.Parallel()
+
+	// List has to be larger than scanThreshold to be searched with binary search
+	hashes := make([]string, 0, 1000)
+	for i := 0; i < cap(hashes); i++ {
+		sum := sha1.Sum([]byte(fmt.Sprintf("password%d", i))) //nolint:gosec // see import
+		hashes = append(hashes, strings.ToUpper(hex.EncodeToString(sum[:])))
+	}
+
+	sort.Strings(hashes)
+
+	var content strings.Builder
+
+	content.WriteString("# breached passwords\n")
+
+	for i, hash := range hashes {
+		fmt.Fprintf(&content, "%s:%d\n", hash, i+1)
+	}
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content.String()), 0o600))
+
+	list, err := LoadHashList(path)
+	require.NoError(t, err)
+
+	for i := 0; i < len(hashes); i++ {
+		found, err := list.Contains(fmt.Sprintf("password%d", i))
+		require.NoError(t, err)
+		require.True(t, found, "password%d", i)
+	}
+
+	for i := len(hashes); i < 2*len(hashes); i++ {
+		found, err := list.Contains(fmt.Sprintf("password%d", i))
+		require.NoError(t, err)
+		require.False(t, found, "password%d", i)
+	}
+}
@@ -0,0 +1,414 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adinovcina/golang-setup/tools/logger"
+	jwt "github.com/golang-jwt/jwt"
+)
+
+const (
+	// AlgorithmHS256 signs tokens with shared secret. Tokens can not be verified by other services.
+	AlgorithmHS256 = "HS256"
+	// AlgorithmRS256 signs tokens with RSA private key.
+	AlgorithmRS256 = "RS256"
+	// AlgorithmEdDSA signs tokens with Ed25519 private key.
+	AlgorithmEdDSA = "EdDSA"
+
+	rsaKeySize       = 2048
+	keyFileExtension = ".pem"
+	// reloadInterval - how often keys directory is checked for keys added by other instances.
+	reloadInterval = time.Minute
+)
+
+// PublishDelay - how long a new key is published before it is used for signing, so verifiers
+// which cache the key set have a chance to fetch it.
+const PublishDelay = 5 * time.Minute
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnexpectedMethod     = errors.New("unexpected signing method")
+	ErrKeyNotFound          = errors.New("signing key not found")
+)
+
+// Options contains configuration of the keyring.
+type Options struct {
+	// Algorithm used to sign new tokens
+	Algorithm string
+	// Secret used for HS256 algorithm
+	Secret string
+	// KeysDirectory contains PEM encoded keys, file name without extension is used as key id
+	KeysDirectory string
+	// RotationInterval - how often a new signing key is generated, rotation is disabled if zero
+	RotationInterval time.Duration
+	// VerificationPeriod - how long replaced key is still used to verify tokens, should match token TTL
+	VerificationPeriod time.Duration
+}
+
+// Key is a single key in the keyring. Keys without private part are only used for verification.
+type Key struct {
+	CreatedAt  time.Time
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+	ID         string
+}
+
+// Keyring signs tokens with the current key and verifies tokens with all active keys.
+type Keyring struct {
+	method     jwt.SigningMethod
+	signingKey *Key
+	// latestKey is the newest private key, it becomes signing key after publish delay
+	latestKey *Key
+	keys      map[string]*Key
+	stop      chan struct{}
+	opts      Options
+	mu        sync.RWMutex
+}
+
+// NewKeyring creates keyring for configured algorithm. For asymmetric algorithms keys are
+// loaded from keys directory, and a new key is generated if there is no key to sign with.
+func NewKeyring(opts Options) (*Keyring, error) {
+	if opts.Algorithm == "" {
+		opts.Algorithm = AlgorithmHS256
+	}
+
+	method := jwt.GetSigningMethod(opts.Algorithm)
+	if method == nil || (opts.Algorithm != AlgorithmHS256 && opts.Algorithm != AlgorithmRS256 && opts.Algorithm != AlgorithmEdDSA) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, opts.Algorithm)
+	}
+
+	k := &Keyring{
+		method: method,
+		keys:   make(map[string]*Key),
+		stop:   make(chan struct{}),
+		opts:   opts,
+	}
+
+	if k.isSymmetric() {
+		if opts.Secret == "" {
+			return nil, errors.New("secret is required for HS256 algorithm")
+		}
+
+		return k, nil
+	}
+
+	if err := os.MkdirAll(opts.KeysDirectory, 0o700); err != nil {
+		return nil, err
+	}
+
+	if err := k.Load(); err != nil {
+		return nil, err
+	}
+
+	if k.rotationDue() {
+		if err := k.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+// Start periodically reloads keys from disk and rotates signing key when rotation is due.
+func (k *Keyring) Start() {
+	if k.isSymmetric() {
+		return
+	}
+
+	interval := reloadInterval
+	if k.opts.RotationInterval > 0 && k.opts.RotationInterval < interval {
+		interval = k.opts.RotationInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-k.stop:
+				return
+			case <-ticker.C:
+				var err error
+
+				if k.rotationDue() {
+					err = k.Rotate()
+				} else {
+					err = k.Load()
+				}
+
+				if err != nil {
+					logger.Error().Err(err).Msg("failed to refresh signing keys")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops key rotation.
+func (k *Keyring) Stop() {
+	select {
+	case <-k.stop:
+	default:
+		close(k.stop)
+	}
+}
+
+// Algorithm returns algorithm used to sign new tokens.
+func (k *Keyring) Algorithm() string {
+	return k.method.Alg()
+}
+
+// Sign signs claims with the current signing key and sets key id in token header.
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+
+	if k.isSymmetric() {
+		return token.SignedString([]byte(k.opts.Secret))
+	}
+
+	k.mu.RLock()
+	key := k.signingKey
+	k.mu.RUnlock()
+
+	if key == nil {
+		return "", ErrKeyNotFound
+	}
+
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.privateKey)
+}
+
+// Keyfunc returns key used to verify the token. It is meant to be passed to jwt.Parse.
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	// Make sure token's signature wasn't changed
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, ErrUnexpectedMethod
+	}
+
+	if k.isSymmetric() {
+		return []byte(k.opts.Secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	k.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	return key.publicKey, nil
+}
+
+// Load reads keys from keys directory. Newest published private key becomes signing key, and keys
+// replaced longer than verification period ago are ignored.
+func (k *Keyring) Load() error {
+	entries, err := os.ReadDir(k.opts.KeysDirectory)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*Key, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExtension {
+			continue
+		}
+
+		key, err := k.readKey(filepath.Join(k.opts.KeysDirectory, entry.Name()))
+		if err != nil {
+			logger.Warn().Err(err).Msgf("skipping signing key %s", entry.Name())
+			continue
+		}
+
+		if key != nil {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	active := make(map[string]*Key, len(keys))
+
+	var signingKey, latestKey *Key
+
+	for i, key := range keys {
+		// Key is no longer needed once tokens it signed have expired
+		if k.opts.VerificationPeriod > 0 && i < len(keys)-1 &&
+			time.Since(keys[i+1].CreatedAt) > k.opts.VerificationPeriod+PublishDelay {
+			continue
+		}
+
+		active[key.ID] = key
+
+		if key.privateKey == nil {
+			continue
+		}
+
+		latestKey = key
+
+		if signingKey == nil || time.Since(key.CreatedAt) >= PublishDelay {
+			signingKey = key
+		}
+	}
+
+	k.mu.Lock()
+	k.keys = active
+	k.signingKey = signingKey
+	k.latestKey = latestKey
+	k.mu.Unlock()
+
+	return nil
+}
+
+// Rotate generates a new signing key and stores it in keys directory. Previous keys are kept
+// for verification until tokens signed with them expire.
+func (k *Keyring) Rotate() error {
+	var privateKey crypto.PrivateKey
+
+	var err error
+
+	switch k.method.Alg() {
+	case AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, k.method.Alg())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	kid := time.Now().UTC().Format("20060102150405") + "-" + hex.EncodeToString(suffix)
+	path := filepath.Join(k.opts.KeysDirectory, kid+keyFileExtension)
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return err
+	}
+
+	logger.Info().Msgf("generated new signing key %s", kid)
+
+	return k.Load()
+}
+
+// Keys returns all keys used for verification.
+func (k *Keyring) Keys() []*Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys
+}
+
+func (k *Keyring) isSymmetric() bool {
+	return k.method.Alg() == AlgorithmHS256
+}
+
+// rotationDue checks if there is no signing key or the newest one is older than rotation interval.
+func (k *Keyring) rotationDue() bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.latestKey == nil {
+		return true
+	}
+
+	return k.opts.RotationInterval > 0 && time.Since(k.latestKey.CreatedAt) >= k.opts.RotationInterval
+}
+
+// readKey parses PEM encoded private or public key. Nil is returned for keys of other algorithms.
+func (k *Keyring) readKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	key := &Key{
+		ID:        strings.TrimSuffix(filepath.Base(path), keyFileExtension),
+		CreatedAt: info.ModTime(),
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key.privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key.privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key.publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %s", block.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if signer, ok := key.privateKey.(crypto.Signer); ok {
+		key.publicKey = signer.Public()
+	}
+
+	switch key.publicKey.(type) {
+	case *rsa.PublicKey:
+		if k.method.Alg() != AlgorithmRS256 {
+			return nil, nil
+		}
+	case ed25519.PublicKey:
+		if k.method.Alg() != AlgorithmEdDSA {
+			return nil, nil
+		}
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,142 @@
+package signing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringSignAndVerify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		algorithm string
+		keyType   string
+	}{
+		{"hs256", AlgorithmHS256, ""},
+		{"rs256", AlgorithmRS256, "RSA"},
+		{"eddsa", AlgorithmEdDSA, "OKP"},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyring, err := NewKeyring(Options{
+				Algorithm:     tt.algorithm,
+				Secret:        "test",
+				KeysDirectory: t.TempDir(),
+			})
+			require.NoError(t, err)
+
+			signed, err := keyring.Sign(&jwt.StandardClaims{Subject: "user"})
+			require.NoError(t, err)
+
+			claims := &jwt.StandardClaims{}
+			token, err := jwt.ParseWithClaims(signed, claims, keyring.Keyfunc)
+			require.NoError(t, err)
+			require.True(t, token.Valid)
+			require.Equal(t, "user", claims.Subject)
+
+			jwks := keyring.JWKS()
+			if tt.keyType == "" {
+				// Shared secret must never be published
+				require.Empty(t, jwks.Keys)
+				return
+			}
+
+			require.Len(t, jwks.Keys, 1)
+			require.Equal(t, tt.keyType, jwks.Keys[0].KeyType)
+			require.Equal(t, token.Header["kid"], jwks.Keys[0].KeyID)
+			require.Equal(t, tt.algorithm, jwks.Keys[0].Algorithm)
+		})
+	}
+}
+
+func TestKeyringRejectsOtherAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	hmacKeyring, err := NewKeyring(Options{Algorithm: AlgorithmHS256, Secret: "test"})
+	require.NoError(t, err)
+
+	rsaKeyring, err := NewKeyring(Options{Algorithm: AlgorithmRS256, KeysDirectory: t.TempDir()})
+	require.NoError(t, err)
+
+	signed, err := hmacKeyring.Sign(&jwt.StandardClaims{})
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(signed, rsaKeyring.Keyfunc)
+	require.Error(t, err)
+
+	signed, err = rsaKeyring.Sign(&jwt.StandardClaims{})
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(signed, hmacKeyring.Keyfunc)
+	require.Error(t, err)
+
+	_, err = NewKeyring(Options{Algorithm: "none"})
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestKeyringRotate(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	keyring, err := NewKeyring(Options{
+		Algorithm:          AlgorithmEdDSA,
+		KeysDirectory:      directory,
+		RotationInterval:   time.Hour,
+		VerificationPeriod: time.Hour,
+	})
+	require.NoError(t, err)
+
+	oldToken, err := keyring.Sign(&jwt.StandardClaims{})
+	require.NoError(t, err)
+
+	// Make the first key old enough to be replaced
+	oldKeyID := keyring.Keys()[0].ID
+	past := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(directory, oldKeyID+keyFileExtension), past, past))
+	require.NoError(t, keyring.Load())
+	require.True(t, keyring.rotationDue())
+
+	require.NoError(t, keyring.Rotate())
+	require.Len(t, keyring.Keys(), 2)
+	require.False(t, keyring.rotationDue())
+
+	// New key is published, but old key keeps signing until publish delay passes
+	token, err := jwt.Parse(oldToken, keyring.Keyfunc)
+	require.NoError(t, err)
+	require.True(t, token.Valid)
+
+	signed, err := keyring.Sign(&jwt.StandardClaims{})
+	require.NoError(t, err)
+
+	token, err = jwt.Parse(signed, keyring.Keyfunc)
+	require.NoError(t, err)
+	require.Equal(t, oldKeyID, token.Header["kid"])
+
+	// Once tokens signed with the old key expire, the key is no longer used for verification
+	for _, key := range keyring.Keys() {
+		if key.ID == oldKeyID {
+			continue
+		}
+
+		published := time.Now().Add(-PublishDelay - 2*time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(directory, key.ID+keyFileExtension), published, published))
+	}
+
+	require.NoError(t, os.Chtimes(filepath.Join(directory, oldKeyID+keyFileExtension), past.Add(-time.Hour), past.Add(-time.Hour)))
+	require.NoError(t, keyring.Load())
+	require.Len(t, keyring.Keys(), 1)
+
+	_, err = jwt.Parse(oldToken, keyring.Keyfunc)
+	require.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // RFC 6238 default algorithm supported by all authenticator apps
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// secretLength - length of the generated secret in bytes (RFC 4226 recommends 160 bits).
+	secretLength = 20
+	// digits - number of digits in generated code.
+	digits = 6
+	// period - number of seconds each code is valid for.
+	period = 30
+	// skew - number of periods before and after current one which are accepted to handle clock drift.
+	skew = 1
+	// recoveryCodeLength - length of the generated recovery code in bytes.
+	recoveryCodeLength = 5
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret generates new random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+// GenerateCode generates code for the secret at the given time.
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return generateCode(key, timeStep(t)), nil
+}
+
+// Validate checks if code is valid for the secret at the given time. Codes from adjacent
+// periods are accepted as well. Matched time step is returned so callers can reject replays.
+func Validate(secret, code string, t time.Time) (int64, bool) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) != digits {
+		return 0, false
+	}
+
+	current := timeStep(t)
+
+	for i := -skew; i <= skew; i++ {
+		step := current + int64(i)
+
+		if subtle.ConstantTimeCompare([]byte(generateCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// ProvisioningURI builds otpauth URI which authenticator apps use to enroll the secret from QR code.
+func ProvisioningURI(issuer, accountName, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(digits))
+	params.Set("period", fmt.Sprint(period))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: params.Encode(),
+	}
+
+	return uri.String()
+}
+
+// GenerateRecoveryCodes generates n random single use recovery codes in format xxxxx-xxxxx.
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+
+	for range n {
+		code := make([]byte, recoveryCodeLength)
+
+		if _, err := rand.Read(code); err != nil {
+			return nil, err
+		}
+
+		encoded := hex.EncodeToString(code)
+		codes = append(codes, encoded[:recoveryCodeLength]+"-"+encoded[recoveryCodeLength:])
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode removes formatting from the recovery code entered by the user.
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func timeStep(t time.Time) int64 {
+	return t.Unix() / period
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	return encoding.DecodeString(strings.ToUpper(strings.ReplaceAll(secret, " ", "")))
+}
+
+// generateCode implements HOTP (RFC 4226) for the given counter.
+func generateCode(key []byte, counter int64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range digits {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
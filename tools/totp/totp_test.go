@@ -0,0 +1,149 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 secret used by RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateCode(t *testing.T) {
+	t.Parallel()
+
+	// RFC 6238 Appendix B test vectors truncated to 6 digits
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{"time_59", 59, "287082"},
+		{"time_1111111109", 1111111109, "081804"},
+		{"time_1111111111", 1111111111, "050471"},
+		{"time_1234567890", 1234567890, "005924"},
+		{"time_2000000000", 2000000000, "279037"},
+		{"time_20000000000", 20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := GenerateCode(rfcSecret, time.Unix(tt.unix, 0))
+			if err != nil {
+				t.Fatalf("GenerateCode() unexpected error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("GenerateCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1111111109, 0)
+	currentStep := now.Unix() / period
+
+	previous, _ := GenerateCode(rfcSecret, now.Add(-period*time.Second))
+	next, _ := GenerateCode(rfcSecret, now.Add(period*time.Second))
+	expired, _ := GenerateCode(rfcSecret, now.Add(-2*period*time.Second))
+
+	tests := []struct {
+		name      string
+		secret    string
+		code      string
+		wantStep  int64
+		wantValid bool
+	}{
+		{"current_code", rfcSecret, "081804", currentStep, true},
+		{"previous_code", rfcSecret, previous, currentStep - 1, true},
+		{"next_code", rfcSecret, next, currentStep + 1, true},
+		{"expired_code", rfcSecret, expired, 0, false},
+		{"invalid_code", rfcSecret, "000000", 0, false},
+		{"invalid_length", rfcSecret, "81804", 0, false},
+		{"invalid_secret", "not-base32!", "081804", 0, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			step, valid := Validate(tt.secret, tt.code, now)
+			if valid != tt.wantValid || step != tt.wantStep {
+				t.Errorf("Validate() = (%v, %v), want (%v, %v)", step, valid, tt.wantStep, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	t.Parallel()
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() unexpected error = %v", err)
+	}
+
+	code, err := GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("GenerateCode() unexpected error = %v", err)
+	}
+
+	if _, valid := Validate(secret, code, time.Now()); !valid {
+		t.Errorf("Validate() generated code %v is not valid", code)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	t.Parallel()
+
+	uri := ProvisioningURI("Golang Setup", "john@doe.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Golang%20Setup:john@doe.com?") {
+		t.Errorf("ProvisioningURI() unexpected label in %v", uri)
+	}
+
+	for _, param := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Golang+Setup", "digits=6", "period=30"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("ProvisioningURI() = %v, missing %v", uri, param)
+		}
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() unexpected error = %v", err)
+	}
+
+	if len(codes) != 10 {
+		t.Fatalf("GenerateRecoveryCodes() returned %v codes, want 10", len(codes))
+	}
+
+	unique := make(map[string]struct{}, len(codes))
+
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("GenerateRecoveryCodes() unexpected code format %v", code)
+		}
+
+		unique[code] = struct{}{}
+	}
+
+	if len(unique) != len(codes) {
+		t.Errorf("GenerateRecoveryCodes() returned duplicated codes")
+	}
+
+	if got := NormalizeRecoveryCode(" ABCDE-12345 "); got != "abcde12345" {
+		t.Errorf("NormalizeRecoveryCode() = %v, want abcde12345", got)
+	}
+}
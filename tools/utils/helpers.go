@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/twinj/uuid"
 )
@@ -14,3 +15,44 @@ func GenerateUniqueID() string {
 func FormatSessionKey(userID uuid.UUID, sessionID string) string {
 	return fmt.Sprintf("session:%v:%s", userID, sessionID)
 }
+
+// FormatSessionIndexKey - method generates key of the Redis hash which keeps track of all user sessions.
+func FormatSessionIndexKey(userID uuid.UUID) string {
+	return fmt.Sprintf("sessions:%v", userID)
+}
+
+// FormatRateLimitKey - method generates key of the Redis sorted set which keeps requests sent to the
+// route by the subject, like IP or email address, within the sliding window.
+func FormatRateLimitKey(route, subject, value string) string {
+	return fmt.Sprintf("ratelimit:%s:%s:%s", route, subject, value)
+}
+
+// FormatAccountLoginFailuresKey - method generates key of the Redis hash which counts failed logins to the account.
+func FormatAccountLoginFailuresKey(userID uuid.UUID) string {
+	return fmt.Sprintf("login_failures:account:%v", userID)
+}
+
+// FormatIPLoginFailuresKey - method generates key of the Redis hash which counts failed logins from the IP address.
+func FormatIPLoginFailuresKey(ip string) string {
+	return fmt.Sprintf("login_failures:ip:%s", ip)
+}
+
+// FormatAccountIPLoginFailuresKey - method generates key of the Redis hash which counts failed logins to the
+// account from the IP address.
+func FormatAccountIPLoginFailuresKey(userID uuid.UUID, ip string) string {
+	return fmt.Sprintf("login_failures:account_ip:%v:%s", userID, ip)
+}
+
+// FormatThrottleKey - method generates key used to throttle the action performed for the subject.
+func FormatThrottleKey(action, subject string) string {
+	return fmt.Sprintf("throttle:%s:%s", action, subject)
+}
+
+// Truncate shortens the value to at most maxLength characters, so it fits the database column.
+func Truncate(value string, maxLength int) string {
+	if utf8.RuneCountInString(value) <= maxLength {
+		return value
+	}
+
+	return string([]rune(value)[:maxLength])
+}
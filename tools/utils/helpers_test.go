@@ -30,3 +30,28 @@ func TestGenerateUniqueID(t *testing.T) {
 		uniqueIDMap[uniqueID] = true
 	}
 }
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		value     string
+		maxLength int
+		expected  string
+	}{
+		{name: "shorter value", value: "abc", maxLength: 5, expected: "abc"},
+		{name: "longer value", value: "abcdef", maxLength: 4, expected: "abcd"},
+		{name: "multibyte characters", value: "čćžšđ", maxLength: 3, expected: "čćž"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if value := Truncate(tt.value, tt.maxLength); value != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustedClientIP returns IP address of the client which sent the request. Headers set by reverse proxy are
+// only read when the request is sent by one of the trusted proxies, otherwise the client could send a different
+// address with every request. X-Forwarded-For is read from the right, skipping addresses added by the trusted
+// proxies.
+func TrustedClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+		return ip
+	}
+
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(ip) == nil {
+			break
+		}
+
+		if !isTrustedProxy(ip, trustedProxies) {
+			return ip
+		}
+	}
+
+	return host
+}
+
+func isTrustedProxy(address string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
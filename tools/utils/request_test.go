@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedClientIP(t *testing.T) {
+	t.Parallel()
+
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	trustedProxies := []*net.IPNet{proxies}
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		remoteAddr string
+		expected   string
+	}{
+		{
+			name:       "remote address",
+			remoteAddr: "203.0.113.5:51234",
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "headers of untrusted client",
+			headers:    map[string]string{"X-Real-IP": "192.168.1.10", "X-Forwarded-For": "192.168.1.11"},
+			remoteAddr: "203.0.113.5:51234",
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "real ip header of trusted proxy",
+			headers:    map[string]string{"X-Real-IP": "192.168.1.10"},
+			remoteAddr: "10.0.0.1:51234",
+			expected:   "192.168.1.10",
+		},
+		{
+			name:       "last untrusted forwarded address",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.7, 203.0.113.5, 10.0.0.2"},
+			remoteAddr: "10.0.0.1:51234",
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "trusted proxy without headers",
+			remoteAddr: "10.0.0.1:51234",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "invalid forwarded address",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.7, unknown"},
+			remoteAddr: "10.0.0.1:51234",
+			expected:   "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			if ip := TrustedClientIP(r, trustedProxies); ip != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, ip)
+			}
+		})
+	}
+}